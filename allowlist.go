@@ -0,0 +1,42 @@
+package log
+
+// StrictJSONFields indicates if only the keys listed in AllowedJSONFields are written in the JSON outputs
+//
+// The other keys are dropped and their number is written as "extra_redacted_count". The timestamp, status and
+// message of the entries and the name and time of the events are always written.
+var StrictJSONFields = false
+
+// AllowedJSONFields contains the keys which are written in the JSON outputs when StrictJSONFields is enabled
+var AllowedJSONFields = []string{}
+
+func applyAllowList(fields map[string]interface{}, reserved ...string) map[string]interface{} {
+
+	if !StrictJSONFields {
+		return fields
+	}
+
+	allowed := make(map[string]bool, len(AllowedJSONFields)+len(reserved))
+	for _, key := range AllowedJSONFields {
+		allowed[key] = true
+	}
+	for _, key := range reserved {
+		allowed[key] = true
+	}
+
+	filtered := make(map[string]interface{}, len(fields))
+	redacted := 0
+	for key, value := range fields {
+		if allowed[key] {
+			filtered[key] = value
+		} else {
+			redacted++
+		}
+	}
+
+	if redacted > 0 {
+		filtered["extra_redacted_count"] = redacted
+	}
+
+	return filtered
+
+}
@@ -0,0 +1,82 @@
+package log_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func resetAllowListConfig() {
+	log.StrictJSONFields = false
+	log.AllowedJSONFields = []string{}
+	log.Format = log.FormatText
+	log.DatadogService = ""
+	log.DatadogEnv = ""
+}
+
+func Test_StrictJSONFields_Datadog(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+	defer resetAllowListConfig()
+
+	log.Format = log.FormatDatadog
+	log.DatadogService = "api"
+	log.DatadogEnv = "prod"
+	log.StrictJSONFields = true
+	log.AllowedJSONFields = []string{"service"}
+
+	log.Info("message")
+
+	var actual map[string]interface{}
+	err := json.Unmarshal(stdout.Bytes(), &actual)
+
+	assert.NoError(t, err, "json")
+	assert.Equal(t, "message", actual["message"], "message")
+	assert.Equal(t, "info", actual["status"], "status")
+	assert.Equal(t, "api", actual["service"], "service")
+	assert.NotContains(t, actual, "env", "env")
+	assert.Equal(t, 1.0, actual["extra_redacted_count"], "extra_redacted_count")
+
+}
+
+func Test_StrictJSONFields_Event(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+	defer resetAllowListConfig()
+
+	log.StrictJSONFields = true
+	log.AllowedJSONFields = []string{"plan"}
+
+	log.Event("user_signup", map[string]interface{}{"plan": "pro", "email": "john@example.com", "ip": "127.0.0.1"})
+
+	var actual map[string]interface{}
+	err := json.Unmarshal(stdout.Bytes(), &actual)
+
+	assert.NoError(t, err, "json")
+	assert.Equal(t, "user_signup", actual["event"], "event")
+	assert.Equal(t, map[string]interface{}{"plan": "pro", "extra_redacted_count": 2.0}, actual["properties"], "properties")
+
+}
+
+func Test_StrictJSONFields_Disabled(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+	defer resetAllowListConfig()
+
+	log.AllowedJSONFields = []string{"plan"}
+
+	log.Event("user_signup", map[string]interface{}{"plan": "pro", "email": "john@example.com"})
+
+	assert.Contains(t, stdout.String(), `"email":"john@example.com"`)
+	assert.NotContains(t, stdout.String(), "extra_redacted_count")
+
+}
@@ -0,0 +1,125 @@
+package log
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ArchiveStore stores the rotated files uploaded by a RotatingFileSink, such as an object storage bucket (see S3Store,
+// which also supports Google Cloud Storage, and AzureStore)
+type ArchiveStore interface {
+	// Put stores the size bytes read from r under name, replacing an existing archive with the same name
+	Put(name string, r io.Reader, size int64) error
+
+	// List returns the names of the stored archives which start with prefix
+	List(prefix string) ([]string, error)
+
+	// Delete removes the archive with the given name
+	Delete(name string) error
+}
+
+// ArchiveOptions are the options used to upload the rotated files of a RotatingFileSink
+type ArchiveOptions struct {
+	// Store is the store the rotated files are uploaded to, the rotated files which are left from a previous process
+	// (e.g. because uploading them failed) are uploaded when the file is opened
+	Store ArchiveStore
+
+	// Prefix is prepended to the file names to get the names of the archives (e.g. "logs/app/")
+	Prefix string
+
+	// MaxAge is the age after which uploaded archives are removed from the store (0 keeps them regardless of their
+	// age)
+	MaxAge time.Duration
+}
+
+// uploadBackups uploads the rotated files and removes the ones which were uploaded, it must be called while holding
+// cleanupMutex
+func (s *RotatingFileSink) uploadBackups(paths []string) {
+
+	opts := s.opts.Archive
+
+	pending := []string{}
+	for _, path := range paths {
+		if err := uploadFile(opts.Store, opts.Prefix+filepath.Base(path), path); err != nil {
+			if fileExists(path) {
+				pending = append(pending, path)
+			}
+			continue
+		}
+		os.Remove(path)
+	}
+	s.pendingUploads = pending
+
+	if opts.MaxAge > 0 {
+		s.pruneArchives()
+	}
+
+}
+
+// uploadLeftovers compresses, encrypts and uploads the rotated files which are still next to the file when it's opened
+// in the background, such as the ones which failed to upload before the previous process exited
+func (s *RotatingFileSink) uploadLeftovers() {
+
+	if s.opts.Archive == nil || s.opts.Archive.Store == nil {
+		return
+	}
+
+	paths := []string{}
+	for _, b := range s.backups() {
+		paths = append(paths, b.path)
+	}
+	if len(paths) == 0 {
+		return
+	}
+
+	s.cleanup.Add(1)
+	go func() {
+		defer s.cleanup.Done()
+		s.cleanupMutex.Lock()
+		defer s.cleanupMutex.Unlock()
+		for i, path := range paths {
+			paths[i] = s.prepareBackup(path)
+		}
+		s.uploadBackups(paths)
+	}()
+
+}
+
+// pruneArchives removes the archives which are older than the MaxAge of the archive options
+func (s *RotatingFileSink) pruneArchives() {
+
+	opts := s.opts.Archive
+
+	names, err := opts.Store.List(opts.Prefix)
+	if err != nil {
+		return
+	}
+
+	for _, name := range names {
+		t, ok := s.backupTime(strings.TrimPrefix(name, opts.Prefix))
+		if ok && time.Since(t) > opts.MaxAge {
+			opts.Store.Delete(name)
+		}
+	}
+
+}
+
+func uploadFile(store ArchiveStore, name string, path string) error {
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	return store.Put(name, f, info.Size())
+
+}
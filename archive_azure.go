@@ -0,0 +1,252 @@
+//go:build !tinygo
+// +build !tinygo
+
+package log
+
+import (
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// azureVersion is the version of the Azure Storage REST API used by AzureStore
+const azureVersion = "2020-10-02"
+
+// AzureStore is an ArchiveStore which stores the archives as block blobs in a container of an Azure Blob Storage
+// account
+//
+// The requests are authorized with the account key (Shared Key) or, when SASToken is set, with a shared access
+// signature.
+type AzureStore struct {
+	// Endpoint is the URL of the blob service (defaults to https://<account>.blob.core.windows.net)
+	Endpoint string
+
+	// Account is the name of the storage account
+	Account string
+
+	// Container is the name of the container
+	Container string
+
+	// AccountKey is the base64 encoded key of the storage account used to sign the requests
+	AccountKey string
+
+	// SASToken is a shared access signature (e.g. "sv=...&sig=...") which is used instead of AccountKey
+	SASToken string
+
+	// Client is used to send the requests (defaults to http.DefaultClient)
+	Client *http.Client
+}
+
+// Put uploads the archive to the container
+func (s *AzureStore) Put(name string, r io.Reader, size int64) error {
+
+	req, err := s.request(http.MethodPut, name, nil, r)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	if size == 0 {
+		req.Body = http.NoBody
+	}
+	req.Header.Set("X-Ms-Blob-Type", "BlockBlob")
+
+	resp, err := s.do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	return nil
+
+}
+
+// List returns the names of the archives in the container which start with prefix
+func (s *AzureStore) List(prefix string) ([]string, error) {
+
+	names := []string{}
+	marker := ""
+
+	for {
+
+		query := url.Values{"restype": {"container"}, "comp": {"list"}, "prefix": {prefix}}
+		if marker != "" {
+			query.Set("marker", marker)
+		}
+
+		req, err := s.request(http.MethodGet, "", query, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := s.do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		var result struct {
+			Blobs struct {
+				Blob []struct {
+					Name string
+				}
+			}
+			NextMarker string
+		}
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, blob := range result.Blobs.Blob {
+			names = append(names, blob.Name)
+		}
+
+		if result.NextMarker == "" {
+			return names, nil
+		}
+		marker = result.NextMarker
+
+	}
+
+}
+
+// Delete removes the archive from the container
+func (s *AzureStore) Delete(name string) error {
+
+	req, err := s.request(http.MethodDelete, name, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	return nil
+
+}
+
+func (s *AzureStore) request(method string, name string, query url.Values, body io.Reader) (*http.Request, error) {
+
+	endpoint := s.Endpoint
+	if endpoint == "" {
+		endpoint = "https://" + s.Account + ".blob.core.windows.net"
+	}
+
+	path := "/" + s.Container
+	if name != "" {
+		path += "/" + name
+	}
+
+	rawURL := strings.TrimSuffix(endpoint, "/") + s3Escape(path, false)
+	rawQuery := query.Encode()
+	if sas := strings.TrimPrefix(s.SASToken, "?"); sas != "" {
+		if rawQuery != "" {
+			rawQuery += "&"
+		}
+		rawQuery += sas
+	}
+	if rawQuery != "" {
+		rawURL += "?" + rawQuery
+	}
+
+	return http.NewRequest(method, rawURL, body)
+
+}
+
+// do signs and sends the request and returns an error for unsuccessful responses
+func (s *AzureStore) do(req *http.Request) (*http.Response, error) {
+
+	req.Header.Set("X-Ms-Date", time.Now().UTC().Format(http.TimeFormat))
+	req.Header.Set("X-Ms-Version", azureVersion)
+	if s.SASToken == "" {
+		if err := s.sign(req); err != nil {
+			return nil, err
+		}
+	}
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		resp.Body.Close()
+		return nil, fmt.Errorf("azure: %s %s: %s", req.Method, req.URL.Path, resp.Status)
+	}
+
+	return resp, nil
+
+}
+
+// sign adds the Shared Key authorization of the request
+func (s *AzureStore) sign(req *http.Request) error {
+
+	key, err := base64.StdEncoding.DecodeString(s.AccountKey)
+	if err != nil {
+		return fmt.Errorf("azure: invalid account key: %v", err)
+	}
+
+	contentLength := ""
+	if req.ContentLength > 0 {
+		contentLength = strconv.FormatInt(req.ContentLength, 10)
+	}
+
+	headers := []string{}
+	for key, values := range req.Header {
+		if key = strings.ToLower(key); strings.HasPrefix(key, "x-ms-") {
+			headers = append(headers, key+":"+strings.TrimSpace(strings.Join(values, ",")))
+		}
+	}
+	sort.Strings(headers)
+
+	resource := "/" + s.Account + req.URL.EscapedPath()
+	query := req.URL.Query()
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		values := query[key]
+		sort.Strings(values)
+		resource += "\n" + strings.ToLower(key) + ":" + strings.Join(values, ",")
+	}
+
+	stringToSign := strings.Join([]string{
+		req.Method,
+		req.Header.Get("Content-Encoding"),
+		req.Header.Get("Content-Language"),
+		contentLength,
+		req.Header.Get("Content-Md5"),
+		req.Header.Get("Content-Type"),
+		"", // Date, x-ms-date is used instead
+		req.Header.Get("If-Modified-Since"),
+		req.Header.Get("If-Match"),
+		req.Header.Get("If-None-Match"),
+		req.Header.Get("If-Unmodified-Since"),
+		req.Header.Get("Range"),
+		strings.Join(headers, "\n"),
+		resource,
+	}, "\n")
+
+	signature := base64.StdEncoding.EncodeToString(hmacSHA256(key, stringToSign))
+	req.Header.Set("Authorization", "SharedKey "+s.Account+":"+signature)
+
+	return nil
+
+}
@@ -0,0 +1,47 @@
+//go:build !tinygo
+// +build !tinygo
+
+package log
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_AzureStore_sign(t *testing.T) {
+
+	s := &AzureStore{Account: "account", Container: "container", AccountKey: "c2VjcmV0"}
+
+	var tests = []struct {
+		name          string
+		method        string
+		path          string
+		body          string
+		headers       map[string]string
+		authorization string
+	}{
+		{"put", http.MethodPut, "/container/logs/app%201.log", "first", map[string]string{"X-Ms-Blob-Type": "BlockBlob"}, "SharedKey account:/EvJgKmTK85A8IoWqBeLIrkhWbAcQvwEXWzdrLJxzw4="},
+		{"list", http.MethodGet, "/container?restype=container&comp=list&prefix=logs%2F", "", nil, "SharedKey account:6Z2kWuf3pFxjFC9HWfo+ODtju39YEPkcabXjPTtTcZQ="},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			req, _ := http.NewRequest(tc.method, "https://account.blob.core.windows.net"+tc.path, strings.NewReader(tc.body))
+			req.ContentLength = int64(len(tc.body))
+			req.Header.Set("X-Ms-Date", "Mon, 02 Jan 2006 15:04:05 GMT")
+			req.Header.Set("X-Ms-Version", azureVersion)
+			for key, value := range tc.headers {
+				req.Header.Set(key, value)
+			}
+
+			assert.NoError(t, s.sign(req), "sign")
+			assert.Equal(t, tc.authorization, req.Header.Get("Authorization"), "authorization")
+
+		})
+	}
+
+}
@@ -0,0 +1,140 @@
+//go:build !tinygo
+// +build !tinygo
+
+package log_test
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+// fakeAzure is a minimal blob container named "container" which returns a single blob per page when listing
+type fakeAzure struct {
+	mutex sync.Mutex
+	blobs map[string]string
+}
+
+func (s *fakeAzure) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	authorized := strings.HasPrefix(r.Header.Get("Authorization"), "SharedKey account:") || r.URL.Query().Get("sig") == "token"
+	if !authorized || r.Header.Get("X-Ms-Date") == "" || r.Header.Get("X-Ms-Version") == "" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	if !strings.HasPrefix(r.URL.Path, "/container") {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	name := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/container"), "/")
+
+	switch r.Method {
+	case http.MethodPut:
+		if r.Header.Get("X-Ms-Blob-Type") != "BlockBlob" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		data, _ := ioutil.ReadAll(r.Body)
+		s.blobs[name] = string(data)
+		w.WriteHeader(http.StatusCreated)
+	case http.MethodDelete:
+		delete(s.blobs, name)
+		w.WriteHeader(http.StatusAccepted)
+	case http.MethodGet:
+		names := []string{}
+		for n := range s.blobs {
+			if strings.HasPrefix(n, r.URL.Query().Get("prefix")) && n > r.URL.Query().Get("marker") {
+				names = append(names, n)
+			}
+		}
+		sort.Strings(names)
+
+		type blob struct {
+			Name string
+		}
+		result := struct {
+			XMLName xml.Name `xml:"EnumerationResults"`
+			Blobs   struct {
+				Blob []blob
+			}
+			NextMarker string
+		}{}
+		if len(names) > 0 {
+			result.Blobs.Blob = []blob{{Name: names[0]}}
+		}
+		if len(names) > 1 {
+			result.NextMarker = names[0]
+		}
+		xml.NewEncoder(w).Encode(result)
+	}
+
+}
+
+func Test_AzureStore(t *testing.T) {
+
+	container := &fakeAzure{blobs: map[string]string{}}
+	server := httptest.NewServer(container)
+	defer server.Close()
+
+	store := &log.AzureStore{
+		Endpoint:   server.URL,
+		Account:    "account",
+		Container:  "container",
+		AccountKey: "c2VjcmV0",
+	}
+
+	assert.NoError(t, store.Put("logs/app-1.log.gz", strings.NewReader("first"), 5), "put")
+	assert.NoError(t, store.Put("logs/app-2.log.gz", strings.NewReader("second"), 6), "put-again")
+	assert.NoError(t, store.Put("other/app-3.log.gz", strings.NewReader(""), 0), "put-empty")
+
+	assert.Equal(t, "first", container.blobs["logs/app-1.log.gz"], "contents")
+	assert.Equal(t, "", container.blobs["other/app-3.log.gz"], "empty")
+
+	names, err := store.List("logs/")
+	assert.NoError(t, err, "list")
+	assert.Equal(t, []string{"logs/app-1.log.gz", "logs/app-2.log.gz"}, names, "names")
+
+	assert.NoError(t, store.Delete("logs/app-1.log.gz"), "delete")
+	assert.NotContains(t, container.blobs, "logs/app-1.log.gz", "deleted")
+
+	store.Container = "missing"
+	assert.EqualError(t, store.Put("app.log", strings.NewReader(""), 0), "azure: PUT /missing/app.log: 404 Not Found", "error")
+
+	store.AccountKey = "not base64"
+	assert.Error(t, store.Delete("app.log"), "invalid-key")
+
+}
+
+func Test_AzureStore_SASToken(t *testing.T) {
+
+	container := &fakeAzure{blobs: map[string]string{}}
+	server := httptest.NewServer(container)
+	defer server.Close()
+
+	store := &log.AzureStore{
+		Endpoint:  server.URL,
+		Account:   "account",
+		Container: "container",
+		SASToken:  "?sv=2020-10-02&sig=token",
+	}
+
+	assert.NoError(t, store.Put("app-1.log", strings.NewReader("first"), 5), "put")
+
+	names, err := store.List("app-")
+	assert.NoError(t, err, "list")
+	assert.Equal(t, []string{"app-1.log"}, names, "names")
+
+}
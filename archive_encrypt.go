@@ -0,0 +1,195 @@
+package log
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+)
+
+// archiveMagic is the header of the encrypted archives
+var archiveMagic = []byte("GOLOGENC1")
+
+// archiveChunkSize is the size of the plain text chunks which are encrypted separately, so that archives of any size
+// can be encrypted without reading them in memory
+const archiveChunkSize = 64 * 1024
+
+// ErrInvalidArchive is returned when decrypting an archive which is corrupt, truncated or encrypted with another key
+var ErrInvalidArchive = errors.New("invalid encrypted archive")
+
+// EncryptArchive encrypts src to dst with AES-GCM using the key (16, 24 or 32 bytes for AES-128, AES-192 or AES-256)
+//
+// The contents are encrypted in chunks, each authenticated together with its position and whether it's the last one,
+// so that reordered, removed or truncated chunks are detected by DecryptArchive.
+func EncryptArchive(dst io.Writer, src io.Reader, key []byte) error {
+
+	gcm, err := archiveCipher(key)
+	if err != nil {
+		return err
+	}
+
+	prefix := make([]byte, gcm.NonceSize()-4)
+	if _, err := io.ReadFull(rand.Reader, prefix); err != nil {
+		return err
+	}
+
+	if _, err := dst.Write(append(append([]byte{}, archiveMagic...), prefix...)); err != nil {
+		return err
+	}
+
+	buf := make([]byte, archiveChunkSize)
+	next := make([]byte, archiveChunkSize)
+
+	n, err := io.ReadFull(src, buf)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return err
+	}
+
+	for counter := uint32(0); ; counter++ {
+
+		// Reading ahead tells whether this chunk is the last one
+		m, nextErr := 0, error(nil)
+		if n == archiveChunkSize {
+			m, nextErr = io.ReadFull(src, next)
+			if nextErr != nil && nextErr != io.EOF && nextErr != io.ErrUnexpectedEOF {
+				return nextErr
+			}
+		}
+		last := m == 0
+
+		sealed := gcm.Seal(nil, archiveNonce(prefix, counter), buf[:n], archiveAdditionalData(last))
+
+		var length [4]byte
+		binary.BigEndian.PutUint32(length[:], uint32(len(sealed)))
+		if _, err := dst.Write(append(length[:], sealed...)); err != nil {
+			return err
+		}
+
+		if last {
+			return nil
+		}
+
+		buf, next = next, buf
+		n = m
+
+	}
+
+}
+
+// DecryptArchive decrypts an archive encrypted with EncryptArchive from src to dst
+func DecryptArchive(dst io.Writer, src io.Reader, key []byte) error {
+
+	gcm, err := archiveCipher(key)
+	if err != nil {
+		return err
+	}
+
+	header := make([]byte, len(archiveMagic)+gcm.NonceSize()-4)
+	if _, err := io.ReadFull(src, header); err != nil || !bytes.Equal(header[:len(archiveMagic)], archiveMagic) {
+		return ErrInvalidArchive
+	}
+	prefix := header[len(archiveMagic):]
+
+	maxSealed := uint32(archiveChunkSize + gcm.Overhead())
+
+	for counter := uint32(0); ; counter++ {
+
+		var length [4]byte
+		if _, err := io.ReadFull(src, length[:]); err != nil {
+			return ErrInvalidArchive
+		}
+
+		size := binary.BigEndian.Uint32(length[:])
+		if size > maxSealed {
+			return ErrInvalidArchive
+		}
+
+		sealed := make([]byte, size)
+		if _, err := io.ReadFull(src, sealed); err != nil {
+			return ErrInvalidArchive
+		}
+
+		nonce := archiveNonce(prefix, counter)
+		plain, err := gcm.Open(nil, nonce, sealed, archiveAdditionalData(false))
+		last := false
+		if err != nil {
+			if plain, err = gcm.Open(nil, nonce, sealed, archiveAdditionalData(true)); err != nil {
+				return ErrInvalidArchive
+			}
+			last = true
+		}
+
+		if _, err := dst.Write(plain); err != nil {
+			return err
+		}
+
+		if last {
+			if n, _ := src.Read(make([]byte, 1)); n > 0 {
+				return ErrInvalidArchive
+			}
+			return nil
+		}
+
+	}
+
+}
+
+func archiveCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func archiveNonce(prefix []byte, counter uint32) []byte {
+	nonce := make([]byte, len(prefix)+4)
+	copy(nonce, prefix)
+	binary.BigEndian.PutUint32(nonce[len(prefix):], counter)
+	return nonce
+}
+
+func archiveAdditionalData(last bool) []byte {
+	if last {
+		return []byte{1}
+	}
+	return []byte{0}
+}
+
+// encryptFile replaces the file at path with its encrypted version at path.enc
+func encryptFile(path string, key []byte) error {
+
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+
+	dst, err := os.OpenFile(path+".enc", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+
+	if err := EncryptArchive(dst, src, key); err != nil {
+		dst.Close()
+		os.Remove(path + ".enc")
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(path + ".enc")
+		return err
+	}
+
+	src.Close()
+	return os.Remove(path)
+
+}
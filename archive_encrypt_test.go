@@ -0,0 +1,77 @@
+package log_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func Test_EncryptArchive(t *testing.T) {
+
+	key := bytes.Repeat([]byte{1}, 32)
+
+	var tests = []struct {
+		name string
+		size int
+	}{
+		{"empty", 0},
+		{"small", 10},
+		{"one-chunk", 64 * 1024},
+		{"multiple-chunks", 200 * 1024},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			plain := bytes.Repeat([]byte("x"), tc.size)
+
+			var encrypted bytes.Buffer
+			assert.NoError(t, log.EncryptArchive(&encrypted, bytes.NewReader(plain), key), "encrypt")
+			assert.False(t, tc.size > 0 && bytes.Contains(encrypted.Bytes(), plain[:tc.size/2]), "encrypted")
+
+			var decrypted bytes.Buffer
+			assert.NoError(t, log.DecryptArchive(&decrypted, bytes.NewReader(encrypted.Bytes()), key), "decrypt")
+			assert.Equal(t, string(plain), decrypted.String(), "decrypted")
+
+		})
+	}
+
+}
+
+func Test_DecryptArchive_Invalid(t *testing.T) {
+
+	key := bytes.Repeat([]byte{1}, 32)
+
+	var encrypted bytes.Buffer
+	log.EncryptArchive(&encrypted, bytes.NewReader(bytes.Repeat([]byte("x"), 100*1024)), key)
+	data := encrypted.Bytes()
+
+	modified := append([]byte{}, data...)
+	modified[len(modified)-1] ^= 1
+
+	var tests = []struct {
+		name string
+		data []byte
+		key  []byte
+	}{
+		{"wrong-key", data, bytes.Repeat([]byte{2}, 32)},
+		{"modified", modified, key},
+		{"truncated", data[:64*1024], key},
+		{"last-chunk-removed", data[:len(data)-(100-64)*1024-4-16], key},
+		{"trailing-data", append(append([]byte{}, data...), 'x'), key},
+		{"not-encrypted", []byte("plain text"), key},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var decrypted bytes.Buffer
+			assert.Equal(t, log.ErrInvalidArchive, log.DecryptArchive(&decrypted, bytes.NewReader(tc.data), tc.key), "error")
+		})
+	}
+
+	assert.Error(t, log.EncryptArchive(&bytes.Buffer{}, bytes.NewReader(nil), []byte("short")), "invalid-key")
+
+}
@@ -0,0 +1,273 @@
+//go:build !tinygo
+// +build !tinygo
+
+package log
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// emptyPayloadHash is the SHA-256 of an empty request body
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// S3Store is an ArchiveStore which stores the archives in a bucket of an S3 compatible object storage, such as
+// Amazon S3, Google Cloud Storage (using HMAC keys and https://storage.googleapis.com) or MinIO
+//
+// The requests are signed with AWS Signature Version 4 and use path-style URLs.
+type S3Store struct {
+	// Endpoint is the URL of the storage (defaults to https://s3.<region>.amazonaws.com)
+	Endpoint string
+
+	// Region is the region of the bucket (e.g. "eu-west-1", or "auto" for Google Cloud Storage)
+	Region string
+
+	// Bucket is the name of the bucket
+	Bucket string
+
+	// AccessKeyID, SecretAccessKey and SessionToken (optional) are the credentials used to sign the requests
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+
+	// Client is used to send the requests (defaults to http.DefaultClient)
+	Client *http.Client
+}
+
+// Put uploads the archive to the bucket
+func (s *S3Store) Put(name string, r io.Reader, size int64) error {
+
+	req, err := s.request(http.MethodPut, name, nil, r)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	if size == 0 {
+		req.Body = http.NoBody
+	}
+
+	resp, err := s.do(req, "UNSIGNED-PAYLOAD")
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	return nil
+
+}
+
+// List returns the names of the archives in the bucket which start with prefix
+func (s *S3Store) List(prefix string) ([]string, error) {
+
+	names := []string{}
+	token := ""
+
+	for {
+
+		query := url.Values{"list-type": {"2"}, "prefix": {prefix}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+
+		req, err := s.request(http.MethodGet, "", query, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := s.do(req, emptyPayloadHash)
+		if err != nil {
+			return nil, err
+		}
+
+		var result struct {
+			Contents []struct {
+				Key string
+			}
+			IsTruncated           bool
+			NextContinuationToken string
+		}
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, object := range result.Contents {
+			names = append(names, object.Key)
+		}
+
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return names, nil
+		}
+		token = result.NextContinuationToken
+
+	}
+
+}
+
+// Delete removes the archive from the bucket
+func (s *S3Store) Delete(name string) error {
+
+	req, err := s.request(http.MethodDelete, name, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.do(req, emptyPayloadHash)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	return nil
+
+}
+
+func (s *S3Store) request(method string, name string, query url.Values, body io.Reader) (*http.Request, error) {
+
+	endpoint := s.Endpoint
+	if endpoint == "" {
+		endpoint = "https://s3." + s.Region + ".amazonaws.com"
+	}
+
+	path := "/" + s.Bucket
+	if name != "" {
+		path += "/" + name
+	}
+
+	rawURL := strings.TrimSuffix(endpoint, "/") + s3Escape(path, false)
+	if len(query) > 0 {
+		rawURL += "?" + s3Query(query)
+	}
+
+	return http.NewRequest(method, rawURL, body)
+
+}
+
+// do signs and sends the request and returns an error for unsuccessful responses
+func (s *S3Store) do(req *http.Request, payloadHash string) (*http.Response, error) {
+
+	s.sign(req, payloadHash, time.Now().UTC())
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		resp.Body.Close()
+		return nil, fmt.Errorf("s3: %s %s: %s", req.Method, req.URL.Path, resp.Status)
+	}
+
+	return resp, nil
+
+}
+
+// sign adds the AWS Signature Version 4 authorization of the request, signing the host and all its headers
+func (s *S3Store) sign(req *http.Request, payloadHash string, t time.Time) {
+
+	date := t.Format("20060102")
+	timestamp := t.Format("20060102T150405Z")
+
+	req.Header.Set("X-Amz-Date", timestamp)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if s.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.SessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for key, values := range req.Header {
+		headers[strings.ToLower(key)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+
+	keys := make([]string, 0, len(headers))
+	for key := range headers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var canonicalHeaders strings.Builder
+	for _, key := range keys {
+		canonicalHeaders.WriteString(key + ":" + headers[key] + "\n")
+	}
+	signedHeaders := strings.Join(keys, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + s.Region + "/s3/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + timestamp + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := []byte("AWS4" + s.SecretAccessKey)
+	for _, part := range []string{date, s.Region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+s.AccessKeyID+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// s3Query returns the query sorted by key and escaped as required by the signature
+func s3Query(query url.Values) string {
+
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := []string{}
+	for _, key := range keys {
+		for _, value := range query[key] {
+			pairs = append(pairs, s3Escape(key, true)+"="+s3Escape(value, true))
+		}
+	}
+
+	return strings.Join(pairs, "&")
+
+}
+
+// s3Escape escapes all characters except the unreserved ones (and slashes unless escapeSlash is true)
+func s3Escape(s string, escapeSlash bool) string {
+	var b strings.Builder
+	for _, c := range []byte(s) {
+		switch {
+		case c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z', c >= '0' && c <= '9', c == '-', c == '.', c == '_', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !escapeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
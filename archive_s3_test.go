@@ -0,0 +1,109 @@
+//go:build !tinygo
+// +build !tinygo
+
+package log_test
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+// fakeS3 is a minimal S3 bucket named "bucket" which returns a single key per page when listing
+type fakeS3 struct {
+	mutex   sync.Mutex
+	objects map[string]string
+}
+
+func (s *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=key/") || r.Header.Get("X-Amz-Date") == "" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	if !strings.HasPrefix(r.URL.Path, "/bucket") {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	key := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/bucket"), "/")
+
+	switch r.Method {
+	case http.MethodPut:
+		data, _ := ioutil.ReadAll(r.Body)
+		s.objects[key] = string(data)
+	case http.MethodDelete:
+		delete(s.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodGet:
+		keys := []string{}
+		for k := range s.objects {
+			if strings.HasPrefix(k, r.URL.Query().Get("prefix")) && k > r.URL.Query().Get("continuation-token") {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+
+		type content struct {
+			Key string
+		}
+		result := struct {
+			XMLName               xml.Name `xml:"ListBucketResult"`
+			Contents              []content
+			IsTruncated           bool
+			NextContinuationToken string
+		}{}
+		if len(keys) > 0 {
+			result.Contents = []content{{Key: keys[0]}}
+			result.IsTruncated = len(keys) > 1
+			result.NextContinuationToken = keys[0]
+		}
+		xml.NewEncoder(w).Encode(result)
+	}
+
+}
+
+func Test_S3Store(t *testing.T) {
+
+	bucket := &fakeS3{objects: map[string]string{}}
+	server := httptest.NewServer(bucket)
+	defer server.Close()
+
+	store := &log.S3Store{
+		Endpoint:        server.URL,
+		Region:          "us-east-1",
+		Bucket:          "bucket",
+		AccessKeyID:     "key",
+		SecretAccessKey: "secret",
+	}
+
+	assert.NoError(t, store.Put("logs/app-1.log.gz", strings.NewReader("first"), 5), "put")
+	assert.NoError(t, store.Put("logs/app-2.log.gz", strings.NewReader("second"), 6), "put-again")
+	assert.NoError(t, store.Put("other/app-3.log.gz", strings.NewReader(""), 0), "put-empty")
+
+	assert.Equal(t, "first", bucket.objects["logs/app-1.log.gz"], "contents")
+	assert.Equal(t, "", bucket.objects["other/app-3.log.gz"], "empty")
+
+	names, err := store.List("logs/")
+	assert.NoError(t, err, "list")
+	assert.Equal(t, []string{"logs/app-1.log.gz", "logs/app-2.log.gz"}, names, "names")
+
+	assert.NoError(t, store.Delete("logs/app-1.log.gz"), "delete")
+	assert.NotContains(t, bucket.objects, "logs/app-1.log.gz", "deleted")
+
+	store.Bucket = "missing"
+	assert.EqualError(t, store.Put("app.log", strings.NewReader(""), 0), "s3: PUT /missing/app.log: 404 Not Found", "error")
+
+}
@@ -0,0 +1,258 @@
+package log_test
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+// memoryStore is an ArchiveStore keeping the archives in memory
+type memoryStore struct {
+	mutex    sync.Mutex
+	archives map[string]string
+	failing  bool
+	attempts int
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{archives: map[string]string{}}
+}
+
+func (s *memoryStore) Put(name string, r io.Reader, size int64) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.attempts++
+	if s.failing {
+		return errors.New("upload failed")
+	}
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if int64(len(data)) != size {
+		return errors.New("size mismatch")
+	}
+	s.archives[name] = string(data)
+	return nil
+}
+
+func (s *memoryStore) List(prefix string) ([]string, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	names := []string{}
+	for name := range s.archives {
+		if strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (s *memoryStore) Delete(name string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.archives, name)
+	return nil
+}
+
+func (s *memoryStore) putAttempts() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.attempts
+}
+
+func (s *memoryStore) setFailing(failing bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.failing = failing
+}
+
+func Test_RotatingFileSink_Archive(t *testing.T) {
+
+	dir, _ := ioutil.TempDir("", "go-log")
+	defer os.RemoveAll(dir)
+
+	store := newMemoryStore()
+	sink, err := log.OpenRotatingFileSink(filepath.Join(dir, "app.log"), log.RotationOptions{
+		Archive: &log.ArchiveOptions{Store: store, Prefix: "logs/"},
+	})
+	assert.NoError(t, err, "open")
+
+	sink.Write([]byte("entry\n"))
+	assert.NoError(t, sink.Rotate(), "rotate")
+	assert.NoError(t, sink.Close(), "close")
+
+	assert.Empty(t, rotatedFiles(t, dir), "local-removed")
+
+	names, _ := store.List("")
+	if assert.Len(t, names, 1, "uploaded") {
+		assert.True(t, strings.HasPrefix(names[0], "logs/app-"), "prefix")
+		assert.True(t, strings.HasSuffix(names[0], ".log"), "suffix")
+		assert.Equal(t, "entry\n", store.archives[names[0]], "contents")
+	}
+
+}
+
+func Test_RotatingFileSink_Archive_Retry(t *testing.T) {
+
+	dir, _ := ioutil.TempDir("", "go-log")
+	defer os.RemoveAll(dir)
+
+	store := newMemoryStore()
+	store.setFailing(true)
+
+	sink, err := log.OpenRotatingFileSink(filepath.Join(dir, "app.log"), log.RotationOptions{
+		Compress: true,
+		Archive:  &log.ArchiveOptions{Store: store},
+	})
+	assert.NoError(t, err, "open")
+	defer sink.Close()
+
+	sink.Write([]byte("first\n"))
+	assert.NoError(t, sink.Rotate(), "rotate")
+	assert.Eventually(t, func() bool {
+		return store.putAttempts() == 1
+	}, time.Second, 10*time.Millisecond, "attempted")
+
+	files := rotatedFiles(t, dir)
+	if assert.Len(t, files, 1, "kept") {
+		assert.True(t, strings.HasSuffix(files[0], ".log.gz"), "compressed")
+	}
+
+	store.setFailing(false)
+
+	sink.Write([]byte("second\n"))
+	assert.NoError(t, sink.Rotate(), "rotate-again")
+	assert.NoError(t, sink.Close(), "close")
+
+	assert.Empty(t, rotatedFiles(t, dir), "local-removed")
+
+	names, _ := store.List("app-")
+	assert.Len(t, names, 2, "uploaded")
+
+}
+
+func Test_RotatingFileSink_Archive_MaxAge(t *testing.T) {
+
+	dir, _ := ioutil.TempDir("", "go-log")
+	defer os.RemoveAll(dir)
+
+	store := newMemoryStore()
+	old := "app-" + time.Now().UTC().Add(-48*time.Hour).Format("2006-01-02T15-04-05.000") + ".log.gz"
+	store.Put(old, strings.NewReader(""), 0)
+	store.Put("other.log", strings.NewReader(""), 0)
+
+	sink, err := log.OpenRotatingFileSink(filepath.Join(dir, "app.log"), log.RotationOptions{
+		Archive: &log.ArchiveOptions{Store: store, MaxAge: 24 * time.Hour},
+	})
+	assert.NoError(t, err, "open")
+
+	sink.Write([]byte("entry\n"))
+	assert.NoError(t, sink.Rotate(), "rotate")
+	assert.NoError(t, sink.Close(), "close")
+
+	names, _ := store.List("")
+	assert.Len(t, names, 2, "archives")
+	assert.NotContains(t, names, old, "expired")
+	assert.Contains(t, names, "other.log", "unrelated")
+
+}
+
+func Test_RotatingFileSink_Archive_Leftovers(t *testing.T) {
+
+	dir, _ := ioutil.TempDir("", "go-log")
+	defer os.RemoveAll(dir)
+
+	store := newMemoryStore()
+	store.setFailing(true)
+
+	sink, err := log.OpenRotatingFileSink(filepath.Join(dir, "app.log"), log.RotationOptions{
+		Archive: &log.ArchiveOptions{Store: store},
+	})
+	assert.NoError(t, err, "open")
+
+	sink.Write([]byte("first\n"))
+	assert.NoError(t, sink.Rotate(), "rotate")
+	assert.NoError(t, sink.Close(), "close")
+	assert.Len(t, rotatedFiles(t, dir), 1, "kept")
+
+	store.setFailing(false)
+
+	sink, err = log.OpenRotatingFileSink(filepath.Join(dir, "app.log"), log.RotationOptions{
+		Archive: &log.ArchiveOptions{Store: store},
+	})
+	assert.NoError(t, err, "reopen")
+	assert.NoError(t, sink.Close(), "close-again")
+
+	assert.Empty(t, rotatedFiles(t, dir), "local-removed")
+
+	names, _ := store.List("app-")
+	if assert.Len(t, names, 1, "uploaded") {
+		assert.Equal(t, "first\n", store.archives[names[0]], "contents")
+	}
+
+}
+
+func Test_RotatingFileSink_Archive_LeftoversEncrypted(t *testing.T) {
+
+	dir, _ := ioutil.TempDir("", "go-log")
+	defer os.RemoveAll(dir)
+
+	// A rotated file left in plain text, e.g. by a crash before it was compressed and encrypted
+	leftover := filepath.Join(dir, "app-2020-03-04T12-00-00.000.log")
+	assert.NoError(t, ioutil.WriteFile(leftover, []byte("secret\n"), 0644), "write")
+
+	store := newMemoryStore()
+	sink, err := log.OpenRotatingFileSink(filepath.Join(dir, "app.log"), log.RotationOptions{
+		Compress:      true,
+		EncryptionKey: make([]byte, 32),
+		Archive:       &log.ArchiveOptions{Store: store},
+	})
+	assert.NoError(t, err, "open")
+	assert.NoError(t, sink.Close(), "close")
+
+	assert.Empty(t, rotatedFiles(t, dir), "local-removed")
+
+	names, _ := store.List("app-")
+	if assert.Len(t, names, 1, "uploaded") {
+		assert.Equal(t, "app-2020-03-04T12-00-00.000.log.gz.enc", names[0], "name")
+		assert.NotContains(t, store.archives[names[0]], "secret", "encrypted")
+	}
+
+}
+
+func Test_RotatingFileSink_Archive_PendingKept(t *testing.T) {
+
+	dir, _ := ioutil.TempDir("", "go-log")
+	defer os.RemoveAll(dir)
+
+	store := newMemoryStore()
+	store.setFailing(true)
+
+	sink, err := log.OpenRotatingFileSink(filepath.Join(dir, "app.log"), log.RotationOptions{
+		MaxBackups: 1,
+		Archive:    &log.ArchiveOptions{Store: store},
+	})
+	assert.NoError(t, err, "open")
+
+	sink.Write([]byte("first\n"))
+	assert.NoError(t, sink.Rotate(), "rotate")
+	sink.Write([]byte("second\n"))
+	assert.NoError(t, sink.Rotate(), "rotate-again")
+	assert.NoError(t, sink.Close(), "close")
+
+	assert.Len(t, rotatedFiles(t, dir), 2, "pending-kept")
+
+}
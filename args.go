@@ -0,0 +1,76 @@
+package log
+
+import (
+	"strconv"
+	"strings"
+)
+
+// InfoArgs prints the command line of the invocation with the values of the given flags masked
+//
+// The flags in maskFlags are given without their dashes (e.g. "password"). Both "-flag=value" and "-flag value"
+// are masked, using one or two dashes, the argument after "-flag" is always taken as its value (even when it starts
+// with a dash), as the flag package does. The program name is added as the "command" field and the masked arguments
+// as the "args" field. Typically called as log.InfoArgs(os.Args, "password", "token").
+func InfoArgs(args []string, maskFlags ...string) {
+
+	if len(args) == 0 {
+		return
+	}
+
+	printEntry("INFO", "Command line", map[string]interface{}{
+		"command": args[0],
+		"args":    strings.Join(maskArgs(args[1:], maskFlags), " "),
+	})
+
+}
+
+func maskArgs(args []string, maskFlags []string) []string {
+
+	masked := make(map[string]bool, len(maskFlags))
+	for _, flag := range maskFlags {
+		masked[strings.TrimLeft(flag, "-")] = true
+	}
+
+	result := make([]string, 0, len(args))
+	maskNext := false
+
+	for i, arg := range args {
+
+		if maskNext {
+			maskNext = false
+			result = append(result, DefaultScrubReplacement)
+			continue
+		}
+
+		if arg == "--" {
+			for _, rest := range args[i:] {
+				result = append(result, quoteArg(rest))
+			}
+			break
+		}
+
+		if strings.HasPrefix(arg, "-") {
+			name := strings.TrimLeft(arg, "-")
+			if eq := strings.IndexByte(name, '='); eq >= 0 {
+				if masked[name[:eq]] {
+					arg = arg[:len(arg)-len(name)+eq+1] + DefaultScrubReplacement
+				}
+			} else if masked[name] {
+				maskNext = true
+			}
+		}
+
+		result = append(result, quoteArg(arg))
+
+	}
+
+	return result
+
+}
+
+func quoteArg(arg string) string {
+	if arg == "" || strings.ContainsAny(arg, " \t\r\n\"'") {
+		return strconv.Quote(arg)
+	}
+	return arg
+}
@@ -0,0 +1,40 @@
+package log
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_maskArgs(t *testing.T) {
+
+	type test struct {
+		name      string
+		args      []string
+		maskFlags []string
+		expected  []string
+	}
+
+	var tests = []test{
+		{"empty", []string{}, []string{"password"}, []string{}},
+		{"no-mask", []string{"-v", "file.txt"}, nil, []string{"-v", "file.txt"}},
+		{"equals", []string{"-password=secret"}, []string{"password"}, []string{"-password=[REDACTED]"}},
+		{"double-dash", []string{"--password=secret"}, []string{"password"}, []string{"--password=[REDACTED]"}},
+		{"separate", []string{"--token", "abc", "file.txt"}, []string{"token"}, []string{"--token", "[REDACTED]", "file.txt"}},
+		{"separate-dash", []string{"--password", "-secret", "file.txt"}, []string{"password"}, []string{"--password", "[REDACTED]", "file.txt"}},
+		{"separate-terminator", []string{"--password", "--", "file.txt"}, []string{"password"}, []string{"--password", "[REDACTED]", "file.txt"}},
+		{"trailing", []string{"--token"}, []string{"token"}, []string{"--token"}},
+		{"dashed-mask-flag", []string{"-token=abc"}, []string{"--token"}, []string{"-token=[REDACTED]"}},
+		{"other-flag", []string{"-user=john"}, []string{"password"}, []string{"-user=john"}},
+		{"quoted", []string{"-name", "john doe"}, nil, []string{"-name", `"john doe"`}},
+		{"terminator", []string{"--", "--password=secret"}, []string{"password"}, []string{"--", "--password=secret"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := maskArgs(tc.args, tc.maskFlags)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+
+}
@@ -0,0 +1,22 @@
+package log_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func Test_InfoArgs(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+
+	log.InfoArgs([]string{"app", "--user", "john", "--password=secret"}, "password")
+	log.InfoArgs([]string{})
+
+	assert.Equal(t, "test | INFO  | Command line args=\"--user john --password=[REDACTED]\" command=app\n", stdout.String())
+
+}
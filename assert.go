@@ -0,0 +1,83 @@
+package log
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// ErrAssertionFailed is wrapped by the value of the panics raised by Assert and AssertNoError in DebugMode
+var ErrAssertionFailed = errors.New("assertion failed")
+
+// Assert checks an invariant, when cond is false it logs an error described by the arguments with the caller and the
+// stack trace
+//
+// In DebugMode it panics instead, so that violated invariants can't be missed during development. The entry has the
+// field "assertion=true" so that violations can be counted separately from regular errors.
+func Assert(cond bool, args ...interface{}) {
+	if !cond {
+		failAssertion(formatMessage(args...))
+	}
+}
+
+// AssertNoError checks that err is nil, see Assert
+func AssertNoError(err error) {
+	if err != nil {
+		failAssertion(err.Error())
+	}
+}
+
+func failAssertion(description string) {
+
+	if DebugMode {
+		if description == "" {
+			panic(ErrAssertionFailed)
+		}
+		panic(fmt.Errorf("%w: %s", ErrAssertionFailed, description))
+	}
+
+	message := "Assertion failed"
+	if description != "" {
+		message += ": " + description
+	}
+
+	fields := map[string]interface{}{"assertion": true}
+	if caller := formatCaller(CallerSkip); caller != "" {
+		fields["caller"] = caller
+	}
+
+	printEntry("ERROR", message+"\n"+callerStack(), fields)
+
+}
+
+// callerStack returns the stack of the caller, skipping the frames of the logger itself (which are inlined into the
+// caller for short functions such as Assert)
+func callerStack() string {
+
+	pcs := make([]uintptr, 64)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	var b strings.Builder
+	sources := map[string][][]byte{}
+	found := false
+
+	for {
+		frame, more := frames.Next()
+		if found || !isSkippedPackage(funcPackage(frame.Function)) {
+			found = true
+			frame = resolveFrame(frame)
+			fmt.Fprintf(&b, "%s:%d (0x%x)\n", frame.File, frame.Line, frame.PC)
+			if source, ok := sourceLine(sources, frame.File, frame.Line); ok {
+				b.WriteString("\t" + frameName(frame.Function) + ": " + source + "\n")
+			}
+		}
+		if !more {
+			break
+		}
+	}
+
+	return strings.TrimSpace(b.String())
+
+}
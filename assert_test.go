@@ -0,0 +1,89 @@
+package log_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func Test_Assert(t *testing.T) {
+
+	type test struct {
+		name            string
+		assert          func()
+		expectedMessage string
+	}
+
+	var tests = []test{
+		{"assert", func() { log.Assert(1 > 2, "one is larger than", 2) }, "Assertion failed: one is larger than 2"},
+		{"assert-no-description", func() { log.Assert(false) }, "Assertion failed"},
+		{"assert-no-error", func() { log.AssertNoError(errors.New("unexpected")) }, "Assertion failed: unexpected"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			resetLogConfig()
+			stdout, stderr := redirectOutput()
+			defer resetLogOutput()
+
+			tc.assert()
+
+			lines := strings.Split(stderr.String(), "\n")
+			assert.Equal(t, "", stdout.String(), "stdout")
+			assert.Equal(t, "test | ERROR | "+tc.expectedMessage, lines[0], "message")
+			assert.Contains(t, lines[1], "assert_test.go:", "stack")
+			assert.Contains(t, stderr.String(), "assertion=true", "assertion")
+			assert.Regexp(t, `caller=\S+assert_test.go:\d+`, stderr.String(), "caller")
+
+		})
+	}
+
+}
+
+func Test_Assert_Valid(t *testing.T) {
+
+	resetLogConfig()
+	stdout, stderr := redirectOutput()
+	defer resetLogOutput()
+
+	log.Assert(true, "never shown")
+	log.AssertNoError(nil)
+
+	assert.Equal(t, "", stdout.String(), "stdout")
+	assert.Equal(t, "", stderr.String(), "stderr")
+
+}
+
+func Test_Assert_DebugMode(t *testing.T) {
+
+	resetLogConfig()
+	_, stderr := redirectOutput()
+	defer resetLogOutput()
+
+	log.DebugMode = true
+
+	var recovered interface{}
+	func() {
+		defer func() {
+			recovered = recover()
+		}()
+		log.Assert(false, "invariant")
+	}()
+
+	err, ok := recovered.(error)
+	if assert.True(t, ok, "error") {
+		assert.True(t, errors.Is(err, log.ErrAssertionFailed), "is")
+		assert.EqualError(t, err, "assertion failed: invariant", "message")
+	}
+	assert.Equal(t, "", stderr.String(), "stderr")
+
+	assert.PanicsWithValue(t, log.ErrAssertionFailed, func() {
+		log.AssertNoError(errors.New(""))
+	}, "no-description")
+
+}
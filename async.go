@@ -0,0 +1,274 @@
+package log
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrBufferFull is returned when an entry is dropped because the buffer of an AsyncWriter is full
+var ErrBufferFull = errors.New("async buffer is full")
+
+// OverflowPolicy defines what happens when an entry is written to an AsyncWriter with a full buffer
+type OverflowPolicy int
+
+const (
+	// OverflowBlock waits until there is room in the buffer
+	OverflowBlock OverflowPolicy = iota
+
+	// OverflowDrop drops the entry and returns ErrBufferFull, which counts it as dropped in the Health status and
+	// writes it to the DeadLetter
+	OverflowDrop
+)
+
+// AsyncOptions are the options used to create an AsyncWriter
+type AsyncOptions struct {
+	// BufferSize is the number of entries which can be queued (defaults to 1024)
+	BufferSize int
+
+	// Overflow defines what happens when the buffer is full (defaults to OverflowBlock)
+	Overflow OverflowPolicy
+}
+
+// AsyncWriter queues the entries in a bounded buffer and writes them to the wrapped writer from a background
+// goroutine, so that slow outputs don't add latency to the callers
+//
+// Errors of the wrapped writer are returned by the next call to Flush or Close.
+type AsyncWriter struct {
+	w       io.Writer
+	opts    AsyncOptions
+	queue   chan asyncEntry
+	mutex   sync.RWMutex
+	closed  bool
+	done    chan struct{}
+	errMu   sync.Mutex
+	err     error
+	dropped uint64
+}
+
+// queuedWriter is implemented by writers which report the number of queued entries in the Stats
+type queuedWriter interface {
+	queueDepth() int
+}
+
+type asyncEntry struct {
+	level   string
+	p       []byte
+	flushed chan struct{}
+
+	// start and sink are set when MeasureLatency is enabled, to record the latency of the entry once it's written
+	start time.Time
+	sink  string
+}
+
+// NewAsyncWriter creates an AsyncWriter which writes to w
+func NewAsyncWriter(w io.Writer, opts AsyncOptions) *AsyncWriter {
+
+	if opts.BufferSize <= 0 {
+		opts.BufferSize = 1024
+	}
+
+	a := &AsyncWriter{
+		w:     w,
+		opts:  opts,
+		queue: make(chan asyncEntry, opts.BufferSize),
+		done:  make(chan struct{}),
+	}
+
+	go a.run()
+
+	return a
+
+}
+
+// ToAsync wraps Stdout and Stderr in AsyncWriters, use Flush and Close to wait for the queued entries
+//
+// Fatal and CheckError flush the queued entries before exiting.
+func ToAsync(opts AsyncOptions) {
+
+	stdout := NewAsyncWriter(Stdout, opts)
+	stderr := stdout
+	if Stderr != Stdout {
+		stderr = NewAsyncWriter(Stderr, opts)
+	}
+
+	Stdout = stdout
+	Stderr = stderr
+
+}
+
+// Flush waits until the entries queued for Stdout and Stderr are written when they are AsyncWriters
+func Flush() error {
+	return std.flush()
+}
+
+// flush waits until the entries queued for the outputs of the logger are written
+func (l *Logger) flush() error {
+	var result error
+	for _, a := range asyncOutputs(l.stdout(), l.stderr()) {
+		if err := a.Flush(); err != nil && result == nil {
+			result = err
+		}
+	}
+	return result
+}
+
+// Close flushes and closes the AsyncWriters used as Stdout and Stderr and restores the writers they wrap
+func Close() error {
+
+	var result error
+	for _, a := range asyncOutputs(Stdout, Stderr) {
+		if err := a.Close(); err != nil && result == nil {
+			result = err
+		}
+	}
+
+	if a, ok := Stdout.(*AsyncWriter); ok {
+		Stdout = a.w
+	}
+	if a, ok := Stderr.(*AsyncWriter); ok {
+		Stderr = a.w
+	}
+
+	return result
+
+}
+
+func asyncOutputs(stdout io.Writer, stderr io.Writer) []*AsyncWriter {
+	outputs := []*AsyncWriter{}
+	if a, ok := stdout.(*AsyncWriter); ok {
+		outputs = append(outputs, a)
+	}
+	if a, ok := stderr.(*AsyncWriter); ok && stderr != stdout {
+		outputs = append(outputs, a)
+	}
+	return outputs
+}
+
+// Write queues p as an info entry
+func (a *AsyncWriter) Write(p []byte) (int, error) {
+	return a.writeLevel("INFO", p)
+}
+
+// Flush waits until the queued entries are written and returns the first error of the wrapped writer since the
+// previous flush
+func (a *AsyncWriter) Flush() error {
+
+	a.mutex.RLock()
+	if a.closed {
+		a.mutex.RUnlock()
+		return ErrSinkClosed
+	}
+	flushed := make(chan struct{})
+	a.queue <- asyncEntry{flushed: flushed}
+	a.mutex.RUnlock()
+
+	<-flushed
+
+	return a.takeErr()
+
+}
+
+// Close writes the queued entries and stops the background goroutine, the wrapped writer is left open
+func (a *AsyncWriter) Close() error {
+
+	a.mutex.Lock()
+	if a.closed {
+		a.mutex.Unlock()
+		return nil
+	}
+	a.closed = true
+	close(a.queue)
+	a.mutex.Unlock()
+
+	<-a.done
+
+	return a.takeErr()
+
+}
+
+// Dropped returns the number of entries which were dropped because the buffer was full
+func (a *AsyncWriter) Dropped() uint64 {
+	return atomic.LoadUint64(&a.dropped)
+}
+
+func (a *AsyncWriter) queueDepth() int {
+	return len(a.queue)
+}
+
+// writeLevel queues a copy of p, keeping the level for writers which need it
+func (a *AsyncWriter) writeLevel(level string, p []byte) (int, error) {
+	return a.queueEntry(asyncEntry{level: level, p: append([]byte(nil), p...)})
+}
+
+// queueEntry queues the entry according to the overflow policy
+func (a *AsyncWriter) queueEntry(e asyncEntry) (int, error) {
+
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+
+	if a.closed {
+		return 0, ErrSinkClosed
+	}
+
+	if a.opts.Overflow == OverflowDrop {
+		select {
+		case a.queue <- e:
+		default:
+			atomic.AddUint64(&a.dropped, 1)
+			return 0, ErrBufferFull
+		}
+		return len(e.p), nil
+	}
+
+	a.queue <- e
+	return len(e.p), nil
+
+}
+
+func (a *AsyncWriter) run() {
+
+	defer close(a.done)
+
+	for e := range a.queue {
+		if e.flushed != nil {
+			close(e.flushed)
+			continue
+		}
+		if _, err := writeLevel(a.w, e.level, e.p); err != nil {
+			a.errMu.Lock()
+			if a.err == nil {
+				a.err = err
+			}
+			a.errMu.Unlock()
+		} else if !e.start.IsZero() {
+			health.recordLatency(e.sink, time.Since(e.start))
+		}
+	}
+
+}
+
+func (a *AsyncWriter) takeErr() error {
+	a.errMu.Lock()
+	defer a.errMu.Unlock()
+	err := a.err
+	a.err = nil
+	return err
+}
+
+// unwrapWriter returns the writer which receives the entries written to w, looking through the wrappers of this
+// package
+func unwrapWriter(w io.Writer) io.Writer {
+	for {
+		switch wrapper := w.(type) {
+		case timestampOverride:
+			w = wrapper.Writer
+		case *AsyncWriter:
+			w = wrapper.w
+		default:
+			return w
+		}
+	}
+}
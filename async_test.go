@@ -0,0 +1,143 @@
+package log_test
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+// blockingWriter blocks every write until it is released
+type blockingWriter struct {
+	mutex   sync.Mutex
+	buf     bytes.Buffer
+	release chan struct{}
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	<-w.release
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.buf.Write(p)
+}
+
+func (w *blockingWriter) String() string {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.buf.String()
+}
+
+func Test_AsyncWriter(t *testing.T) {
+
+	w := &blockingWriter{release: make(chan struct{})}
+	a := log.NewAsyncWriter(w, log.AsyncOptions{})
+
+	n, err := a.Write([]byte("first\n"))
+	assert.NoError(t, err, "write")
+	assert.Equal(t, 6, n, "n")
+	a.Write([]byte("second\n"))
+
+	assert.Empty(t, w.String(), "queued")
+
+	close(w.release)
+
+	assert.NoError(t, a.Flush(), "flush")
+	assert.Equal(t, "first\nsecond\n", w.String(), "flushed")
+
+	assert.NoError(t, a.Close(), "close")
+	assert.NoError(t, a.Close(), "close-twice")
+
+	_, err = a.Write([]byte("closed\n"))
+	assert.Equal(t, log.ErrSinkClosed, err, "write-closed")
+	assert.Equal(t, log.ErrSinkClosed, a.Flush(), "flush-closed")
+
+}
+
+func Test_AsyncWriter_OverflowDrop(t *testing.T) {
+
+	w := &blockingWriter{release: make(chan struct{})}
+	a := log.NewAsyncWriter(w, log.AsyncOptions{BufferSize: 2, Overflow: log.OverflowDrop})
+
+	// The first entry is taken by the background goroutine, which blocks on writing it
+	a.Write([]byte("1\n"))
+	for a.Dropped() == 0 {
+		a.Write([]byte("x\n"))
+	}
+
+	_, err := a.Write([]byte("dropped\n"))
+	assert.Equal(t, log.ErrBufferFull, err, "full")
+
+	close(w.release)
+	assert.NoError(t, a.Close(), "close")
+
+	assert.True(t, strings.HasPrefix(w.String(), "1\n"), "written")
+	assert.NotContains(t, w.String(), "dropped", "dropped")
+
+}
+
+func Test_AsyncWriter_Error(t *testing.T) {
+
+	a := log.NewAsyncWriter(failingWriter{}, log.AsyncOptions{})
+
+	_, err := a.Write([]byte("message\n"))
+	assert.NoError(t, err, "write")
+
+	assert.EqualError(t, a.Flush(), "write failed", "flush")
+	assert.NoError(t, a.Flush(), "flush-again")
+	assert.NoError(t, a.Close(), "close")
+
+}
+
+func Test_ToAsync(t *testing.T) {
+
+	resetLogConfig()
+	stdout, stderr := redirectOutput()
+	defer resetLogOutput()
+
+	log.ToAsync(log.AsyncOptions{})
+
+	log.Info("info")
+	log.Error("error")
+
+	assert.NoError(t, log.Flush(), "flush")
+	assert.Equal(t, "test | INFO  | info\n", stdout.String(), "stdout")
+	assert.Equal(t, "test | ERROR | error\n", stderr.String(), "stderr")
+
+	log.Info("last")
+
+	assert.NoError(t, log.Close(), "close")
+	assert.Equal(t, stdout, log.Stdout, "restored-stdout")
+	assert.Equal(t, stderr, log.Stderr, "restored-stderr")
+	assert.Equal(t, "test | INFO  | info\ntest | INFO  | last\n", stdout.String(), "closed")
+
+}
+
+func Test_ToAsync_Fatal(t *testing.T) {
+
+	resetLogConfig()
+	_, stderr := redirectOutput()
+	defer resetLogOutput()
+
+	log.ToAsync(log.AsyncOptions{})
+	defer log.Close()
+
+	oldOsExit := log.OsExit
+	defer func() {
+		log.OsExit = oldOsExit
+	}()
+
+	var exitCode int
+	log.OsExit = func(code int) {
+		exitCode = code
+		assert.Equal(t, "test | FATAL | fatal\n", stderr.String(), "flushed-before-exit")
+	}
+
+	log.Fatal("fatal")
+
+	assert.Equal(t, 1, exitCode, "exit-code")
+
+}
@@ -0,0 +1,41 @@
+package log
+
+// BreakerState is the state of a circuit breaker
+type BreakerState string
+
+const (
+	// BreakerClosed is the state in which requests pass through
+	BreakerClosed BreakerState = "closed"
+
+	// BreakerOpen is the state in which requests are rejected
+	BreakerOpen BreakerState = "open"
+
+	// BreakerHalfOpen is the state in which a limited number of requests is let through to probe for recovery
+	BreakerHalfOpen BreakerState = "half-open"
+)
+
+// BreakerReporter is the interface circuit breakers use to report their state transitions
+type BreakerReporter interface {
+	BreakerStateChanged(component string, from BreakerState, to BreakerState, failureRate float64)
+}
+
+// BreakerLogger is a BreakerReporter which logs the state transitions as notices
+type BreakerLogger struct{}
+
+// BreakerStateChanged logs the state transition as a notice
+func (BreakerLogger) BreakerStateChanged(component string, from BreakerState, to BreakerState, failureRate float64) {
+	LogBreakerStateChange(component, from, to, failureRate)
+}
+
+// LogBreakerStateChange logs a circuit breaker state transition as a notice with the component, the old and new
+// state and the failure rate (between 0 and 1) which triggered it
+//
+// It can be passed directly as the state change callback of most circuit breaker libraries.
+func LogBreakerStateChange(component string, from BreakerState, to BreakerState, failureRate float64) {
+	printEntry("NOTE", "Circuit breaker state changed", map[string]interface{}{
+		"component":    component,
+		"from":         string(from),
+		"to":           string(to),
+		"failure_rate": failureRate,
+	})
+}
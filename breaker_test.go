@@ -0,0 +1,28 @@
+package log_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func Test_LogBreakerStateChange(t *testing.T) {
+
+	resetLogConfig()
+	stdout, stderr := redirectOutput()
+	defer resetLogOutput()
+
+	var reporter log.BreakerReporter = log.BreakerLogger{}
+
+	reporter.BreakerStateChanged("payments", log.BreakerClosed, log.BreakerOpen, 0.75)
+	reporter.BreakerStateChanged("payments", log.BreakerOpen, log.BreakerHalfOpen, 0.75)
+
+	expected := "test | NOTE  | Circuit breaker state changed component=payments failure_rate=0.75 from=closed to=open\n" +
+		"test | NOTE  | Circuit breaker state changed component=payments failure_rate=0.75 from=open to=half-open\n"
+
+	assert.Equal(t, expected, stdout.String(), "stdout")
+	assert.Equal(t, "", stderr.String(), "stderr")
+
+}
@@ -0,0 +1,166 @@
+package log
+
+import (
+	"path"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// CallerStyle defines how the caller is formatted
+type CallerStyle int
+
+const (
+	// CallerDefault uses CallerFormat, it's the default CallerFormat of the outputs (see OutputOptions)
+	CallerDefault CallerStyle = iota
+
+	// CallerFull reports the full path of the file and the line (e.g. /src/app/pkg/file.go:123)
+	CallerFull
+
+	// CallerShort reports the file name and the line (e.g. file.go:123)
+	CallerShort
+
+	// CallerPackage reports the directory, file name and the line (e.g. pkg/file.go:123)
+	CallerPackage
+
+	// CallerFunction reports the package and function name only (e.g. pkg.Function)
+	CallerFunction
+)
+
+// ReportCaller indicates if the file and line of the calling code should be included in the log messages
+var ReportCaller = false
+
+// CallerSkipPackages contains the packages (or package path prefixes) which are skipped when looking up the
+// caller, such as logging wrappers or middleware
+var CallerSkipPackages = []string{}
+
+// CallerTrimPrefixes contains the path prefixes (such as the module root) which are trimmed from the reported file
+var CallerTrimPrefixes = []string{}
+
+// CallerFormat defines how the caller is formatted when ReportCaller is enabled, unless the output has its own
+// CallerFormat (see OutputOptions)
+var CallerFormat = CallerFull
+
+// CallerWithFunction appends the function name to the reported file and line (e.g. file.go:123 pkg.Function)
+var CallerWithFunction = false
+
+// CallerSkip is the number of extra stack frames which are skipped when looking up the caller, for wrapper
+// functions which are not in one of the CallerSkipPackages
+var CallerSkip = 0
+
+var packagePath = funcPackage(runtime.FuncForPC(currentPC()).Name())
+
+func currentPC() uintptr {
+	pc, _, _, _ := runtime.Caller(0)
+	return pc
+}
+
+func formatCaller(skip int) string {
+
+	frame, ok := findCaller(skip)
+	if !ok {
+		return ""
+	}
+
+	return formatCallerFrame(frame, CallerFormat)
+
+}
+
+// findCallerPC returns the frame of the program counter, e.g. of a slog record
+func findCallerPC(pc uintptr) (runtime.Frame, bool) {
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	return frame, frame.PC != 0
+}
+
+// formatCallerFrame formats the caller in the given style, CallerDefault uses CallerFormat
+func formatCallerFrame(frame runtime.Frame, style CallerStyle) string {
+
+	if style == CallerDefault {
+		style = CallerFormat
+	}
+
+	caller := formatFrame(frame, style)
+	if CallerWithFunction && style != CallerFunction {
+		_, function := path.Split(frame.Function)
+		caller += " " + function
+	}
+
+	return caller
+
+}
+
+func formatFrame(frame runtime.Frame, style CallerStyle) string {
+
+	if style == CallerFunction {
+		_, function := path.Split(frame.Function)
+		return function
+	}
+
+	file := frame.File
+	if trimsSourcePaths() {
+		file = trimSourcePath(file, frame.Function)
+	}
+
+	switch style {
+	case CallerShort:
+		file = path.Base(file)
+	case CallerPackage:
+		file = path.Join(path.Base(path.Dir(file)), path.Base(file))
+	default:
+		for _, prefix := range CallerTrimPrefixes {
+			if strings.HasPrefix(file, prefix) {
+				file = strings.TrimPrefix(strings.TrimPrefix(file, prefix), "/")
+				break
+			}
+		}
+	}
+
+	return file + ":" + strconv.Itoa(frame.Line)
+
+}
+
+// findCaller returns the first frame outside of the skipped packages, after skipping skip more frames
+func findCaller(skip int) (runtime.Frame, bool) {
+
+	pcs := make([]uintptr, 32+skip)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	found := false
+	for {
+		frame, more := frames.Next()
+		if found || !isSkippedPackage(funcPackage(frame.Function)) {
+			if skip <= 0 {
+				return frame, true
+			}
+			found = true
+			skip--
+		}
+		if !more {
+			return runtime.Frame{}, false
+		}
+	}
+
+}
+
+func isSkippedPackage(pkg string) bool {
+	if pkg == packagePath {
+		return true
+	}
+	for _, skip := range CallerSkipPackages {
+		if pkg == skip || strings.HasPrefix(pkg, strings.TrimSuffix(skip, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// funcPackage returns the package path of a function name as reported by the runtime, which escapes the dots in
+// the last path element (e.g. "gopkg.in/yaml%2ev2.Marshal")
+func funcPackage(function string) string {
+	dir, name := path.Split(function)
+	if i := strings.Index(name, "."); i >= 0 {
+		name = name[:i]
+	}
+	return dir + strings.Replace(name, "%2e", ".", -1)
+}
@@ -0,0 +1,67 @@
+package log
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_funcPackage(t *testing.T) {
+
+	type test struct {
+		name     string
+		function string
+		expected string
+	}
+
+	var tests = []test{
+		{"function", "github.com/pieterclaerhout/go-log.Info", "github.com/pieterclaerhout/go-log"},
+		{"method", "github.com/pieterclaerhout/go-log.(*healthState).sink", "github.com/pieterclaerhout/go-log"},
+		{"closure", "github.com/pieterclaerhout/go-log.HealthHandler.func1", "github.com/pieterclaerhout/go-log"},
+		{"main", "main.main", "main"},
+		{"dotted-path", "gopkg.in/yaml%2ev2.Marshal", "gopkg.in/yaml.v2"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := funcPackage(tc.function)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+
+}
+
+func Test_packagePath(t *testing.T) {
+	assert.Equal(t, "github.com/pieterclaerhout/go-log", packagePath)
+}
+
+func Test_formatFrame(t *testing.T) {
+
+	frame := runtime.Frame{
+		Function: "github.com/pieterclaerhout/go-log.(*healthState).sink",
+		File:     "/src/go-log/health.go",
+		Line:     123,
+	}
+
+	type test struct {
+		name     string
+		style    CallerStyle
+		expected string
+	}
+
+	var tests = []test{
+		{"full", CallerFull, "/src/go-log/health.go:123"},
+		{"short", CallerShort, "health.go:123"},
+		{"package", CallerPackage, "go-log/health.go:123"},
+		{"function", CallerFunction, "go-log.(*healthState).sink"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := formatFrame(frame, tc.style)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+
+}
@@ -0,0 +1,199 @@
+package log_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func resetCallerConfig() {
+	log.ReportCaller = false
+	log.CallerSkipPackages = []string{}
+	log.CallerTrimPrefixes = []string{}
+	log.CallerFormat = log.CallerFull
+	log.CallerWithFunction = false
+	log.CallerSkip = 0
+}
+
+func Test_ReportCaller(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+	defer resetCallerConfig()
+
+	_, file, line, _ := runtime.Caller(0)
+
+	log.ReportCaller = true
+	log.Info("info")
+
+	expected := "test | INFO  | " + file + ":" + strconv.Itoa(line+3) + " | info\n"
+	assert.Equal(t, expected, stdout.String())
+
+}
+
+func Test_ReportCaller_TrimPrefix(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+	defer resetCallerConfig()
+
+	_, file, line, _ := runtime.Caller(0)
+
+	log.ReportCaller = true
+	log.CallerTrimPrefixes = []string{filepath.Dir(file)}
+	log.Warn("warn")
+
+	expected := "test | WARN  | caller_test.go:" + strconv.Itoa(line+4) + " | warn\n"
+	assert.Equal(t, expected, stdout.String())
+
+}
+
+func logFromWrapper(message string) {
+	log.Info(message)
+}
+
+func Test_ReportCaller_SkipPackages(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+	defer resetCallerConfig()
+
+	log.ReportCaller = true
+	log.CallerSkipPackages = []string{"github.com/pieterclaerhout/go-log_test"}
+	log.CallerTrimPrefixes = []string{runtime.GOROOT()}
+
+	logFromWrapper("info")
+
+	assert.Contains(t, stdout.String(), "testing/testing.go:")
+
+}
+
+func Test_ReportCaller_Skip(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+	defer resetCallerConfig()
+
+	log.ReportCaller = true
+	log.CallerSkip = 1
+
+	_, file, line, _ := runtime.Caller(0)
+	logFromWrapper("info")
+
+	expected := "test | INFO  | " + file + ":" + strconv.Itoa(line+1) + " | info\n"
+	assert.Equal(t, expected, stdout.String())
+
+}
+
+func Test_ReportCaller_SkipLogger(t *testing.T) {
+
+	resetLogConfig()
+	defer resetCallerConfig()
+
+	stdout := &bytes.Buffer{}
+	logger := log.New(log.WithOutput(stdout, stdout), log.WithCallerSkip(1))
+
+	log.ReportCaller = true
+
+	_, file, line, _ := runtime.Caller(0)
+	func() {
+		logger.Info("info")
+	}()
+
+	assert.Equal(t, file+":"+strconv.Itoa(line+3)+" | info\n", stdout.String())
+
+}
+
+func Test_ReportCaller_OutputFormat(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+	defer resetCallerConfig()
+	defer log.RemoveOutputs()
+
+	log.ReportCaller = true
+	log.CallerFormat = log.CallerShort
+
+	full := &bytes.Buffer{}
+	function := &bytes.Buffer{}
+	inherited := &bytes.Buffer{}
+
+	log.AddOutput(full, log.OutputOptions{CallerFormat: log.CallerFull})
+	log.AddOutput(function, log.OutputOptions{CallerFormat: log.CallerFunction})
+	log.AddOutput(inherited, log.OutputOptions{})
+
+	_, file, line, _ := runtime.Caller(0)
+	log.Info("info")
+
+	short := "test | INFO  | caller_test.go:" + strconv.Itoa(line+1) + " | info\n"
+	assert.Equal(t, short, stdout.String(), "stdout")
+	assert.Equal(t, short, inherited.String(), "inherited")
+	assert.Equal(t, "test | INFO  | "+file+":"+strconv.Itoa(line+1)+" | info\n", full.String(), "full")
+	assert.Equal(t, "test | INFO  | go-log_test.Test_ReportCaller_OutputFormat | info\n", function.String(), "function")
+
+}
+
+func Test_ReportCaller_WithFunction(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+	defer resetCallerConfig()
+
+	log.ReportCaller = true
+	log.CallerFormat = log.CallerShort
+	log.CallerWithFunction = true
+
+	_, _, line, _ := runtime.Caller(0)
+	log.Info("info")
+
+	expected := "test | INFO  | caller_test.go:" + strconv.Itoa(line+1) + " go-log_test.Test_ReportCaller_WithFunction | info\n"
+	assert.Equal(t, expected, stdout.String())
+
+}
+
+func Test_ReportCaller_Format(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+	defer resetCallerConfig()
+
+	log.ReportCaller = true
+	log.CallerFormat = log.CallerFunction
+	log.Info("info")
+
+	assert.Equal(t, "test | INFO  | go-log_test.Test_ReportCaller_Format | info\n", stdout.String())
+
+}
+
+func Test_ReportCaller_TrimSourcePaths(t *testing.T) {
+
+	resetLogConfig()
+	resetCallerConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+	defer resetCallerConfig()
+	defer func() {
+		log.TrimSourcePaths = false
+	}()
+
+	log.ReportCaller = true
+	log.TrimSourcePaths = true
+
+	log.Info("message")
+
+	assert.Regexp(t, `^test \| INFO  \| caller_test\.go:\d+ \| message\n$`, stdout.String())
+
+}
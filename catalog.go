@@ -0,0 +1,153 @@
+package log
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// EventIDField is the field which contains the event ID of a catalog message
+const EventIDField = "event.id"
+
+// LocaleField is the field which contains the locale in which a catalog message was rendered
+const LocaleField = "msg.locale"
+
+// Locale is the locale in which the catalog messages are rendered (e.g. "nl" or "nl-BE")
+//
+// When empty or when there is no translation for a message, the English template is used.
+var Locale = ""
+
+// CatalogMessage describes a log message with a stable event ID
+//
+// The template uses fmt.Sprintf verbs which are filled from the arguments passed at the log site. The
+// documentation describes the message, its cause and the possible remedies for the users of the product.
+type CatalogMessage struct {
+	ID            int    `json:"id"`
+	Template      string `json:"template"`
+	Documentation string `json:"documentation"`
+}
+
+var catalogMutex = &sync.RWMutex{}
+var catalog = map[int]CatalogMessage{}
+var translations = map[string]map[int]string{}
+
+// RegisterMessages adds the messages to the catalog, replacing the messages with the same IDs
+func RegisterMessages(messages ...CatalogMessage) {
+	catalogMutex.Lock()
+	defer catalogMutex.Unlock()
+	for _, message := range messages {
+		catalog[message.ID] = message
+	}
+}
+
+// RegisterTranslations adds the translated templates (by event ID) for the locale
+func RegisterTranslations(locale string, templates map[int]string) {
+	catalogMutex.Lock()
+	defer catalogMutex.Unlock()
+	locale = normalizeLocale(locale)
+	if translations[locale] == nil {
+		translations[locale] = map[int]string{}
+	}
+	for id, template := range templates {
+		translations[locale][id] = template
+	}
+}
+
+// LookupTranslation returns the translated template for the event ID in the locale
+//
+// If there is no translation for the locale (e.g. "nl-BE"), the base language (e.g. "nl") is tried.
+func LookupTranslation(locale string, id int) (string, bool) {
+	catalogMutex.RLock()
+	defer catalogMutex.RUnlock()
+	locale = normalizeLocale(locale)
+	for locale != "" {
+		if template, ok := translations[locale][id]; ok {
+			return template, true
+		}
+		i := strings.LastIndexByte(locale, '-')
+		if i < 0 {
+			break
+		}
+		locale = locale[:i]
+	}
+	return "", false
+}
+
+func normalizeLocale(locale string) string {
+	return strings.Replace(strings.ToLower(locale), "_", "-", -1)
+}
+
+// LookupMessage returns the catalog message with the given ID
+func LookupMessage(id int) (CatalogMessage, bool) {
+	catalogMutex.RLock()
+	defer catalogMutex.RUnlock()
+	message, ok := catalog[id]
+	return message, ok
+}
+
+// CatalogMessages returns all the messages of the catalog sorted by ID, e.g. to generate a reference of the
+// event IDs for the documentation
+func CatalogMessages() []CatalogMessage {
+	catalogMutex.RLock()
+	defer catalogMutex.RUnlock()
+	messages := make([]CatalogMessage, 0, len(catalog))
+	for _, message := range catalog {
+		messages = append(messages, message)
+	}
+	sort.Slice(messages, func(i, j int) bool {
+		return messages[i].ID < messages[j].ID
+	})
+	return messages
+}
+
+// DebugID prints the debug message with the given event ID from the catalog
+//
+// Only shown if DebugMode is set to true
+func DebugID(id int, args ...interface{}) {
+	if DebugMode || isRecording() {
+		printCatalogMessage("DEBUG", id, args...)
+	}
+}
+
+// InfoID prints the info message with the given event ID from the catalog
+//
+// The message is rendered in the configured Locale. The event ID is added as the "event.id" field and the English
+// template as the "msg.template" field. If the ID isn't in the catalog, the arguments are printed as with Info.
+func InfoID(id int, args ...interface{}) {
+	printCatalogMessage("INFO", id, args...)
+}
+
+// WarnID prints the warning message with the given event ID from the catalog
+func WarnID(id int, args ...interface{}) {
+	printCatalogMessage("WARN", id, args...)
+}
+
+// ErrorID prints the error message with the given event ID from the catalog
+func ErrorID(id int, args ...interface{}) {
+	printCatalogMessage("ERROR", id, args...)
+}
+
+func printCatalogMessage(level string, id int, args ...interface{}) {
+
+	fields := map[string]interface{}{EventIDField: id}
+
+	message, ok := LookupMessage(id)
+	if !ok {
+		printEntry(level, formatMessage(args...), fields)
+		return
+	}
+
+	fields[TemplateField] = message.Template
+
+	template := message.Template
+	if Locale != "" {
+		if translated, ok := LookupTranslation(Locale, id); ok {
+			template = translated
+			fields[LocaleField] = Locale
+		}
+	}
+
+	printEntry(level, fmt.Sprintf(template, args...), fields)
+
+}
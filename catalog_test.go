@@ -0,0 +1,105 @@
+package log_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func init() {
+	log.RegisterMessages(
+		log.CatalogMessage{ID: 1042, Template: "Disk %s is %d%% full", Documentation: "The disk is almost full, free up some space."},
+		log.CatalogMessage{ID: 1001, Template: "Server started", Documentation: "The server started."},
+	)
+}
+
+func Test_InfoID(t *testing.T) {
+
+	resetLogConfig()
+	stdout, stderr := redirectOutput()
+	defer resetLogOutput()
+
+	log.InfoID(1042, "/dev/sda1", 95)
+	log.WarnID(9999, "unknown", "event")
+	log.ErrorID(1001)
+	log.DebugID(1001)
+
+	expectedStdout := "test | INFO  | Disk /dev/sda1 is 95% full event.id=1042 msg.template=\"Disk %s is %d%% full\"\n" +
+		"test | WARN  | unknown event event.id=9999\n"
+
+	assert.Equal(t, expectedStdout, stdout.String(), "stdout")
+	assert.Equal(t, "test | ERROR | Server started event.id=1001 msg.template=\"Server started\"\n", stderr.String(), "stderr")
+
+}
+
+func Test_CatalogMessages(t *testing.T) {
+
+	messages := log.CatalogMessages()
+
+	assert.True(t, len(messages) >= 2, "count")
+	for i := 1; i < len(messages); i++ {
+		assert.True(t, messages[i-1].ID < messages[i].ID, "sorted")
+	}
+
+	message, found := log.LookupMessage(1042)
+	assert.True(t, found, "found")
+	assert.Equal(t, "The disk is almost full, free up some space.", message.Documentation, "documentation")
+
+	_, found = log.LookupMessage(9999)
+	assert.False(t, found, "not-found")
+
+}
+
+func Test_InfoID_Locale(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+
+	log.RegisterTranslations("nl", map[int]string{1042: "Schijf %s is %d%% vol"})
+
+	log.Locale = "nl-BE"
+	defer func() {
+		log.Locale = ""
+	}()
+
+	log.InfoID(1042, "/dev/sda1", 95)
+	log.InfoID(1001)
+
+	expected := "test | INFO  | Schijf /dev/sda1 is 95% vol event.id=1042 msg.locale=nl-BE msg.template=\"Disk %s is %d%% full\"\n" +
+		"test | INFO  | Server started event.id=1001 msg.template=\"Server started\"\n"
+
+	assert.Equal(t, expected, stdout.String())
+
+}
+
+func Test_LookupTranslation(t *testing.T) {
+
+	log.RegisterTranslations("fr-CA", map[int]string{1: "bonjour"})
+
+	type test struct {
+		name     string
+		locale   string
+		expected string
+		found    bool
+	}
+
+	var tests = []test{
+		{"exact", "fr-CA", "bonjour", true},
+		{"case-insensitive", "FR_ca", "bonjour", true},
+		{"base-missing", "fr", "", false},
+		{"region-fallback", "fr-CA-x", "bonjour", true},
+		{"empty", "", "", false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, found := log.LookupTranslation(tc.locale, 1)
+			assert.Equal(t, tc.found, found, "found")
+			assert.Equal(t, tc.expected, actual, "template")
+		})
+	}
+
+}
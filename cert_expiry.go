@@ -0,0 +1,78 @@
+//go:build !tinygo
+// +build !tinygo
+
+package log
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"time"
+)
+
+// CheckCertificateExpiry logs a warning for each certificate of config which expires within the given duration
+//
+// All certificates of each chain in config.Certificates are checked. Certificates which already expired are logged
+// as errors. The entries have the fields "subject", "issuer", "not_after", "expires_in" and "chain_index". An error
+// is returned when a certificate can't be parsed.
+func CheckCertificateExpiry(config *tls.Config, within time.Duration) error {
+
+	if config == nil {
+		return nil
+	}
+
+	now := time.Now()
+
+	for index, chain := range config.Certificates {
+		for position, der := range chain.Certificate {
+
+			cert := chain.Leaf
+			if position > 0 || cert == nil {
+				var err error
+				if cert, err = x509.ParseCertificate(der); err != nil {
+					return err
+				}
+			}
+
+			expiresIn := cert.NotAfter.Sub(now)
+			if expiresIn > within {
+				continue
+			}
+
+			fields := map[string]interface{}{
+				"subject":     cert.Subject.String(),
+				"issuer":      cert.Issuer.String(),
+				"not_after":   cert.NotAfter.UTC().Format(time.RFC3339),
+				"expires_in":  expiresIn.Round(time.Second),
+				"chain_index": index,
+			}
+
+			if expiresIn <= 0 {
+				printEntry("ERROR", "Certificate expired", fields)
+			} else {
+				printEntry("WARN", "Certificate expires soon", fields)
+			}
+
+		}
+	}
+
+	return nil
+
+}
+
+// WatchCertificateExpiry checks the certificates of config at once and then every interval
+//
+// See CheckCertificateExpiry for what is logged. Calling the returned function stops watching, it returns once the
+// certificates are no longer checked.
+func WatchCertificateExpiry(config *tls.Config, within time.Duration, interval time.Duration) (func(), error) {
+
+	if err := CheckCertificateExpiry(config, within); err != nil {
+		return nil, err
+	}
+
+	return poll(interval, func() {
+		if err := CheckCertificateExpiry(config, within); err != nil {
+			Error("Failed to check certificate expiry:", err)
+		}
+	}), nil
+
+}
@@ -0,0 +1,111 @@
+//go:build !tinygo
+// +build !tinygo
+
+package log_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func testCertificate(t *testing.T, name string, notAfter time.Time) []byte {
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err, "key")
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err, "certificate")
+
+	return der
+
+}
+
+func Test_CheckCertificateExpiry(t *testing.T) {
+
+	resetLogConfig()
+	stdout, stderr := redirectOutput()
+	defer resetLogOutput()
+
+	now := time.Now()
+	config := &tls.Config{
+		Certificates: []tls.Certificate{
+			{Certificate: [][]byte{
+				testCertificate(t, "soon.example.com", now.Add(48*time.Hour)),
+				testCertificate(t, "intermediate", now.Add(-time.Hour)),
+			}},
+			{Certificate: [][]byte{testCertificate(t, "later.example.com", now.Add(90*24*time.Hour))}},
+		},
+	}
+
+	err := log.CheckCertificateExpiry(config, 30*24*time.Hour)
+
+	assert.NoError(t, err, "error")
+	assert.Regexp(t, `^test \| WARN  \| Certificate expires soon chain_index=0 expires_in=4[78]h\S* issuer="CN=soon\.example\.com" not_after=\S+ subject="CN=soon\.example\.com"\n$`, stdout.String(), "stdout")
+	assert.Regexp(t, `^test \| ERROR \| Certificate expired chain_index=0 expires_in=-1h0m[01]s issuer="CN=intermediate" not_after=\S+ subject="CN=intermediate"\n$`, stderr.String(), "stderr")
+	assert.NotContains(t, stdout.String(), "later.example.com", "not-expiring")
+
+}
+
+func Test_CheckCertificateExpiry_Invalid(t *testing.T) {
+
+	resetLogConfig()
+	redirectOutput()
+	defer resetLogOutput()
+
+	assert.NoError(t, log.CheckCertificateExpiry(nil, time.Hour), "nil-config")
+
+	config := &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{[]byte("invalid")}}}}
+	assert.Error(t, log.CheckCertificateExpiry(config, time.Hour), "invalid")
+
+	_, err := log.WatchCertificateExpiry(config, time.Hour, time.Hour)
+	assert.Error(t, err, "watch-invalid")
+
+}
+
+func Test_WatchCertificateExpiry(t *testing.T) {
+
+	resetLogConfig()
+	redirectOutput()
+	defer resetLogOutput()
+
+	stdout := &syncBuffer{}
+	log.Stdout = stdout
+
+	config := &tls.Config{
+		Certificates: []tls.Certificate{
+			{Certificate: [][]byte{testCertificate(t, "soon.example.com", time.Now().Add(time.Hour))}},
+		},
+	}
+
+	stop, err := log.WatchCertificateExpiry(config, 24*time.Hour, 5*time.Millisecond)
+	assert.NoError(t, err, "watch")
+
+	assert.True(t, waitForOutput(stdout, "Certificate expires soon"), "initial")
+	deadline := time.Now().Add(2 * time.Second)
+	for strings.Count(stdout.String(), "Certificate expires soon") < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	stop()
+
+	assert.True(t, strings.Count(stdout.String(), "Certificate expires soon") >= 2, "periodic")
+
+}
@@ -0,0 +1,115 @@
+package log
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+)
+
+// ErrorKind is the class of an error, used to alert on classes of errors instead of individual messages
+type ErrorKind string
+
+const (
+	// ErrorKindTimeout is used for errors caused by timeouts
+	ErrorKindTimeout ErrorKind = "timeout"
+
+	// ErrorKindCanceled is used for errors caused by canceled operations
+	ErrorKindCanceled ErrorKind = "canceled"
+
+	// ErrorKindNotFound is used for errors caused by missing resources
+	ErrorKindNotFound ErrorKind = "not_found"
+
+	// ErrorKindConflict is used for errors caused by resources which already exist or changed concurrently
+	ErrorKindConflict ErrorKind = "conflict"
+
+	// ErrorKindInternal is used for all errors which can't be classified
+	ErrorKindInternal ErrorKind = "internal"
+)
+
+// ErrorClassifier returns the kind of err or false if it can't classify it
+type ErrorClassifier func(err error) (ErrorKind, bool)
+
+// ErrorClassifiers are the classifiers used by ClassifyError, in order
+//
+// Custom classifiers can be prepended to take precedence over the built-in ones
+var ErrorClassifiers = []ErrorClassifier{
+	ClassifyKindError,
+	ClassifyContextError,
+	ClassifyTimeoutError,
+	ClassifyNotFoundError,
+	ClassifyConflictError,
+}
+
+// ClassifyError returns the kind of err using the ErrorClassifiers
+//
+// If no classifier recognizes err, ErrorKindInternal is returned. For a nil error, an empty kind is returned.
+func ClassifyError(err error) ErrorKind {
+	if err == nil {
+		return ""
+	}
+	for _, classifier := range ErrorClassifiers {
+		if kind, ok := classifier(err); ok {
+			return kind
+		}
+	}
+	return ErrorKindInternal
+}
+
+// ErrorClassified prints an error message for err with the "error.kind" field set to its kind
+func ErrorClassified(err error, args ...interface{}) {
+	message := formatMessage(append(args, err)...)
+	printEntry("ERROR", message, map[string]interface{}{"error.kind": string(ClassifyError(err))})
+}
+
+// ClassifyKindError classifies errors in the chain which implement ErrorKind() ErrorKind
+func ClassifyKindError(err error) (ErrorKind, bool) {
+	type kinder interface {
+		ErrorKind() ErrorKind
+	}
+	var k kinder
+	if errors.As(err, &k) {
+		return k.ErrorKind(), true
+	}
+	return "", false
+}
+
+// ClassifyContextError classifies context.Canceled and context.DeadlineExceeded
+func ClassifyContextError(err error) (ErrorKind, bool) {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return ErrorKindCanceled, true
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrorKindTimeout, true
+	default:
+		return "", false
+	}
+}
+
+// ClassifyTimeoutError classifies errors in the chain which implement Timeout() bool (such as net.Error)
+func ClassifyTimeoutError(err error) (ErrorKind, bool) {
+	type timeout interface {
+		Timeout() bool
+	}
+	var t timeout
+	if errors.As(err, &t) && t.Timeout() {
+		return ErrorKindTimeout, true
+	}
+	return "", false
+}
+
+// ClassifyNotFoundError classifies os.ErrNotExist and sql.ErrNoRows
+func ClassifyNotFoundError(err error) (ErrorKind, bool) {
+	if errors.Is(err, os.ErrNotExist) || errors.Is(err, sql.ErrNoRows) {
+		return ErrorKindNotFound, true
+	}
+	return "", false
+}
+
+// ClassifyConflictError classifies os.ErrExist
+func ClassifyConflictError(err error) (ErrorKind, bool) {
+	if errors.Is(err, os.ErrExist) {
+		return ErrorKindConflict, true
+	}
+	return "", false
+}
@@ -0,0 +1,89 @@
+package log_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+type kindError struct{}
+
+func (e kindError) Error() string {
+	return "kind error"
+}
+
+func (e kindError) ErrorKind() log.ErrorKind {
+	return "quota"
+}
+
+func Test_ClassifyError(t *testing.T) {
+
+	_, timeoutErr := net.DialTimeout("tcp", "10.255.255.1:80", 1)
+
+	type test struct {
+		name     string
+		err      error
+		expected log.ErrorKind
+	}
+
+	var tests = []test{
+		{"nil", nil, ""},
+		{"canceled", fmt.Errorf("request: %w", context.Canceled), log.ErrorKindCanceled},
+		{"deadline", context.DeadlineExceeded, log.ErrorKindTimeout},
+		{"net-timeout", timeoutErr, log.ErrorKindTimeout},
+		{"not-exist", fmt.Errorf("open: %w", os.ErrNotExist), log.ErrorKindNotFound},
+		{"no-rows", sql.ErrNoRows, log.ErrorKindNotFound},
+		{"exist", os.ErrExist, log.ErrorKindConflict},
+		{"custom-kind", fmt.Errorf("wrapped: %w", kindError{}), "quota"},
+		{"internal", errors.New("boom"), log.ErrorKindInternal},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := log.ClassifyError(tc.err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+
+}
+
+func Test_ClassifyError_CustomClassifier(t *testing.T) {
+
+	oldClassifiers := log.ErrorClassifiers
+	defer func() {
+		log.ErrorClassifiers = oldClassifiers
+	}()
+
+	errConflict := errors.New("version mismatch")
+
+	log.ErrorClassifiers = append([]log.ErrorClassifier{
+		func(err error) (log.ErrorKind, bool) {
+			return log.ErrorKindConflict, errors.Is(err, errConflict)
+		},
+	}, log.ErrorClassifiers...)
+
+	assert.Equal(t, log.ErrorKindConflict, log.ClassifyError(errConflict), "custom")
+	assert.Equal(t, log.ErrorKindCanceled, log.ClassifyError(context.Canceled), "built-in")
+
+}
+
+func Test_ErrorClassified(t *testing.T) {
+
+	resetLogConfig()
+	stdout, stderr := redirectOutput()
+	defer resetLogOutput()
+
+	log.ErrorClassified(context.Canceled, "request failed:")
+
+	assert.Equal(t, "", stdout.String(), "stdout")
+	assert.Equal(t, "test | ERROR | request failed: context canceled error.kind=canceled\n", stderr.String(), "stderr")
+
+}
@@ -0,0 +1,123 @@
+// Command logverify verifies the signatures and sequence numbers of logs written with log.SigningKey and
+// log.SequenceNumbers
+//
+// Usage:
+//
+//	logverify [-key-file path] [-seq] [file ...]
+//
+// Rotated files are verified by passing them in the order they were written (oldest first), the standard input is
+// read when no files are given. It reports the first tampered or missing entry and exits with status 1 when the
+// verification fails.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strconv"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+
+	flags := flag.NewFlagSet("logverify", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	keyFile := flags.String("key-file", "", "path to the file containing the signing key")
+	sequence := flags.Bool("seq", false, "verify the continuity of the sequence numbers")
+
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	opts := log.VerifyOptions{Sequence: *sequence}
+	if *keyFile != "" {
+		key, err := ioutil.ReadFile(*keyFile)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 2
+		}
+		opts.Key = bytes.TrimRight(key, "\r\n")
+	}
+
+	files := flags.Args()
+
+	var r io.Reader = stdin
+	if len(files) > 0 {
+		readers := []io.Reader{}
+		for _, path := range files {
+			f, err := os.Open(path)
+			if err != nil {
+				fmt.Fprintln(stderr, err)
+				return 2
+			}
+			defer f.Close()
+			readers = append(readers, f)
+		}
+		r = io.MultiReader(readers...)
+	}
+
+	report, err := log.VerifyLog(r, opts)
+	if err != nil {
+		fmt.Fprintln(stdout, "FAIL:", locate(files, err))
+		return 1
+	}
+
+	fmt.Fprintf(stdout, "OK: %d entries, %d restarts\n", report.Entries, report.Restarts)
+	return 0
+
+}
+
+var lineError = regexp.MustCompile(`^line (\d+): `)
+
+// locate replaces the line number counted over all files in the error with the file and its line number
+func locate(files []string, err error) string {
+
+	m := lineError.FindStringSubmatch(err.Error())
+	if m == nil || len(files) == 0 {
+		return err.Error()
+	}
+
+	line, _ := strconv.Atoi(m[1])
+	rest := err.Error()[len(m[0]):]
+
+	for _, path := range files {
+		n := countLines(path)
+		if line <= n || path == files[len(files)-1] {
+			return fmt.Sprintf("%s:%d: %s", path, line, rest)
+		}
+		line -= n
+	}
+
+	return err.Error()
+
+}
+
+func countLines(path string) int {
+
+	f, err := os.Open(path)
+	if err != nil {
+		return 0
+	}
+	defer f.Close()
+
+	n := 0
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024*1024)
+	for scanner.Scan() {
+		n++
+	}
+
+	return n
+
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+// writeRotatedLog writes the messages from a single process to path, rotating it after the first half
+func writeRotatedLog(t *testing.T, path string, rotated string, messages ...string) {
+
+	buf := bytes.NewBufferString("")
+	log.Stdout = buf
+	defer func() {
+		log.Stdout = os.Stdout
+	}()
+
+	for _, message := range messages {
+		log.Info(message)
+	}
+
+	lines := strings.SplitAfter(buf.String(), "\n")
+	half := len(messages) / 2
+
+	assert.NoError(t, ioutil.WriteFile(rotated, []byte(strings.Join(lines[:half], "")), 0644), "write-rotated")
+	assert.NoError(t, ioutil.WriteFile(path, []byte(strings.Join(lines[half:], "")), 0644), "write")
+
+}
+
+func Test_run(t *testing.T) {
+
+	dir, _ := ioutil.TempDir("", "logverify")
+	defer os.RemoveAll(dir)
+
+	keyFile := filepath.Join(dir, "key")
+	ioutil.WriteFile(keyFile, []byte("secret\n"), 0600)
+
+	log.SigningKey = []byte("secret")
+	log.SequenceNumbers = true
+	defer func() {
+		log.SigningKey = nil
+		log.SequenceNumbers = false
+	}()
+
+	rotated := filepath.Join(dir, "app-1.log")
+	current := filepath.Join(dir, "app.log")
+	writeRotatedLog(t, current, rotated, "first", "second", "third", "fourth")
+
+	tampered := filepath.Join(dir, "tampered.log")
+	data, _ := ioutil.ReadFile(current)
+	ioutil.WriteFile(tampered, []byte(strings.Replace(string(data), "fourth", "altered", 1)), 0644)
+
+	type test struct {
+		name     string
+		args     []string
+		stdin    string
+		expected string
+		code     int
+	}
+
+	var tests = []test{
+		{"files", []string{"-key-file", keyFile, "-seq", rotated, current}, "", "OK: 4 entries, 1 restarts\n", 0},
+		{"stdin", []string{"-key-file", keyFile}, string(data), "FAIL: line 1: signature mismatch\n", 1},
+		{"tampered", []string{"-key-file", keyFile, rotated, tampered}, "", "FAIL: " + tampered + ":2: signature mismatch\n", 1},
+		{"missing", []string{"-seq", current, rotated}, "", "FAIL: " + current + ":1: missing entries (expected seq 1, got 3)\n", 1},
+		{"nothing", []string{current}, "", "FAIL: nothing to verify\n", 1},
+		{"missing-file", []string{"-seq", filepath.Join(dir, "missing.log")}, "", "", 2},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			stdout := bytes.NewBufferString("")
+			stderr := bytes.NewBufferString("")
+
+			code := run(tc.args, strings.NewReader(tc.stdin), stdout, stderr)
+
+			assert.Equal(t, tc.code, code, "code")
+			assert.Equal(t, tc.expected, stdout.String(), "stdout")
+
+		})
+	}
+
+}
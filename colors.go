@@ -0,0 +1,45 @@
+package log
+
+import (
+	"io"
+	"os"
+)
+
+// ForceColors colors the levels even when the output is not a terminal (e.g. when piping to less -R)
+var ForceColors = false
+
+// DisableColors never colors the levels, it defaults to true when the NO_COLOR environment variable is set
+var DisableColors = os.Getenv("NO_COLOR") != ""
+
+// ColorLevels maps the levels to the ANSI escape sequence used to color them when they are written to a terminal
+//
+// The level column is colored, or the message itself when the entries don't include timestamps. Levels which are
+// highlighted (see HighlightLevels) are not colored.
+var ColorLevels = DefaultColorLevels()
+
+// DefaultColorLevels returns a mapping which shows debug messages in grey, warnings in yellow and errors in red
+func DefaultColorLevels() map[string]string {
+	return map[string]string{
+		"TRACE": "\x1b[90m",
+		"DEBUG": "\x1b[90m",
+		"INFO":  "\x1b[36m",
+		"NOTE":  "\x1b[32m",
+		"WARN":  "\x1b[33m",
+		"ERROR": "\x1b[31m",
+		"FATAL": "\x1b[1;31m",
+	}
+}
+
+// colorFor returns the ANSI escape sequence used for the level when writing to w (or an empty string)
+func colorFor(level string, w io.Writer) string {
+	if DisableColors {
+		return ""
+	}
+	if _, highlight := HighlightLevels[level]; highlight {
+		return ""
+	}
+	if !ForceColors && !isTerminal(w) {
+		return ""
+	}
+	return ColorLevels[level]
+}
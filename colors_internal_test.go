@@ -0,0 +1,57 @@
+package log
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_colorFor(t *testing.T) {
+
+	oldIsTerminal := isTerminal
+	defer func() {
+		isTerminal = oldIsTerminal
+		ForceColors = false
+		DisableColors = false
+		HighlightLevels = map[string]string{}
+	}()
+
+	type test struct {
+		name      string
+		level     string
+		terminal  bool
+		force     bool
+		disable   bool
+		highlight bool
+		expected  string
+	}
+
+	var tests = []test{
+		{"terminal", "WARN", true, false, false, false, "\x1b[33m"},
+		{"not-terminal", "WARN", false, false, false, false, ""},
+		{"force", "ERROR", false, true, false, false, "\x1b[31m"},
+		{"disable", "ERROR", true, false, true, false, ""},
+		{"disable-wins", "ERROR", true, true, true, false, ""},
+		{"highlighted", "ERROR", true, false, false, true, ""},
+		{"debug", "DEBUG", true, false, false, false, "\x1b[90m"},
+		{"unknown", "OTHER", true, false, false, false, ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			isTerminal = func(w io.Writer) bool {
+				return tc.terminal
+			}
+			ForceColors = tc.force
+			DisableColors = tc.disable
+			HighlightLevels = map[string]string{}
+			if tc.highlight {
+				HighlightLevels = DefaultHighlightLevels()
+			}
+			assert.Equal(t, tc.expected, colorFor(tc.level, &bytes.Buffer{}))
+		})
+	}
+
+}
@@ -0,0 +1,47 @@
+package log_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func Test_ForceColors(t *testing.T) {
+
+	resetLogConfig()
+	stdout, stderr := redirectOutput()
+	defer resetLogOutput()
+	defer func() {
+		log.ForceColors = false
+	}()
+
+	log.ForceColors = true
+
+	log.Warn("warn")
+	log.Error("error")
+
+	assert.Equal(t, "test | \x1b[33mWARN \x1b[0m | warn\n", stdout.String(), "stdout")
+	assert.Equal(t, "test | \x1b[31mERROR\x1b[0m | error\n", stderr.String(), "stderr")
+
+}
+
+func Test_ForceColors_WithoutTimestamp(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+	defer func() {
+		log.ForceColors = false
+	}()
+
+	log.ForceColors = true
+	log.PrintTimestamp = false
+	log.DebugMode = true
+
+	log.Debug("debug")
+
+	assert.Equal(t, "\x1b[90mdebug\x1b[0m\n", stdout.String())
+
+}
@@ -0,0 +1,321 @@
+package log
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+)
+
+// Config contains the settings which can be loaded from a JSON configuration file
+//
+// Level takes precedence over DebugMode and TraceMode, an empty Level keeps the current minimum level. The Outputs
+// replace the outputs of the previously applied config, the outputs added with AddOutput are kept.
+type Config struct {
+	DebugMode      bool            `json:"debug_mode"`
+	TraceMode      bool            `json:"trace_mode"`
+	DebugSQLMode   bool            `json:"debug_sql_mode"`
+	PrintTimestamp bool            `json:"print_timestamp"`
+	TimeFormat     string          `json:"time_format"`
+	TimeZone       string          `json:"time_zone"`
+	Level          string          `json:"level,omitempty"`
+	Format         string          `json:"format"`
+	Outputs        []OutputConfig  `json:"outputs"`
+	TransformRules []TransformRule `json:"transform_rules"`
+}
+
+// OutputConfig is a file to which the entries are written next to Stdout and Stderr, see AddOutput
+type OutputConfig struct {
+	Name     string `json:"name"`
+	Path     string `json:"path"`
+	Format   string `json:"format,omitempty"`
+	MinLevel string `json:"min_level,omitempty"`
+}
+
+type configOutput struct {
+	cfg  OutputConfig
+	opts OutputOptions
+	sink *FileSink
+}
+
+// configMutex serializes ApplyConfig and guards configOutputs, it's always locked before logMutex
+var configMutex = &sync.Mutex{}
+
+// configOutputs are the outputs added by the config applied last
+var configOutputs []configOutput
+
+// CurrentConfig returns the settings which are currently active
+func CurrentConfig() Config {
+
+	configMutex.Lock()
+	defer configMutex.Unlock()
+
+	logMutex.Lock()
+	defer logMutex.Unlock()
+
+	cfg := Config{
+		DebugMode:      DebugMode,
+		TraceMode:      TraceMode,
+		DebugSQLMode:   DebugSQLMode,
+		PrintTimestamp: PrintTimestamp,
+		TimeFormat:     TimeFormat,
+		Format:         Format.String(),
+		TransformRules: append([]TransformRule{}, TransformRules...),
+	}
+	if TimeZone != nil {
+		cfg.TimeZone = TimeZone.String()
+	}
+	for _, o := range configOutputs {
+		cfg.Outputs = append(cfg.Outputs, o.cfg)
+	}
+
+	return cfg
+
+}
+
+// LoadConfig reads the configuration file at path
+//
+// Settings which are not specified in the file keep their current value
+func LoadConfig(path string) (Config, error) {
+
+	cfg := CurrentConfig()
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return cfg, err
+	}
+
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, err
+	}
+
+	return cfg, nil
+
+}
+
+// ApplyConfig applies all settings from cfg at once and records each changed setting as a notice
+//
+// If the config is invalid or one of its outputs can't be opened, none of the settings are applied
+func ApplyConfig(cfg Config, source string) error {
+
+	location, err := time.LoadLocation(cfg.TimeZone)
+	if err != nil {
+		return err
+	}
+
+	level := GetLevel()
+	debugMode, traceMode := cfg.DebugMode, cfg.TraceMode
+	if cfg.Level != "" {
+		if level, err = ParseLevel(cfg.Level); err != nil {
+			return err
+		}
+		debugMode, traceMode = level <= DebugLevel, level <= TraceLevel
+	}
+
+	format := Format
+	if cfg.Format != "" {
+		if format, err = ParseFormat(cfg.Format); err != nil {
+			return err
+		}
+	}
+
+	rules, err := normalizeTransformRules(cfg.TransformRules)
+	if err != nil {
+		return err
+	}
+
+	configMutex.Lock()
+	defer configMutex.Unlock()
+
+	opened, err := openConfigOutputs(cfg.Outputs)
+	if err != nil {
+		return err
+	}
+
+	logMutex.Lock()
+
+	var changes []string
+	record := func(setting string, old interface{}, new interface{}) {
+		if message, changed := changeMessage(setting, old, new, source); changed {
+			changes = append(changes, message)
+		}
+	}
+
+	record("DebugMode", DebugMode, debugMode)
+	record("TraceMode", TraceMode, traceMode)
+	record("DebugSQLMode", DebugSQLMode, cfg.DebugSQLMode)
+	record("PrintTimestamp", PrintTimestamp, cfg.PrintTimestamp)
+	record("TimeFormat", TimeFormat, cfg.TimeFormat)
+	record("TimeZone", TimeZone, location)
+	if cfg.Level != "" {
+		record("Level", GetLevel(), level)
+	}
+	record("Format", Format, format)
+	record("Outputs", outputConfigs(configOutputs), outputConfigs(opened))
+	record("TransformRules", TransformRules, rules)
+
+	DebugMode = debugMode
+	TraceMode = traceMode
+	if cfg.Level != "" {
+		SetLevel(level)
+	}
+	DebugSQLMode = cfg.DebugSQLMode
+	PrintTimestamp = cfg.PrintTimestamp
+	TimeFormat = cfg.TimeFormat
+	TimeZone = location
+	Format = format
+	TransformRules = rules
+
+	closed := replaceConfigOutputs(opened)
+
+	logMutex.Unlock()
+
+	for _, sink := range closed {
+		if err := sink.Close(); err != nil {
+			Warn("Failed to close", sink.Path()+":", err)
+		}
+	}
+
+	for _, change := range changes {
+		Notice(change)
+	}
+
+	return nil
+
+}
+
+// openConfigOutputs opens the files of the outputs, the outputs which are already open are reused
+func openConfigOutputs(configs []OutputConfig) ([]configOutput, error) {
+
+	var opened []configOutput
+
+	fail := func(err error) ([]configOutput, error) {
+		for _, o := range opened {
+			if !containsSink(configOutputs, o.sink) {
+				o.sink.Close()
+			}
+		}
+		return nil, err
+	}
+
+	for _, oc := range configs {
+
+		opts := OutputOptions{Name: oc.Name}
+		var err error
+		if oc.Format != "" {
+			if opts.Format, err = ParseFormat(oc.Format); err != nil {
+				return fail(err)
+			}
+		}
+		if oc.MinLevel != "" {
+			if opts.MinLevel, err = ParseLevel(oc.MinLevel); err != nil {
+				return fail(err)
+			}
+		}
+
+		reused := false
+		for _, o := range configOutputs {
+			if o.cfg == oc {
+				opened = append(opened, o)
+				reused = true
+				break
+			}
+		}
+		if reused {
+			continue
+		}
+
+		sink, err := OpenFileSink(oc.Path, FileOptions{CreateDirs: true})
+		if err != nil {
+			return fail(err)
+		}
+		opened = append(opened, configOutput{cfg: oc, opts: opts, sink: sink})
+
+	}
+
+	return opened, nil
+
+}
+
+// replaceConfigOutputs replaces the outputs of the previous config and returns the sinks which are no longer used, it
+// must be called while holding logMutex
+func replaceConfigOutputs(opened []configOutput) []*FileSink {
+
+	var closed []*FileSink
+
+	for _, o := range configOutputs {
+		if !containsSink(opened, o.sink) {
+			removeOutput(o.sink)
+			closed = append(closed, o.sink)
+		}
+	}
+
+	for _, o := range opened {
+		if !containsSink(configOutputs, o.sink) {
+			addOutput(o.sink, o.opts)
+		}
+	}
+
+	configOutputs = opened
+
+	return closed
+
+}
+
+func containsSink(outputs []configOutput, sink *FileSink) bool {
+	for _, o := range outputs {
+		if o.sink == sink {
+			return true
+		}
+	}
+	return false
+}
+
+func outputConfigs(outputs []configOutput) []OutputConfig {
+	configs := []OutputConfig{}
+	for _, o := range outputs {
+		configs = append(configs, o.cfg)
+	}
+	return configs
+}
+
+// WatchConfig loads and applies the configuration file at path and reapplies it each time it changes
+//
+// The file is polled every interval. The returned function stops watching the file and waits for a reload in
+// progress. Errors while reloading the file are logged and the previous settings stay active.
+func WatchConfig(path string, interval time.Duration) (func(), error) {
+
+	source := "config file " + path
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := ApplyConfig(cfg, source); err != nil {
+		return nil, err
+	}
+
+	modTime, size := info.ModTime(), info.Size()
+
+	return poll(interval, func() {
+		info, err := os.Stat(path)
+		if err != nil || (info.ModTime().Equal(modTime) && info.Size() == size) {
+			return
+		}
+		modTime, size = info.ModTime(), info.Size()
+		cfg, err := LoadConfig(path)
+		if err == nil {
+			err = ApplyConfig(cfg, source)
+		}
+		if err != nil {
+			Error("Failed to reload", source+":", err)
+		}
+	}), nil
+
+}
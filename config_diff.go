@@ -0,0 +1,102 @@
+//go:build !tinygo
+// +build !tinygo
+
+package log
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ConfigChange describes a single field which differs between two configs
+type ConfigChange struct {
+	Path string
+	Old  interface{}
+	New  interface{}
+}
+
+// InfoConfigDiff prints the fields which differ between two configs (typically structs)
+//
+// Each changed field is added to the entry by its path (using the json tag names of nested structs), with a value
+// like "old -> new". Fields tagged with `log:"redact"` are shown as DefaultScrubReplacement and fields tagged with
+// `log:"-"` are ignored. Nothing is printed when the configs are equal.
+func InfoConfigDiff(old interface{}, new interface{}) {
+
+	changes := DiffConfig(old, new)
+	if len(changes) == 0 {
+		return
+	}
+
+	fields := make(map[string]interface{}, len(changes))
+	for _, change := range changes {
+		fields[change.Path] = fmt.Sprintf("%v -> %v", change.Old, change.New)
+	}
+
+	printEntry("INFO", "Config changed", fields)
+
+}
+
+// DiffConfig returns the fields which differ between two configs with the redacted fields masked
+func DiffConfig(old interface{}, new interface{}) []ConfigChange {
+	changes := []ConfigChange{}
+	diffValues("config", reflect.ValueOf(old), reflect.ValueOf(new), false, &changes)
+	return changes
+}
+
+func diffValues(path string, old reflect.Value, new reflect.Value, redact bool, changes *[]ConfigChange) {
+
+	old, new = indirectValue(old), indirectValue(new)
+
+	if old.IsValid() && new.IsValid() && old.Type() == new.Type() && old.Kind() == reflect.Struct {
+		for i := 0; i < old.NumField(); i++ {
+			field := old.Type().Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			tag := field.Tag.Get("log")
+			if tag == "-" {
+				continue
+			}
+			diffValues(path+"."+configFieldName(field), old.Field(i), new.Field(i), redact || tag == "redact", changes)
+		}
+		return
+	}
+
+	oldValue, newValue := interfaceOf(old), interfaceOf(new)
+	if reflect.DeepEqual(oldValue, newValue) {
+		return
+	}
+
+	if redact {
+		oldValue, newValue = DefaultScrubReplacement, DefaultScrubReplacement
+	}
+
+	*changes = append(*changes, ConfigChange{Path: path, Old: oldValue, New: newValue})
+
+}
+
+func indirectValue(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+func interfaceOf(v reflect.Value) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+	return v.Interface()
+}
+
+func configFieldName(field reflect.StructField) string {
+	name := strings.Split(field.Tag.Get("json"), ",")[0]
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
+}
@@ -0,0 +1,77 @@
+//go:build !tinygo
+// +build !tinygo
+
+package log_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+type testDatabaseConfig struct {
+	Host     string `json:"host"`
+	Password string `json:"password" log:"redact"`
+}
+
+type testServiceConfig struct {
+	Name     string             `json:"name"`
+	Timeout  time.Duration      `json:"timeout"`
+	Tags     []string           `json:"tags"`
+	Database testDatabaseConfig `json:"database"`
+	Cache    *testDatabaseConfig
+	Internal string `log:"-"`
+	private  string
+}
+
+func Test_DiffConfig(t *testing.T) {
+
+	old := testServiceConfig{
+		Name:     "api",
+		Timeout:  5 * time.Second,
+		Tags:     []string{"a"},
+		Database: testDatabaseConfig{Host: "db1", Password: "old"},
+		Internal: "x",
+		private:  "x",
+	}
+
+	new := old
+	new.Timeout = 10 * time.Second
+	new.Tags = []string{"a", "b"}
+	new.Database = testDatabaseConfig{Host: "db2", Password: "new"}
+	new.Cache = &testDatabaseConfig{Host: "cache"}
+	new.Internal = "y"
+	new.private = "y"
+
+	changes := log.DiffConfig(old, &new)
+
+	assert.Equal(t, []log.ConfigChange{
+		{Path: "config.timeout", Old: 5 * time.Second, New: 10 * time.Second},
+		{Path: "config.tags", Old: []string{"a"}, New: []string{"a", "b"}},
+		{Path: "config.database.host", Old: "db1", New: "db2"},
+		{Path: "config.database.password", Old: "[REDACTED]", New: "[REDACTED]"},
+		{Path: "config.Cache", Old: nil, New: testDatabaseConfig{Host: "cache"}},
+	}, changes)
+
+	assert.Empty(t, log.DiffConfig(old, old), "equal")
+	assert.Equal(t, []log.ConfigChange{{Path: "config", Old: 1, New: "1"}}, log.DiffConfig(1, "1"), "types")
+
+}
+
+func Test_InfoConfigDiff(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+
+	old := testDatabaseConfig{Host: "db1", Password: "old"}
+
+	log.InfoConfigDiff(old, old)
+	log.InfoConfigDiff(old, testDatabaseConfig{Host: "db2", Password: "new"})
+
+	assert.Equal(t, "test | INFO  | Config changed config.host=\"db1 -> db2\" config.password=\"[REDACTED] -> [REDACTED]\"\n", stdout.String())
+
+}
@@ -0,0 +1,222 @@
+package log_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func writeConfigFile(t *testing.T, dir string, contents string) string {
+	path := filepath.Join(dir, "log.json")
+	err := ioutil.WriteFile(path, []byte(contents), 0644)
+	assert.NoError(t, err, "write-config")
+	return path
+}
+
+func Test_LoadConfig(t *testing.T) {
+
+	resetLogConfig()
+	redirectOutput()
+	defer resetLogOutput()
+
+	dir, _ := ioutil.TempDir("", "go-log")
+	defer os.RemoveAll(dir)
+
+	path := writeConfigFile(t, dir, `{"debug_mode": true, "time_zone": "UTC"}`)
+
+	cfg, err := log.LoadConfig(path)
+
+	assert.NoError(t, err, "error")
+	assert.True(t, cfg.DebugMode, "debug-mode")
+	assert.False(t, cfg.DebugSQLMode, "debug-sql-mode")
+	assert.True(t, cfg.PrintTimestamp, "print-timestamp")
+	assert.Equal(t, log.TestingTimeFormat, cfg.TimeFormat, "time-format")
+	assert.Equal(t, "UTC", cfg.TimeZone, "time-zone")
+
+}
+
+func Test_LoadConfig_Invalid(t *testing.T) {
+
+	dir, _ := ioutil.TempDir("", "go-log")
+	defer os.RemoveAll(dir)
+
+	_, err := log.LoadConfig(writeConfigFile(t, dir, `{`))
+	assert.Error(t, err, "invalid-json")
+
+	_, err = log.LoadConfig(filepath.Join(dir, "missing.json"))
+	assert.Error(t, err, "missing-file")
+
+}
+
+func Test_ApplyConfig(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+
+	cfg := log.CurrentConfig()
+	cfg.DebugMode = true
+	cfg.TimeZone = "UTC"
+
+	err := log.ApplyConfig(cfg, "test")
+
+	assert.NoError(t, err, "error")
+	assert.True(t, log.DebugMode, "debug-mode")
+	assert.Equal(t, time.UTC, log.TimeZone, "time-zone")
+
+	expected := "test | NOTE  | DebugMode changed from \"false\" to \"true\" by test\n" +
+		"test | NOTE  | TimeZone changed from \"Europe/Brussels\" to \"UTC\" by test\n"
+	assert.Equal(t, expected, stdout.String(), "stdout")
+
+}
+
+func Test_ApplyConfig_InvalidTimeZone(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+
+	cfg := log.CurrentConfig()
+	cfg.DebugMode = true
+	cfg.TimeZone = "Invalid/Zone"
+
+	err := log.ApplyConfig(cfg, "test")
+
+	assert.Error(t, err, "error")
+	assert.False(t, log.DebugMode, "debug-mode")
+	assert.Equal(t, "", stdout.String(), "stdout")
+
+}
+
+func Test_ApplyConfig_LevelOutputsRules(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+
+	dir, _ := ioutil.TempDir("", "go-log")
+	defer os.RemoveAll(dir)
+
+	output := filepath.Join(dir, "logs", "app.log")
+	path := writeConfigFile(t, dir, `{
+		"level": "warning",
+		"time_zone": "Europe/Brussels",
+		"outputs": [{"name": "app", "path": "`+filepath.ToSlash(output)+`", "format": "logfmt", "min_level": "error"}],
+		"transform_rules": [{"name": "health", "match": {"fields": {"path": "/health"}}, "drop": true}]
+	}`)
+
+	cfg, err := log.LoadConfig(path)
+	assert.NoError(t, err, "load")
+
+	err = log.ApplyConfig(cfg, "test")
+	assert.NoError(t, err, "apply")
+	defer func() {
+		log.TransformRules = []log.TransformRule{}
+	}()
+
+	assert.Equal(t, log.WarnLevel, log.GetLevel(), "level")
+	assert.Equal(t, cfg.Outputs, log.CurrentConfig().Outputs, "outputs")
+
+	log.Info("hidden")
+	log.Warn("shown")
+	log.WithField("path", "/health").Warn("dropped")
+	log.Error("failed")
+
+	assert.Contains(t, stdout.String(), "test | WARN  | shown\n", "shown")
+	assert.NotContains(t, stdout.String(), "hidden", "level")
+	assert.NotContains(t, stdout.String(), "dropped", "rules")
+
+	cfg.Level = "info"
+	cfg.Outputs = nil
+	cfg.TransformRules = nil
+	err = log.ApplyConfig(cfg, "test")
+	assert.NoError(t, err, "reset")
+
+	log.Error("not-written")
+
+	data, err := ioutil.ReadFile(output)
+	assert.NoError(t, err, "read")
+	assert.Regexp(t, `(?m)^level=error .*msg=failed$`, string(data), "output")
+	assert.NotContains(t, string(data), "msg=shown", "min-level")
+	assert.NotContains(t, string(data), "not-written", "removed")
+	assert.Contains(t, stdout.String(), "Outputs changed from", "notice")
+
+}
+
+func Test_ApplyConfig_InvalidLevel(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+
+	cfg := log.CurrentConfig()
+	cfg.DebugMode = true
+	cfg.Level = "verbose"
+
+	err := log.ApplyConfig(cfg, "test")
+
+	assert.Error(t, err, "error")
+	assert.False(t, log.DebugMode, "debug-mode")
+	assert.Equal(t, "", stdout.String(), "stdout")
+
+}
+
+func Test_ApplyConfig_InvalidTransformRule(t *testing.T) {
+
+	resetLogConfig()
+	redirectOutput()
+	defer resetLogOutput()
+
+	cfg := log.CurrentConfig()
+	cfg.TransformRules = []log.TransformRule{{Name: "louder", SetLevel: "loud"}}
+
+	err := log.ApplyConfig(cfg, "test")
+
+	assert.EqualError(t, err, `transform rule "louder": unknown level: "loud"`, "error")
+	assert.Empty(t, log.TransformRules, "rules")
+
+}
+
+func Test_WatchConfig(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+
+	dir, _ := ioutil.TempDir("", "go-log")
+	defer os.RemoveAll(dir)
+
+	path := writeConfigFile(t, dir, `{"debug_mode": true}`)
+
+	stop, err := log.WatchConfig(path, 10*time.Millisecond)
+	assert.NoError(t, err, "error")
+	defer stop()
+
+	assert.True(t, log.CurrentConfig().DebugMode, "initial")
+
+	writeConfigFile(t, dir, `{"debug_mode": false, "debug_sql_mode": true}`)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) && !log.CurrentConfig().DebugSQLMode {
+		time.Sleep(10 * time.Millisecond)
+	}
+	stop()
+
+	cfg := log.CurrentConfig()
+	assert.False(t, cfg.DebugMode, "debug-mode")
+	assert.True(t, cfg.DebugSQLMode, "debug-sql-mode")
+	assert.True(t, strings.Contains(stdout.String(), "DebugSQLMode changed from \"false\" to \"true\" by config file "+path), "stdout")
+
+}
+
+func Test_WatchConfig_Missing(t *testing.T) {
+	_, err := log.WatchConfig("missing.json", time.Second)
+	assert.Error(t, err)
+}
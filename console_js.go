@@ -0,0 +1,50 @@
+//go:build js && wasm
+// +build js,wasm
+
+package log
+
+import (
+	"bytes"
+	"syscall/js"
+)
+
+// consoleMethods maps the levels to the methods of the browser console
+var consoleMethods = map[string]string{
+	"TRACE": "debug",
+	"DEBUG": "debug",
+	"INFO":  "info",
+	"NOTE":  "info",
+	"WARN":  "warn",
+	"ERROR": "error",
+	"FATAL": "error",
+}
+
+// ConsoleWriter is a writer which forwards the log entries to the browser console (js/wasm only)
+//
+// The levels are mapped to console.debug, console.info, console.warn and console.error. The console shows the
+// level and the time of each message, so the entries don't include them, regardless of PrintTimestamp.
+type ConsoleWriter struct{}
+
+// ToConsole uses a ConsoleWriter as Stdout and Stderr
+func ToConsole() {
+	Stdout = ConsoleWriter{}
+	Stderr = ConsoleWriter{}
+}
+
+// Write forwards p to console.info
+func (w ConsoleWriter) Write(p []byte) (int, error) {
+	return w.writeLevel("INFO", p)
+}
+
+func (w ConsoleWriter) printTimestamp() bool {
+	return false
+}
+
+func (w ConsoleWriter) writeLevel(level string, p []byte) (int, error) {
+	method, ok := consoleMethods[level]
+	if !ok {
+		method = "log"
+	}
+	js.Global().Get("console").Call(method, string(bytes.TrimRight(p, "\n")))
+	return len(p), nil
+}
@@ -0,0 +1,54 @@
+//go:build js && wasm
+// +build js,wasm
+
+package log_test
+
+import (
+	"syscall/js"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func Test_ToConsole(t *testing.T) {
+
+	resetLogConfig()
+	defer resetLogOutput()
+
+	calls := []string{}
+
+	fake := js.Global().Get("Object").New()
+	for _, method := range []string{"log", "debug", "info", "warn", "error"} {
+		method := method
+		fn := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			calls = append(calls, method+": "+args[0].String())
+			return nil
+		})
+		defer fn.Release()
+		fake.Set(method, fn)
+	}
+
+	console := js.Global().Get("console")
+	js.Global().Set("console", fake)
+	defer js.Global().Set("console", console)
+
+	log.ToConsole()
+	log.DebugMode = true
+
+	log.Debug("debug")
+	log.Info("info")
+	log.Notice("notice")
+	log.Warn("warn")
+	log.Error("error")
+
+	assert.Equal(t, []string{
+		"debug: debug",
+		"info: info",
+		"info: notice",
+		"warn: warn",
+		"error: error",
+	}, calls)
+
+}
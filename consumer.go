@@ -0,0 +1,131 @@
+package log
+
+import (
+	"fmt"
+	"time"
+)
+
+// ConsumedMessage contains the metadata of a message consumed from a broker such as Kafka or AMQP
+//
+// Zero values are left out of the logged fields.
+type ConsumedMessage struct {
+	System      string
+	Topic       string
+	Partition   int32
+	Offset      int64
+	Key         string
+	ID          string
+	Lag         int64
+	Exchange    string
+	RoutingKey  string
+	DeliveryTag uint64
+	Redelivered bool
+	Timestamp   time.Time
+}
+
+// KafkaMessage returns the metadata of a Kafka message, the lag is computed from the high water mark of the
+// partition (0 if unknown) and the ID is the "topic/partition/offset" of the message
+func KafkaMessage(topic string, partition int32, offset int64, key []byte, highWaterMark int64) ConsumedMessage {
+
+	msg := ConsumedMessage{
+		System:    "kafka",
+		Topic:     topic,
+		Partition: partition,
+		Offset:    offset,
+		Key:       string(key),
+		ID:        fmt.Sprintf("%s/%d/%d", topic, partition, offset),
+	}
+
+	if highWaterMark > offset {
+		msg.Lag = highWaterMark - offset - 1
+	}
+
+	return msg
+
+}
+
+// AMQPMessage returns the metadata of an AMQP delivery, the message ID defaults to the delivery tag
+func AMQPMessage(queue string, exchange string, routingKey string, messageID string, deliveryTag uint64, redelivered bool) ConsumedMessage {
+
+	if messageID == "" {
+		messageID = fmt.Sprintf("%s/%d", queue, deliveryTag)
+	}
+
+	return ConsumedMessage{
+		System:      "amqp",
+		Topic:       queue,
+		ID:          messageID,
+		Exchange:    exchange,
+		RoutingKey:  routingKey,
+		DeliveryTag: deliveryTag,
+		Redelivered: redelivered,
+	}
+
+}
+
+// Consume runs handler for the message and returns its error
+//
+// The metadata of the message is logged as debug before handler runs. The outcome is logged as info, or as an error
+// when handler fails, with the "message.id" field to correlate both entries.
+func Consume(msg ConsumedMessage, handler func() error) error {
+
+	if DebugMode || isRecording() {
+		printEntry("DEBUG", "Message received", msg.fields())
+	}
+
+	start := time.Now()
+	err := handler()
+
+	fields := map[string]interface{}{
+		"messaging.system": msg.System,
+		"message.topic":    msg.Topic,
+		"message.id":       msg.ID,
+		"duration":         time.Since(start),
+	}
+
+	if err != nil {
+		fields["error"] = err.Error()
+		printEntry("ERROR", "Message processing failed", fields)
+		return err
+	}
+
+	printEntry("INFO", "Message processed", fields)
+	return nil
+
+}
+
+func (msg ConsumedMessage) fields() map[string]interface{} {
+
+	fields := map[string]interface{}{
+		"messaging.system": msg.System,
+		"message.topic":    msg.Topic,
+		"message.id":       msg.ID,
+	}
+
+	if msg.System == "kafka" || msg.Partition != 0 || msg.Offset != 0 {
+		fields["message.partition"] = msg.Partition
+		fields["message.offset"] = msg.Offset
+		fields["message.lag"] = msg.Lag
+	}
+	if msg.Key != "" {
+		fields["message.key"] = msg.Key
+	}
+	if msg.Exchange != "" {
+		fields["message.exchange"] = msg.Exchange
+	}
+	if msg.RoutingKey != "" {
+		fields["message.routing_key"] = msg.RoutingKey
+	}
+	if msg.DeliveryTag != 0 {
+		fields["message.delivery_tag"] = msg.DeliveryTag
+	}
+	if msg.Redelivered {
+		fields["message.redelivered"] = true
+	}
+	if !msg.Timestamp.IsZero() {
+		fields["message.timestamp"] = msg.Timestamp.Format(time.RFC3339Nano)
+	}
+
+	return fields
+
+}
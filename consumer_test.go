@@ -0,0 +1,83 @@
+package log_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func Test_KafkaMessage(t *testing.T) {
+
+	msg := log.KafkaMessage("orders", 2, 41, []byte("customer-1"), 50)
+
+	assert.Equal(t, log.ConsumedMessage{
+		System:    "kafka",
+		Topic:     "orders",
+		Partition: 2,
+		Offset:    41,
+		Key:       "customer-1",
+		ID:        "orders/2/41",
+		Lag:       8,
+	}, msg)
+
+	assert.Equal(t, int64(0), log.KafkaMessage("orders", 0, 10, nil, 0).Lag, "unknown-lag")
+
+}
+
+func Test_AMQPMessage(t *testing.T) {
+	assert.Equal(t, "msg-1", log.AMQPMessage("emails", "", "", "msg-1", 7, false).ID, "message-id")
+	assert.Equal(t, "emails/7", log.AMQPMessage("emails", "", "", "", 7, false).ID, "delivery-tag")
+}
+
+func Test_Consume(t *testing.T) {
+
+	type test struct {
+		name   string
+		msg    log.ConsumedMessage
+		err    error
+		stdout string
+		stderr string
+	}
+
+	var tests = []test{
+		{
+			"kafka-success",
+			log.KafkaMessage("orders", 2, 41, []byte("customer-1"), 50),
+			nil,
+			`^test \| DEBUG \| Message received message\.id=orders/2/41 message\.key=customer-1 message\.lag=8 message\.offset=41 message\.partition=2 message\.topic=orders messaging\.system=kafka\n` +
+				`test \| INFO  \| Message processed duration=\S+ message\.id=orders/2/41 message\.topic=orders messaging\.system=kafka\n$`,
+			`^$`,
+		},
+		{
+			"amqp-failure",
+			log.AMQPMessage("emails", "notifications", "email.send", "msg-1", 7, true),
+			errors.New("smtp down"),
+			`^test \| DEBUG \| Message received message\.delivery_tag=7 message\.exchange=notifications message\.id=msg-1 message\.redelivered=true message\.routing_key=email\.send message\.topic=emails messaging\.system=amqp\n$`,
+			`^test \| ERROR \| Message processing failed duration=\S+ error="smtp down" message\.id=msg-1 message\.topic=emails messaging\.system=amqp\n$`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			resetLogConfig()
+			stdout, stderr := redirectOutput()
+			defer resetLogOutput()
+
+			log.DebugMode = true
+
+			err := log.Consume(tc.msg, func() error {
+				return tc.err
+			})
+
+			assert.Equal(t, tc.err, err, "error")
+			assert.Regexp(t, tc.stdout, stdout.String(), "stdout")
+			assert.Regexp(t, tc.stderr, stderr.String(), "stderr")
+
+		})
+	}
+
+}
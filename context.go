@@ -0,0 +1,87 @@
+package log
+
+import (
+	"context"
+)
+
+// RequestIDField is the field which holds the request ID of the context, see FromContext
+const RequestIDField = "request_id"
+
+type loggerKey struct{}
+
+// NewContext returns a copy of ctx carrying the logger
+func NewContext(ctx context.Context, logger *Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext returns the logger carried by ctx (or the default logger if there is none)
+//
+// When ctx carries a request ID (see ContextWithRequestID), it's added to the fields of the logger as
+// RequestIDField. The IDs of a span (see ContextWithSpan) are added as TraceIDField and SpanIDField.
+func FromContext(ctx context.Context) *Logger {
+
+	if ctx == nil {
+		return std
+	}
+
+	logger, _ := ctx.Value(loggerKey{}).(*Logger)
+	if logger == nil {
+		logger = std
+	}
+
+	traceID, spanID := SpanFromContext(ctx)
+	ids := map[string]string{
+		RequestIDField: RequestIDFromContext(ctx),
+		TraceIDField:   traceID,
+		SpanIDField:    spanID,
+	}
+
+	fields := map[string]interface{}{}
+	for key, id := range ids {
+		if _, ok := logger.fields[key]; !ok && id != "" {
+			fields[key] = id
+		}
+	}
+
+	if len(fields) > 0 {
+		logger = logger.WithFields(fields)
+	}
+
+	return logger
+
+}
+
+// TraceCtx prints a trace message using the logger carried by ctx
+func TraceCtx(ctx context.Context, args ...interface{}) {
+	FromContext(ctx).Trace(args...)
+}
+
+// DebugCtx prints a debug message using the logger carried by ctx
+func DebugCtx(ctx context.Context, args ...interface{}) {
+	FromContext(ctx).Debug(args...)
+}
+
+// InfoCtx prints an info message using the logger carried by ctx
+func InfoCtx(ctx context.Context, args ...interface{}) {
+	FromContext(ctx).Info(args...)
+}
+
+// NoticeCtx prints a notice message using the logger carried by ctx
+func NoticeCtx(ctx context.Context, args ...interface{}) {
+	FromContext(ctx).Notice(args...)
+}
+
+// WarnCtx prints a warning message using the logger carried by ctx
+func WarnCtx(ctx context.Context, args ...interface{}) {
+	FromContext(ctx).Warn(args...)
+}
+
+// ErrorCtx prints an error message using the logger carried by ctx
+func ErrorCtx(ctx context.Context, args ...interface{}) {
+	FromContext(ctx).Error(args...)
+}
+
+// FatalCtx logs a fatal error message using the logger carried by ctx and exits the program with exit code 1
+func FatalCtx(ctx context.Context, args ...interface{}) {
+	FromContext(ctx).Fatal(args...)
+}
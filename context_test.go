@@ -0,0 +1,88 @@
+package log_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func Test_FromContext(t *testing.T) {
+
+	resetLogConfig()
+	stdout, stderr := redirectOutput()
+	defer resetLogOutput()
+
+	ctx := log.NewContext(context.Background(), log.WithField("trace_id", "t-1"))
+
+	log.InfoCtx(ctx, "info")
+	log.WarnCtx(ctx, "warn")
+	log.ErrorCtx(ctx, "error")
+	log.InfoCtx(context.Background(), "plain")
+
+	assert.Equal(t, "test | INFO  | info trace_id=t-1\ntest | WARN  | warn trace_id=t-1\ntest | INFO  | plain\n", stdout.String(), "stdout")
+	assert.Equal(t, "test | ERROR | error trace_id=t-1\n", stderr.String(), "stderr")
+
+}
+
+func Test_FromContext_Default(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+
+	log.DebugMode = true
+
+	log.DebugCtx(context.Background(), "debug")
+	log.FromContext(nil).Notice("notice")
+
+	assert.Equal(t, "test | DEBUG | debug\ntest | NOTE  | notice\n", stdout.String())
+
+}
+
+func Test_FromContext_RequestID(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+
+	ctx := log.ContextWithRequestID(context.Background(), "req-1")
+	log.InfoCtx(ctx, "implicit")
+
+	ctx = log.NewContext(ctx, log.WithField(log.RequestIDField, "explicit"))
+	log.InfoCtx(ctx, "explicit")
+
+	assert.Equal(t, "test | INFO  | implicit request_id=req-1\ntest | INFO  | explicit request_id=explicit\n", stdout.String())
+
+}
+
+func Test_FromContext_Span(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+
+	ctx := log.ContextWithSpan(context.Background(), "t-1", "s-1")
+	log.InfoCtx(ctx, "span")
+
+	assert.Equal(t, "test | INFO  | span span_id=s-1 trace_id=t-1\n", stdout.String())
+
+}
+
+func Test_FromContext_Instance(t *testing.T) {
+
+	resetLogConfig()
+	defer resetLogOutput()
+
+	stdout := &bytes.Buffer{}
+	logger := log.New(log.WithOutput(stdout, stdout), log.WithTraceMode(true))
+
+	ctx := log.NewContext(context.Background(), logger)
+	log.TraceCtx(ctx, "trace")
+
+	assert.Equal(t, "trace\n", stdout.String())
+
+}
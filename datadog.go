@@ -0,0 +1,85 @@
+package log
+
+import (
+	"encoding/json"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DatadogService is the service name added to the Datadog formatted entries (defaults to $DD_SERVICE)
+var DatadogService = os.Getenv("DD_SERVICE")
+
+// DatadogEnv is the environment added to the Datadog formatted entries (defaults to $DD_ENV)
+var DatadogEnv = os.Getenv("DD_ENV")
+
+// DatadogVersion is the version added to the Datadog formatted entries (defaults to $DD_VERSION)
+var DatadogVersion = os.Getenv("DD_VERSION")
+
+// datadogTraceFields are the names of the fields which correlate the entries with the traces in Datadog APM
+var datadogTraceFields = map[string]string{
+	TraceIDField: "dd.trace_id",
+	SpanIDField:  "dd.span_id",
+}
+
+var datadogStatuses = map[string]string{
+	"TRACE": "debug",
+	"DEBUG": "debug",
+	"INFO":  "info",
+	"NOTE":  "notice",
+	"WARN":  "warn",
+	"ERROR": "error",
+	"FATAL": "critical",
+}
+
+func formatDatadog(e entry, timestamp bool) []byte {
+
+	status, ok := datadogStatuses[e.level]
+	if !ok {
+		status = strings.ToLower(e.level)
+	}
+
+	attributes := map[string]interface{}{}
+	for key, value := range e.fields {
+		attributes[key] = value
+	}
+
+	for field, key := range datadogTraceFields {
+		if id, ok := attributes[field]; ok {
+			attributes[key] = id
+			delete(attributes, field)
+		}
+	}
+
+	if timestamp {
+		attributes["timestamp"] = e.time.UTC().Format(time.RFC3339Nano)
+	}
+	attributes["status"] = status
+	attributes["message"] = e.message
+	attributes[SchemaVersionField] = SchemaVersion
+
+	if DatadogService != "" {
+		attributes["service"] = DatadogService
+	}
+	if DatadogEnv != "" {
+		attributes["env"] = DatadogEnv
+	}
+	if DatadogVersion != "" {
+		attributes["version"] = DatadogVersion
+	}
+	if e.caller != "" {
+		attributes["logger.method_name"] = e.caller
+	}
+	if e.goroutine != 0 {
+		attributes["logger.thread_name"] = strconv.FormatUint(e.goroutine, 10)
+	}
+
+	data, err := json.Marshal(applyAllowList(attributes, "timestamp", "status", "message", SchemaVersionField, EntryIDField, "dd.trace_id", "dd.span_id"))
+	if err != nil {
+		data, _ = json.Marshal(map[string]string{"status": "error", "message": err.Error()})
+	}
+
+	return append(data, '\n')
+
+}
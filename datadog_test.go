@@ -0,0 +1,121 @@
+package log_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func Test_FormatDatadog(t *testing.T) {
+
+	type test struct {
+		name           string
+		log            func()
+		expectedStatus string
+		stderr         bool
+	}
+
+	var tests = []test{
+		{"info", func() { log.Info("message") }, "info", false},
+		{"notice", func() { log.Notice("message") }, "notice", false},
+		{"warn", func() { log.Warn("message") }, "warn", false},
+		{"error", func() { log.Error("message") }, "error", true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			resetLogConfig()
+			stdout, stderr := redirectOutput()
+			defer resetLogOutput()
+
+			log.Format = log.FormatDatadog
+			log.DatadogService = "api"
+			log.DatadogEnv = "prod"
+			log.DatadogVersion = "1.2.3"
+			defer func() {
+				log.Format = log.FormatText
+				log.DatadogService = ""
+				log.DatadogEnv = ""
+				log.DatadogVersion = ""
+			}()
+
+			tc.log()
+
+			output := stdout.Bytes()
+			if tc.stderr {
+				output = stderr.Bytes()
+			}
+
+			var actual map[string]interface{}
+			err := json.Unmarshal(output, &actual)
+
+			assert.NoError(t, err, "json")
+			assert.Equal(t, tc.expectedStatus, actual["status"], "status")
+			assert.Equal(t, "message", actual["message"], "message")
+			assert.Equal(t, "api", actual["service"], "service")
+			assert.Equal(t, "prod", actual["env"], "env")
+			assert.Equal(t, "1.2.3", actual["version"], "version")
+
+			_, err = time.Parse(time.RFC3339Nano, actual["timestamp"].(string))
+			assert.NoError(t, err, "timestamp")
+
+		})
+	}
+
+}
+
+func Test_FormatDatadog_Caller(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+	defer resetCallerConfig()
+
+	log.Format = log.FormatDatadog
+	defer func() {
+		log.Format = log.FormatText
+	}()
+
+	log.ReportCaller = true
+	log.CallerFormat = log.CallerFunction
+	log.Info("message")
+
+	var actual map[string]interface{}
+	err := json.Unmarshal(stdout.Bytes(), &actual)
+
+	assert.NoError(t, err, "json")
+	assert.Equal(t, "go-log_test.Test_FormatDatadog_Caller", actual["logger.method_name"], "caller")
+	assert.NotContains(t, actual, "service", "service")
+
+}
+
+func Test_FormatDatadog_Span(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+
+	log.Format = log.FormatDatadog
+	defer func() {
+		log.Format = log.FormatText
+	}()
+
+	ctx := log.ContextWithSpan(context.Background(), "1234567890", "987654321")
+	log.InfoCtx(ctx, "message")
+
+	var actual map[string]interface{}
+	err := json.Unmarshal(stdout.Bytes(), &actual)
+
+	assert.NoError(t, err, "json")
+	assert.Equal(t, "1234567890", actual["dd.trace_id"], "trace-id")
+	assert.Equal(t, "987654321", actual["dd.span_id"], "span-id")
+	assert.NotContains(t, actual, log.TraceIDField, "trace-id-field")
+	assert.NotContains(t, actual, log.SpanIDField, "span-id-field")
+
+}
@@ -0,0 +1,56 @@
+package log
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// WriteRetries is the number of times a failed write of an entry is retried before it is given up
+var WriteRetries = 0
+
+// DeadLetter is the writer which receives the entries which could not be written after all retries (nil disables it)
+//
+// An entry is only written to it when all of its sinks (the stream of its level and the outputs added with
+// AddOutput) failed, so that replaying it doesn't duplicate it in the sinks which did get it.
+//
+// The entries are written as one JSON object per line in the same format as StartRecording, so they can be replayed
+// later with Replay or ReplayFile. A FileSink opened with OpenFileSink is the typical dead letter writer.
+var DeadLetter io.Writer
+
+// writeWithRetries must be called while holding logMutex
+func writeWithRetries(sinkName string, w io.Writer, level string, line []byte) (int, error) {
+
+	n, err := writeLevel(w, level, line)
+	for attempt := 0; err != nil && attempt < WriteRetries; attempt++ {
+		health.recordRetry(sinkName)
+		n, err = writeLevel(w, level, line)
+	}
+
+	return n, err
+
+}
+
+// writeDeadLetter must be called while holding logMutex
+func writeDeadLetter(e entry) {
+
+	if DeadLetter == nil {
+		return
+	}
+
+	data, err := json.Marshal(RecordedEntry{
+		Time:          e.time,
+		Level:         e.level,
+		Message:       e.message,
+		Fields:        e.fields,
+		Caller:        e.caller,
+		Goroutine:     e.goroutine,
+		SchemaVersion: SchemaVersion,
+	})
+	if err != nil {
+		data, _ = json.Marshal(RecordedEntry{Time: e.time, Level: e.level, Message: e.message, SchemaVersion: SchemaVersion})
+	}
+
+	n, err := DeadLetter.Write(append(data, '\n'))
+	health.recordWrite("dead-letter", e.time, n, err)
+
+}
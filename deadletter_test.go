@@ -0,0 +1,147 @@
+package log_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+type flakyWriter struct {
+	failures int
+	bytes.Buffer
+}
+
+func (w *flakyWriter) Write(p []byte) (int, error) {
+	if w.failures > 0 {
+		w.failures--
+		return 0, errors.New("write failed")
+	}
+	return w.Buffer.Write(p)
+}
+
+func resetDeadLetterConfig() {
+	log.DeadLetter = nil
+	log.WriteRetries = 0
+}
+
+func Test_DeadLetter(t *testing.T) {
+
+	resetLogConfig()
+	redirectOutput()
+	defer resetLogOutput()
+	defer resetDeadLetterConfig()
+
+	deadLetter := &bytes.Buffer{}
+	log.DeadLetter = deadLetter
+	log.Stderr = failingWriter{}
+
+	log.Info("delivered")
+	log.ErrorTemplate("lost {id}", map[string]interface{}{"id": 42})
+
+	var recorded log.RecordedEntry
+	err := json.Unmarshal(deadLetter.Bytes(), &recorded)
+
+	assert.NoError(t, err, "json")
+	assert.Equal(t, "ERROR", recorded.Level, "level")
+	assert.Equal(t, "lost 42", recorded.Message, "message")
+	assert.Equal(t, float64(42), recorded.Fields["id"], "fields")
+
+	stats, _ := log.SinkStatsFor("dead-letter")
+	assert.True(t, stats.Entries > 0, "stats")
+
+	stdout, stderr := redirectOutput()
+	count, err := log.Replay(deadLetter)
+
+	assert.NoError(t, err, "replay")
+	assert.Equal(t, 1, count, "replay-count")
+	assert.Equal(t, "", stdout.String(), "replay-stdout")
+	assert.Equal(t, "test | ERROR | lost 42 id=42 msg.template=\"lost {id}\"\n", stderr.String(), "replay-stderr")
+
+}
+
+func Test_WriteRetries(t *testing.T) {
+
+	type test struct {
+		name       string
+		retries    int
+		failures   int
+		stdout     string
+		deadLetter bool
+	}
+
+	var tests = []test{
+		{"no-retries", 0, 1, "", true},
+		{"recovered", 2, 2, "test | INFO  | message\n", false},
+		{"exhausted", 2, 3, "", true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			resetLogConfig()
+			redirectOutput()
+			defer resetLogOutput()
+			defer resetDeadLetterConfig()
+
+			stdout := &flakyWriter{failures: tc.failures}
+			deadLetter := &bytes.Buffer{}
+			log.Stdout = stdout
+			log.DeadLetter = deadLetter
+			log.WriteRetries = tc.retries
+
+			before, _ := log.SinkStatsFor("stdout")
+			log.Info("message")
+			after, _ := log.SinkStatsFor("stdout")
+
+			assert.Equal(t, tc.stdout, stdout.String(), "stdout")
+			assert.Equal(t, tc.deadLetter, deadLetter.Len() > 0, "dead-letter")
+			assert.Equal(t, uint64(tc.retries), after.Retries-before.Retries, "retries")
+
+		})
+	}
+
+}
+
+func Test_DeadLetter_Outputs(t *testing.T) {
+
+	type test struct {
+		name           string
+		stdoutFailures int
+		outputFailures int
+		deadLetter     bool
+	}
+
+	var tests = []test{
+		{"all-written", 0, 0, false},
+		{"stdout-failed", 1, 0, false},
+		{"output-failed", 0, 1, false},
+		{"all-failed", 1, 1, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			resetLogConfig()
+			redirectOutput()
+			defer resetLogOutput()
+			defer resetDeadLetterConfig()
+			defer log.RemoveOutputs()
+
+			deadLetter := &bytes.Buffer{}
+			log.Stdout = &flakyWriter{failures: tc.stdoutFailures}
+			log.DeadLetter = deadLetter
+			log.AddOutput(&flakyWriter{failures: tc.outputFailures}, log.OutputOptions{})
+
+			log.Info("message")
+
+			assert.Equal(t, tc.deadLetter, deadLetter.Len() > 0, "dead-letter")
+
+		})
+	}
+
+}
@@ -0,0 +1,190 @@
+//go:build !tinygo
+// +build !tinygo
+
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+// RecentLogs keeps the recent log entries served by the "/logs/recent" debug handler
+var RecentLogs = NewMemorySink(1 << 20)
+
+var recentLogsOnce sync.Once
+
+type levelSettings struct {
+	Level        string `json:"level"`
+	TraceMode    bool   `json:"trace_mode"`
+	DebugMode    bool   `json:"debug_mode"`
+	DebugSQLMode bool   `json:"debug_sql_mode"`
+}
+
+// DebugHandlers returns a mux with debug endpoints for an internal admin server:
+//
+//	/logs/recent  the recent log entries
+//	/logs/level   the current level and trace, debug and SQL modes (change them with POST or PUT and ?level=,
+//	              ?trace=, ?debug= or ?sql=)
+//	/logs/stats   the statistics of the outputs
+//	/logs/stream  the log entries as they are written, as server-sent events
+//
+// Recording the recent entries starts when DebugHandlers is called for the first time.
+func DebugHandlers() *http.ServeMux {
+
+	recentLogsOnce.Do(func() {
+		addTap(RecentLogs)
+	})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/logs/recent", serveRecentLogs)
+	mux.HandleFunc("/logs/level", serveLogLevel)
+	mux.HandleFunc("/logs/stats", serveLogStats)
+	mux.HandleFunc("/logs/stream", serveLogStream)
+	return mux
+
+}
+
+func addTap(w io.Writer) {
+	logMutex.Lock()
+	taps = append(taps, w)
+	logMutex.Unlock()
+}
+
+func removeTap(w io.Writer) {
+	logMutex.Lock()
+	defer logMutex.Unlock()
+	for i, tap := range taps {
+		if tap == w {
+			taps = append(taps[:i:i], taps[i+1:]...)
+			return
+		}
+	}
+}
+
+func serveRecentLogs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Write(RecentLogs.Bytes())
+}
+
+func serveLogLevel(w http.ResponseWriter, r *http.Request) {
+
+	if r.Method == http.MethodPost || r.Method == http.MethodPut {
+
+		source := "http " + r.RemoteAddr
+		query := r.URL.Query()
+
+		// All values are validated before any of them is applied, so that an invalid request changes nothing
+		changes := []func(){}
+
+		if value := query.Get("level"); value != "" {
+			level, err := ParseLevel(value)
+			if err != nil {
+				http.Error(w, "invalid value for level: "+value, http.StatusBadRequest)
+				return
+			}
+			changes = append(changes, func() {
+				old := GetLevel()
+				SetLevel(level)
+				auditChange("Level", old.String(), level.String(), source)
+			})
+		}
+
+		setters := []struct {
+			name string
+			set  func(bool, string)
+		}{
+			{"trace", SetTraceMode},
+			{"debug", SetDebugMode},
+			{"sql", SetDebugSQLMode},
+		}
+		for _, setter := range setters {
+			value := query.Get(setter.name)
+			if value == "" {
+				continue
+			}
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				http.Error(w, "invalid value for "+setter.name+": "+value, http.StatusBadRequest)
+				return
+			}
+			set := setter.set
+			changes = append(changes, func() {
+				set(enabled, source)
+			})
+		}
+
+		for _, change := range changes {
+			change()
+		}
+
+	}
+
+	cfg := CurrentConfig()
+	writeJSON(w, levelSettings{
+		Level:        GetLevel().String(),
+		TraceMode:    cfg.TraceMode,
+		DebugMode:    cfg.DebugMode,
+		DebugSQLMode: cfg.DebugSQLMode,
+	})
+
+}
+
+func serveLogStats(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, Stats())
+}
+
+func serveLogStream(w http.ResponseWriter, r *http.Request) {
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	stream := &streamTap{lines: make(chan []byte, 256)}
+	addTap(stream)
+	defer removeTap(stream)
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case entry := <-stream.lines:
+			for _, line := range bytes.Split(bytes.TrimRight(entry, "\r\n"), []byte("\n")) {
+				io.WriteString(w, "data: ")
+				w.Write(line)
+				io.WriteString(w, "\n")
+			}
+			io.WriteString(w, "\n")
+			flusher.Flush()
+		}
+	}
+
+}
+
+// streamTap passes the entries to a stream, dropping them when the stream can't keep up
+type streamTap struct {
+	lines chan []byte
+}
+
+func (s *streamTap) Write(p []byte) (int, error) {
+	select {
+	case s.lines <- append([]byte(nil), p...):
+	default:
+	}
+	return len(p), nil
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,154 @@
+//go:build !tinygo
+// +build !tinygo
+
+package log_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func Test_DebugHandlers_Recent(t *testing.T) {
+
+	resetLogConfig()
+	redirectOutput()
+	defer resetLogOutput()
+
+	mux := log.DebugHandlers()
+	log.RecentLogs.Reset()
+
+	log.Info("recent entry")
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/logs/recent", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code, "status")
+	assert.Equal(t, "test | INFO  | recent entry\n", rec.Body.String(), "body")
+
+}
+
+func Test_DebugHandlers_Level(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+
+	mux := log.DebugHandlers()
+
+	type test struct {
+		name           string
+		method         string
+		target         string
+		expectedStatus int
+		expectedDebug  bool
+		expectedLevel  string
+	}
+
+	var tests = []test{
+		{"get", http.MethodGet, "/logs/level", http.StatusOK, false, "INFO"},
+		{"get-ignores-query", http.MethodGet, "/logs/level?debug=true", http.StatusOK, false, "INFO"},
+		{"post-invalid", http.MethodPost, "/logs/level?debug=maybe", http.StatusBadRequest, false, "INFO"},
+		{"post", http.MethodPost, "/logs/level?debug=true", http.StatusOK, true, "DEBUG"},
+		{"put-level", http.MethodPut, "/logs/level?level=info", http.StatusOK, false, "INFO"},
+		{"put-level-invalid", http.MethodPut, "/logs/level?level=verbose", http.StatusBadRequest, false, "INFO"},
+		{"post-level", http.MethodPost, "/logs/level?level=debug", http.StatusOK, true, "DEBUG"},
+		{"post-level-partly-invalid", http.MethodPost, "/logs/level?level=info&trace=maybe", http.StatusBadRequest, true, "DEBUG"},
+		{"post-sql-partly-invalid", http.MethodPost, "/logs/level?sql=true&debug=maybe", http.StatusBadRequest, true, "DEBUG"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(tc.method, tc.target, nil))
+
+			assert.Equal(t, tc.expectedStatus, rec.Code, "status")
+			assert.Equal(t, tc.expectedDebug, log.DebugMode, "debug-mode")
+
+			if rec.Code == http.StatusOK {
+				var actual map[string]interface{}
+				err := json.Unmarshal(rec.Body.Bytes(), &actual)
+				assert.NoError(t, err, "json")
+				assert.Equal(t, tc.expectedDebug, actual["debug_mode"], "body")
+				assert.Equal(t, tc.expectedLevel, actual["level"], "body-level")
+			}
+
+		})
+	}
+
+	assert.Contains(t, stdout.String(), "DebugMode changed from \"false\" to \"true\" by http", "audit")
+	assert.Contains(t, stdout.String(), "Level changed from \"INFO\" to \"DEBUG\" by http", "audit-level")
+	assert.Equal(t, log.DebugLevel, log.GetLevel(), "level")
+	assert.False(t, log.DebugSQLMode, "sql-unchanged")
+
+}
+
+func Test_DebugHandlers_Stats(t *testing.T) {
+
+	resetLogConfig()
+	redirectOutput()
+	defer resetLogOutput()
+
+	log.Info("info")
+
+	rec := httptest.NewRecorder()
+	log.DebugHandlers().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/logs/stats", nil))
+
+	var actual []log.SinkStats
+	err := json.Unmarshal(rec.Body.Bytes(), &actual)
+
+	assert.NoError(t, err, "json")
+	assert.NotEmpty(t, actual, "stats")
+
+}
+
+func Test_DebugHandlers_Stream(t *testing.T) {
+
+	resetLogConfig()
+	redirectOutput()
+	defer resetLogOutput()
+
+	server := httptest.NewServer(log.DebugHandlers())
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/logs/stream", nil)
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	assert.NoError(t, err, "request")
+	defer resp.Body.Close()
+
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"), "content-type")
+
+	go func() {
+		for ctx.Err() == nil {
+			log.Info("streamed\nentry")
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+
+	reader := bufio.NewReader(resp.Body)
+	lines := []string{}
+	for len(lines) < 3 {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			break
+		}
+		lines = append(lines, strings.TrimRight(line, "\n"))
+	}
+	cancel()
+
+	assert.Equal(t, []string{"data: test | INFO  | streamed", "data: entry", ""}, lines, "event")
+
+}
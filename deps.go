@@ -0,0 +1,52 @@
+//go:build !log_minimal && !tinygo
+// +build !log_minimal,!tinygo
+
+package log
+
+import (
+	"github.com/go-errors/errors"
+	"github.com/pieterclaerhout/go-formatter"
+	pkgerrors "github.com/pkg/errors"
+	"github.com/sanity-io/litter"
+)
+
+// dump returns a structural dump of arg
+func dump(arg interface{}) string {
+	return litter.Sdump(arg)
+}
+
+// formatSQL pretty prints the SQL statement
+func formatSQL(sql string) (string, error) {
+	return formatter.SQL(sql)
+}
+
+// newStackError returns err with the stack of the caller, skip is the number of frames to skip
+func newStackError(err error, skip int) stackError {
+	return errors.Wrap(err, skip+1)
+}
+
+// pkgErrorsStack returns the stack embedded in err by github.com/pkg/errors
+func pkgErrorsStack(err error) []uintptr {
+
+	e, ok := err.(interface{ StackTrace() pkgerrors.StackTrace })
+	if !ok {
+		return nil
+	}
+
+	trace := e.StackTrace()
+	stack := make([]uintptr, len(trace))
+	for i, frame := range trace {
+		stack[i] = uintptr(frame)
+	}
+
+	return stack
+
+}
+
+// unwrapGoError returns the error wrapped by an error of github.com/go-errors/errors, which doesn't implement Unwrap
+func unwrapGoError(err error) error {
+	if e, ok := err.(*errors.Error); ok {
+		return e.Err
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+//go:build log_minimal || tinygo
+// +build log_minimal tinygo
+
+package log
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// The log_minimal build tag removes the dependencies on litter, go-formatter and go-errors for small binaries. Dumps
+// then use the Go syntax representation of the values, DebugSQL prints the statement as-is and the stack traces are
+// collected using the runtime package. The stacks embedded in the errors of github.com/pkg/errors aren't shown, the
+// errors they wrap still are.
+//
+// Building with TinyGo implies log_minimal and also leaves out the add-ons which open network connections, serve HTTP,
+// use TLS or run other programs (StatsDHook, SyslogWriter, DebugDialer, CheckCertificateExpiry, DebugHandlers,
+// HealthHandler, the telemetry uploads, S3Store, AzureStore and the pager) and DiffConfig, which compares the settings using
+// reflection. The core still uses reflect (through fmt and encoding/json), regexp (ScrubRules and the signatures),
+// log/slog, database/sql (ClassifyError) and crypto/x509 (ParseEncryptionKey), see Test_TinyGo_Build.
+
+// dump returns the Go syntax representation of arg
+func dump(arg interface{}) string {
+	return fmt.Sprintf("%#v", arg)
+}
+
+// formatSQL returns the SQL statement as-is
+func formatSQL(sql string) (string, error) {
+	return sql, nil
+}
+
+// pkgErrorsStack returns nil as github.com/pkg/errors isn't a dependency
+func pkgErrorsStack(err error) []uintptr {
+	return nil
+}
+
+// unwrapGoError returns nil as github.com/go-errors/errors isn't a dependency
+func unwrapGoError(err error) error {
+	return nil
+}
+
+type minimalStackError struct {
+	err   error
+	stack []uintptr
+}
+
+// newStackError returns err with the stack of the caller, skip is the number of frames to skip
+func newStackError(err error, skip int) stackError {
+
+	if se, ok := err.(stackError); ok {
+		return se
+	}
+
+	stack := make([]uintptr, 50)
+	length := runtime.Callers(2+skip, stack)
+
+	return &minimalStackError{err: err, stack: stack[:length]}
+
+}
+
+func (e *minimalStackError) Error() string {
+	return e.err.Error()
+}
+
+func (e *minimalStackError) TypeName() string {
+	return reflect.TypeOf(e.err).String()
+}
+
+func (e *minimalStackError) Callers() []uintptr {
+	return e.stack
+}
+
+func (e *minimalStackError) ErrorStack() string {
+
+	var b strings.Builder
+	b.WriteString(e.TypeName() + " " + e.Error() + "\n")
+
+	sources := map[string][][]byte{}
+	frames := runtime.CallersFrames(e.stack)
+	for {
+		frame, more := frames.Next()
+		fmt.Fprintf(&b, "%s:%d (0x%x)\n", frame.File, frame.Line, frame.PC)
+		if source, ok := sourceLine(sources, frame.File, frame.Line); ok {
+			b.WriteString("\t" + frameName(frame.Function) + ": " + source + "\n")
+		}
+		if !more {
+			break
+		}
+	}
+
+	return b.String()
+
+}
@@ -0,0 +1,35 @@
+//go:build log_minimal || tinygo
+// +build log_minimal tinygo
+
+package log
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_dump_Minimal(t *testing.T) {
+	assert.Equal(t, `struct { Name string }{Name:"john"}`, dump(struct{ Name string }{"john"}))
+}
+
+func Test_formatSQL_Minimal(t *testing.T) {
+	actual, err := formatSQL("select * from users")
+	assert.NoError(t, err, "error")
+	assert.Equal(t, "select * from users", actual, "sql")
+}
+
+func Test_newStackError_Minimal(t *testing.T) {
+
+	err := newStackError(errors.New("my error"), 0)
+
+	assert.Equal(t, "my error", err.Error(), "error")
+	assert.Equal(t, "*errors.errorString", err.TypeName(), "type-name")
+	assert.NotEmpty(t, err.Callers(), "callers")
+	assert.True(t, strings.HasPrefix(err.ErrorStack(), "*errors.errorString my error\n"), "error-stack")
+	assert.Contains(t, err.ErrorStack(), "deps_minimal_internal_test.go:", "frames")
+	assert.Equal(t, err, newStackError(err, 0), "existing-stack")
+
+}
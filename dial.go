@@ -0,0 +1,186 @@
+//go:build !tinygo
+// +build !tinygo
+
+package log
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// DebugDialer wraps a net.Dialer to log the DNS resolution, the connection latency and the TLS handshake details of
+// each connection as debug, to diagnose flaky connectivity
+//
+// Nothing is logged when DebugMode is disabled. It can be used as the DialContext of an http.Transport.
+type DebugDialer struct {
+	Dialer   *net.Dialer
+	Resolver *net.Resolver
+}
+
+var tlsVersions = map[uint16]string{
+	tls.VersionTLS10: "TLS 1.0",
+	tls.VersionTLS11: "TLS 1.1",
+	tls.VersionTLS12: "TLS 1.2",
+	tls.VersionTLS13: "TLS 1.3",
+}
+
+// DebugDial connects to the address on the named network using a DebugDialer
+func DebugDial(network string, addr string) (net.Conn, error) {
+	return (&DebugDialer{}).DialContext(context.Background(), network, addr)
+}
+
+// DebugDialTLS connects to the address on the named network and performs a TLS handshake using a DebugDialer
+func DebugDialTLS(network string, addr string, config *tls.Config) (*tls.Conn, error) {
+	return (&DebugDialer{}).DialTLSContext(context.Background(), network, addr, config)
+}
+
+// DialContext connects to the address on the named network
+func (d *DebugDialer) DialContext(ctx context.Context, network string, addr string) (net.Conn, error) {
+
+	if !DebugMode && !isRecording() {
+		return d.dialer().DialContext(ctx, network, addr)
+	}
+
+	d.logResolution(ctx, addr)
+
+	start := time.Now()
+	conn, err := d.dialer().DialContext(ctx, network, addr)
+
+	fields := map[string]interface{}{
+		"network":  network,
+		"addr":     addr,
+		"duration": time.Since(start),
+	}
+
+	if err != nil {
+		fields["error"] = err.Error()
+		printEntry("DEBUG", "Dial failed", fields)
+		return nil, err
+	}
+
+	fields["local_addr"] = conn.LocalAddr().String()
+	fields["remote_addr"] = conn.RemoteAddr().String()
+	printEntry("DEBUG", "Connected", fields)
+
+	return conn, nil
+
+}
+
+// DialTLSContext connects to the address on the named network and performs a TLS handshake
+//
+// When config doesn't have a ServerName, the host of addr is used.
+func (d *DebugDialer) DialTLSContext(ctx context.Context, network string, addr string, config *tls.Config) (*tls.Conn, error) {
+
+	conn, err := d.DialContext(ctx, network, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	if config == nil {
+		config = &tls.Config{}
+	}
+	if config.ServerName == "" {
+		config = config.Clone()
+		config.ServerName, _, _ = net.SplitHostPort(addr)
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+		defer conn.SetDeadline(time.Time{})
+	}
+
+	tlsConn := tls.Client(conn, config)
+
+	start := time.Now()
+	err = tlsConn.Handshake()
+
+	if DebugMode || isRecording() {
+		logHandshake(addr, config.ServerName, time.Since(start), tlsConn.ConnectionState(), err)
+	}
+
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return tlsConn, nil
+
+}
+
+func (d *DebugDialer) dialer() *net.Dialer {
+	if d.Dialer == nil {
+		return &net.Dialer{}
+	}
+	return d.Dialer
+}
+
+func (d *DebugDialer) logResolution(ctx context.Context, addr string) {
+
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil || host == "" || net.ParseIP(host) != nil {
+		return
+	}
+
+	resolver := d.Resolver
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+
+	start := time.Now()
+	addrs, err := resolver.LookupHost(ctx, host)
+
+	fields := map[string]interface{}{
+		"host":     host,
+		"duration": time.Since(start),
+	}
+
+	if err != nil {
+		fields["error"] = err.Error()
+		printEntry("DEBUG", "DNS lookup failed", fields)
+		return
+	}
+
+	fields["addrs"] = strings.Join(addrs, ",")
+	printEntry("DEBUG", "DNS resolved", fields)
+
+}
+
+func logHandshake(addr string, serverName string, duration time.Duration, state tls.ConnectionState, err error) {
+
+	fields := map[string]interface{}{
+		"addr":     addr,
+		"duration": duration,
+	}
+
+	if err != nil {
+		fields["error"] = err.Error()
+		printEntry("DEBUG", "TLS handshake failed", fields)
+		return
+	}
+
+	version, ok := tlsVersions[state.Version]
+	if !ok {
+		version = fmt.Sprintf("0x%04x", state.Version)
+	}
+
+	fields["tls.version"] = version
+	fields["tls.cipher_suite"] = fmt.Sprintf("0x%04x", state.CipherSuite)
+	fields["tls.server_name"] = serverName
+	fields["tls.resumed"] = state.DidResume
+	if state.NegotiatedProtocol != "" {
+		fields["tls.protocol"] = state.NegotiatedProtocol
+	}
+	if len(state.PeerCertificates) > 0 {
+		cert := state.PeerCertificates[0]
+		fields["tls.peer_subject"] = cert.Subject.String()
+		fields["tls.peer_issuer"] = cert.Issuer.String()
+		fields["tls.peer_not_after"] = cert.NotAfter.UTC().Format(time.RFC3339)
+	}
+
+	printEntry("DEBUG", "TLS handshake completed", fields)
+
+}
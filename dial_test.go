@@ -0,0 +1,94 @@
+//go:build !tinygo
+// +build !tinygo
+
+package log_test
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func Test_DebugDial(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err, "listen")
+	defer listener.Close()
+
+	log.DebugMode = true
+
+	conn, err := log.DebugDial("tcp", listener.Addr().String())
+	assert.NoError(t, err, "dial")
+	conn.Close()
+
+	assert.Regexp(t, `^test \| DEBUG \| Connected addr=127\.0\.0\.1:\d+ duration=\S+ local_addr=127\.0\.0\.1:\d+ network=tcp remote_addr=127\.0\.0\.1:\d+\n$`, stdout.String())
+
+}
+
+func Test_DebugDial_Resolution(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err, "listen")
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+	listener.Close()
+
+	log.DebugMode = true
+
+	_, err = log.DebugDial("tcp", net.JoinHostPort("localhost", port))
+
+	assert.Error(t, err, "closed-port")
+	assert.Regexp(t, `^test \| DEBUG \| DNS resolved addrs=\S*127\.0\.0\.1\S* duration=\S+ host=localhost\n`, stdout.String(), "resolved")
+	assert.Regexp(t, `\ntest \| DEBUG \| Dial failed addr=localhost:\d+ duration=\S+ error=".*" network=tcp\n$`, stdout.String(), "failed")
+
+}
+
+func Test_DebugDial_Disabled(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err, "listen")
+	defer listener.Close()
+
+	conn, err := log.DebugDial("tcp", listener.Addr().String())
+	assert.NoError(t, err, "dial")
+	conn.Close()
+
+	assert.Equal(t, "", stdout.String())
+
+}
+
+func Test_DebugDialTLS(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	log.DebugMode = true
+
+	conn, err := log.DebugDialTLS("tcp", server.Listener.Addr().String(), &tls.Config{InsecureSkipVerify: true})
+	assert.NoError(t, err, "dial")
+	conn.Close()
+
+	assert.Regexp(t, `\ntest \| DEBUG \| TLS handshake completed addr=127\.0\.0\.1:\d+ duration=\S+ tls\.cipher_suite=0x[0-9a-f]{4} tls\.peer_issuer=\S+.* tls\.peer_not_after=\S+ tls\.peer_subject=.* tls\.resumed=false tls\.server_name=127\.0\.0\.1 tls\.version="TLS 1\.3"\n$`, stdout.String())
+
+}
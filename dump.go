@@ -0,0 +1,66 @@
+package log
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// DumpMaxLines is the number of lines above which the dumps written to a terminal are folded (0 disables folding)
+//
+// A folded dump shows its first DumpMaxLines lines followed by a marker with the number of lines left out.
+var DumpMaxLines = 0
+
+// DumpPager indicates if the dumps which are folded are first shown in full using the command in the PAGER
+// environment variable (e.g. "less")
+var DumpPager = false
+
+// DebugDumpFull disables the folding of the dumps, so that they are always written in full
+var DebugDumpFull = false
+
+// dumpFor returns the dump of arg for an entry of the level
+func dumpFor(level string, arg interface{}) string {
+	return foldDump(level, dump(arg))
+}
+
+// foldDump folds the dump when it's too long for the terminal the entry of the level is written to, showing it in the
+// pager first when DumpPager is enabled
+func foldDump(level string, message string) string {
+
+	if DumpMaxLines <= 0 || DebugDumpFull || !std.levelEnabled(level) {
+		return message
+	}
+
+	lines := strings.Split(message, "\n")
+	if len(lines) <= DumpMaxLines {
+		return message
+	}
+
+	w, _ := streamForLevel(level)
+	if !isTerminal(w) {
+		return message
+	}
+
+	if DumpPager {
+		if pager := os.Getenv("PAGER"); pager != "" {
+			if terminal, ok := unwrapWriter(w).(*os.File); ok {
+				// The entry itself is scrubbed when it's logged, the pager shows the dump before that
+				paged := message
+				if len(ScrubRules) > 0 && !ScrubDryRun {
+					paged = scrubMessage(level, paged)
+				}
+				if err := runPager(pager, paged+"\n", terminal); err != nil {
+					Warn("Failed to show the dump in the pager:", err)
+				}
+			}
+		}
+	}
+
+	more := strconv.Itoa(len(lines)-DumpMaxLines) + " more lines"
+	if len(lines)-DumpMaxLines == 1 {
+		more = "1 more line"
+	}
+
+	return strings.Join(lines[:DumpMaxLines], "\n") + "\n... " + more + " (set DebugDumpFull)"
+
+}
@@ -0,0 +1,121 @@
+package log
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_foldDump(t *testing.T) {
+
+	terminal, err := ioutil.TempFile("", "go-log-terminal")
+	assert.NoError(t, err, "tempfile")
+	defer os.Remove(terminal.Name())
+	defer terminal.Close()
+
+	oldIsTerminal, oldRunPager, oldPager := isTerminal, runPager, os.Getenv("PAGER")
+	defer func() {
+		isTerminal, runPager = oldIsTerminal, oldRunPager
+		os.Setenv("PAGER", oldPager)
+		DumpMaxLines, DumpPager, DebugDumpFull = 0, false, false
+		ScrubRules = []ScrubRule{}
+		resetLogConfig()
+		resetLogOutput()
+	}()
+
+	long := "line 1\nline 2\nline 3\nline 4\nline 5"
+	folded := "line 1\nline 2\n... 3 more lines (set DebugDumpFull)"
+
+	type test struct {
+		name     string
+		level    string
+		maxLines int
+		terminal bool
+		full     bool
+		pager    bool
+		debug    bool
+		message  string
+		expected string
+		paged    string
+		scrub    bool
+	}
+
+	var tests = []test{
+		{"disabled", "INFO", 0, true, false, false, false, long, long, "", false},
+		{"short", "INFO", 5, true, false, false, false, long, long, "", false},
+		{"folded", "INFO", 2, true, false, false, false, long, folded, "", false},
+		{"folded-stderr", "ERROR", 2, true, false, false, false, long, folded, "", false},
+		{"not-terminal", "INFO", 2, false, false, false, false, long, long, "", false},
+		{"full", "INFO", 2, true, true, false, false, long, long, "", false},
+		{"pager", "INFO", 2, true, false, true, false, long, folded, long + "\n", false},
+		{"debug-hidden", "DEBUG", 2, true, false, true, false, long, long, "", false},
+		{"debug-shown", "DEBUG", 2, true, false, true, true, long, folded, long + "\n", false},
+		{"pager-scrubbed", "INFO", 2, true, false, true, false, long, folded, strings.Replace(long, "line 5", "[REDACTED]", 1) + "\n", true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			resetLogConfig()
+			Stdout = terminal
+			Stderr = terminal
+
+			DumpMaxLines, DumpPager, DebugDumpFull, DebugMode = tc.maxLines, tc.pager, tc.full, tc.debug
+			ScrubRules = []ScrubRule{}
+			if tc.scrub {
+				ScrubRules = []ScrubRule{{Name: "last", Pattern: regexp.MustCompile(`line 5`)}}
+			}
+			os.Setenv("PAGER", "less -R")
+
+			isTerminal = func(w io.Writer) bool {
+				return tc.terminal && w == terminal
+			}
+
+			paged := ""
+			runPager = func(command string, text string, f *os.File) error {
+				assert.Equal(t, "less -R", command, "command")
+				assert.Equal(t, terminal, f, "terminal")
+				paged = text
+				return nil
+			}
+
+			assert.Equal(t, tc.expected, foldDump(tc.level, tc.message), "message")
+			assert.Equal(t, tc.paged, paged, "paged")
+
+		})
+	}
+
+}
+
+func Test_foldDump_NoPager(t *testing.T) {
+
+	oldIsTerminal, oldRunPager, oldPager := isTerminal, runPager, os.Getenv("PAGER")
+	defer func() {
+		isTerminal, runPager = oldIsTerminal, oldRunPager
+		os.Setenv("PAGER", oldPager)
+		DumpMaxLines, DumpPager = 0, false
+		resetLogOutput()
+	}()
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+
+	DumpMaxLines, DumpPager = 1, true
+	os.Setenv("PAGER", "")
+	isTerminal = func(w io.Writer) bool {
+		return true
+	}
+	runPager = func(command string, text string, f *os.File) error {
+		t.Error("pager called")
+		return nil
+	}
+
+	assert.Equal(t, "a\n... 1 more line (set DebugDumpFull)", foldDump("INFO", "a\nb"), "folded")
+	assert.False(t, strings.Contains(stdout.String(), "pager"), "no-warning")
+
+}
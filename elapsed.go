@@ -0,0 +1,46 @@
+package log
+
+import (
+	"strconv"
+	"time"
+)
+
+// TimestampStyle defines what the timestamps of the text format show
+type TimestampStyle int
+
+const (
+	// TimestampWallClock shows the time formatted with TimeFormat in TimeZone
+	TimestampWallClock TimestampStyle = iota
+
+	// TimestampSinceStart shows the time elapsed since the process started (e.g. "+12.345s")
+	TimestampSinceStart
+
+	// TimestampSincePrevious shows the time elapsed since the previous entry (e.g. "+0.002s")
+	TimestampSincePrevious
+)
+
+// TimestampMode defines what the timestamps of the text format show
+var TimestampMode = TimestampWallClock
+
+var processStart = time.Now()
+
+// lastEntryTime is the time of the previous entry and is guarded by logMutex
+var lastEntryTime time.Time
+
+func formatTimestamp(e entry) string {
+	switch TimestampMode {
+	case TimestampSinceStart:
+		return formatElapsed(e.time.Sub(processStart))
+	case TimestampSincePrevious:
+		if e.previous.IsZero() {
+			return formatElapsed(e.time.Sub(processStart))
+		}
+		return formatElapsed(e.time.Sub(e.previous))
+	default:
+		return e.time.In(e.logger.timeZone()).Format(e.logger.timeFormat())
+	}
+}
+
+func formatElapsed(d time.Duration) string {
+	return "+" + strconv.FormatFloat(d.Seconds(), 'f', 3, 64) + "s"
+}
@@ -0,0 +1,28 @@
+package log
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_formatElapsed(t *testing.T) {
+	assert.Equal(t, "+0.000s", formatElapsed(0))
+	assert.Equal(t, "+0.002s", formatElapsed(2*time.Millisecond))
+	assert.Equal(t, "+12.346s", formatElapsed(12345600*time.Microsecond))
+}
+
+func Test_formatTimestamp_SincePrevious(t *testing.T) {
+
+	TimestampMode = TimestampSincePrevious
+	defer func() {
+		TimestampMode = TimestampWallClock
+	}()
+
+	now := time.Now()
+	e := entry{time: now, previous: now.Add(-1500 * time.Millisecond)}
+
+	assert.Equal(t, "+1.500s", formatTimestamp(e))
+
+}
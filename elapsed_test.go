@@ -0,0 +1,48 @@
+package log_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func Test_TimestampMode(t *testing.T) {
+
+	type test struct {
+		name string
+		mode log.TimestampStyle
+	}
+
+	var tests = []test{
+		{"since-start", log.TimestampSinceStart},
+		{"since-previous", log.TimestampSincePrevious},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			resetLogConfig()
+			stdout, _ := redirectOutput()
+			defer resetLogOutput()
+
+			log.TimestampMode = tc.mode
+			defer func() {
+				log.TimestampMode = log.TimestampWallClock
+			}()
+
+			log.Info("first")
+			log.Info("second")
+
+			lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+			assert.Len(t, lines, 2, "lines")
+			for _, line := range lines {
+				assert.Regexp(t, `^\+\d+\.\d{3}s \| INFO  \| (first|second)$`, line, "line")
+			}
+
+		})
+	}
+
+}
@@ -0,0 +1,96 @@
+package log
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// encryptedPrefix is the prefix of the encrypted field values, its version changes with the wire format
+const encryptedPrefix = "enc:v2:"
+
+// encryptionFailed replaces the values which could not be encrypted, so that they are never written in plain text
+const encryptionFailed = "[encryption failed]"
+
+// EncryptedFields contains the keys of the fields whose values are encrypted with EncryptionKey before the entries
+// are written or recorded
+var EncryptedFields = []string{}
+
+// EncryptionKey is the P-256 public key used to encrypt the EncryptedFields (nil disables the encryption)
+//
+// The values are encrypted with a key agreed between an ephemeral P-256 key and EncryptionKey using crypto/ecdh,
+// derived with HKDF-SHA256 (with both public keys as the salt and "go-log encrypted field v2" as the info) and
+// used for AES-256-GCM. They are written as "enc:v2:" followed by the base64 (standard, without padding) encoding of:
+//
+//	ephemeral public key  65 bytes, uncompressed P-256 point
+//	nonce                 12 bytes
+//	ciphertext            the encrypted value followed by the 16 bytes GCM tag
+//
+// The values can only be decrypted with DecryptField and the private key. Encryption requires Go 1.24 or later (for
+// crypto/hkdf), older versions write "[encryption failed]" instead of the values.
+var EncryptionKey *ecdsa.PublicKey
+
+// ErrInvalidEncryptedField is returned when decrypting a value which wasn't encrypted for the private key
+var ErrInvalidEncryptedField = errors.New("invalid encrypted field")
+
+// ErrEncryptionUnsupported is returned when encrypting or decrypting field values with a Go version older than 1.24
+var ErrEncryptionUnsupported = errors.New("field encryption requires Go 1.24 or later")
+
+// ParseEncryptionKey parses a PEM encoded P-256 public key (e.g. from "openssl ec -pubout")
+func ParseEncryptionKey(data []byte) (*ecdsa.PublicKey, error) {
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM encoded public key found")
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	pub, ok := key.(*ecdsa.PublicKey)
+	if !ok || pub.Curve != elliptic.P256() {
+		return nil, errors.New("public key is not a P-256 key")
+	}
+
+	return pub, nil
+
+}
+
+// encryptFields returns a copy of the fields with the values of the EncryptedFields encrypted
+func encryptFields(fields map[string]interface{}) map[string]interface{} {
+
+	var encrypted map[string]interface{}
+
+	for _, key := range EncryptedFields {
+
+		// Values which are already encrypted, such as replayed ones, are kept as is
+		value, ok := fields[key]
+		if s, isString := value.(string); !ok || (isString && strings.HasPrefix(s, encryptedPrefix)) {
+			continue
+		}
+
+		if encrypted == nil {
+			encrypted = mergeFields(fields, nil)
+		}
+
+		s, err := EncryptField(EncryptionKey, fmt.Sprint(value))
+		if err != nil {
+			s = encryptionFailed
+		}
+		encrypted[key] = s
+
+	}
+
+	if encrypted == nil {
+		return fields
+	}
+
+	return encrypted
+
+}
@@ -0,0 +1,124 @@
+//go:build go1.24
+// +build go1.24
+
+package log
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+)
+
+// encryptionInfo is the HKDF info of the key which encrypts the field values
+const encryptionInfo = "go-log encrypted field v2"
+
+// encryptedKeySize is the size of an uncompressed P-256 public key
+const encryptedKeySize = 65
+
+// EncryptField encrypts the value for the owner of the private key of pub, see EncryptionKey for the format
+func EncryptField(pub *ecdsa.PublicKey, value string) (string, error) {
+
+	recipient, err := pub.ECDH()
+	if err != nil {
+		return "", err
+	}
+
+	ephemeral, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return "", err
+	}
+
+	shared, err := ephemeral.ECDH(recipient)
+	if err != nil {
+		return "", err
+	}
+
+	ephemeralPub := ephemeral.PublicKey().Bytes()
+
+	gcm, err := encryptionCipher(shared, ephemeralPub, recipient.Bytes())
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	data := append(ephemeralPub, nonce...)
+	data = gcm.Seal(data, nonce, []byte(value), nil)
+
+	return encryptedPrefix + base64.RawStdEncoding.EncodeToString(data), nil
+
+}
+
+// DecryptField decrypts a value encrypted with the public key of priv
+func DecryptField(priv *ecdsa.PrivateKey, value string) (string, error) {
+
+	if !strings.HasPrefix(value, encryptedPrefix) {
+		return "", ErrInvalidEncryptedField
+	}
+
+	data, err := base64.RawStdEncoding.DecodeString(strings.TrimPrefix(value, encryptedPrefix))
+	if err != nil || len(data) < encryptedKeySize {
+		return "", ErrInvalidEncryptedField
+	}
+
+	key, err := priv.ECDH()
+	if err != nil {
+		return "", err
+	}
+
+	ephemeral, err := ecdh.P256().NewPublicKey(data[:encryptedKeySize])
+	if err != nil {
+		return "", ErrInvalidEncryptedField
+	}
+
+	shared, err := key.ECDH(ephemeral)
+	if err != nil {
+		return "", ErrInvalidEncryptedField
+	}
+
+	gcm, err := encryptionCipher(shared, data[:encryptedKeySize], key.PublicKey().Bytes())
+	if err != nil {
+		return "", err
+	}
+
+	data = data[encryptedKeySize:]
+	if len(data) < gcm.NonceSize() {
+		return "", ErrInvalidEncryptedField
+	}
+
+	plaintext, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", ErrInvalidEncryptedField
+	}
+
+	return string(plaintext), nil
+
+}
+
+// encryptionCipher returns the AES-256-GCM cipher with the key derived from the shared secret
+func encryptionCipher(shared []byte, ephemeralPub []byte, recipientPub []byte) (cipher.AEAD, error) {
+
+	salt := append(append([]byte{}, ephemeralPub...), recipientPub...)
+
+	key, err := hkdf.Key(sha256.New, shared, salt, encryptionInfo, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+
+}
@@ -0,0 +1,18 @@
+//go:build !go1.24
+// +build !go1.24
+
+package log
+
+import (
+	"crypto/ecdsa"
+)
+
+// EncryptField returns ErrEncryptionUnsupported, field encryption requires Go 1.24 or later
+func EncryptField(pub *ecdsa.PublicKey, value string) (string, error) {
+	return "", ErrEncryptionUnsupported
+}
+
+// DecryptField returns ErrEncryptionUnsupported, field encryption requires Go 1.24 or later
+func DecryptField(priv *ecdsa.PrivateKey, value string) (string, error) {
+	return "", ErrEncryptionUnsupported
+}
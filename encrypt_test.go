@@ -0,0 +1,170 @@
+//go:build go1.24
+// +build go1.24
+
+package log_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func resetEncryptionConfig() {
+	log.EncryptedFields = []string{}
+	log.EncryptionKey = nil
+	log.Format = log.FormatText
+}
+
+func Test_EncryptField(t *testing.T) {
+
+	priv, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	other, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	type test struct {
+		name  string
+		value string
+	}
+
+	var tests = []test{
+		{"empty", ""},
+		{"simple", "4111-1111-1111-1111"},
+		{"unicode", "crème brûlée"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			encrypted, err := log.EncryptField(&priv.PublicKey, tc.value)
+			assert.NoError(t, err, "encrypt")
+			assert.True(t, strings.HasPrefix(encrypted, "enc:v2:"), "prefix")
+
+			actual, err := log.DecryptField(priv, encrypted)
+			assert.NoError(t, err, "decrypt")
+			assert.Equal(t, tc.value, actual, "value")
+
+			_, err = log.DecryptField(other, encrypted)
+			assert.Equal(t, log.ErrInvalidEncryptedField, err, "other-key")
+
+		})
+	}
+
+}
+
+func Test_EncryptField_Format(t *testing.T) {
+
+	priv, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	encrypted, err := log.EncryptField(&priv.PublicKey, "value")
+	assert.NoError(t, err, "encrypt")
+
+	data, err := base64.RawStdEncoding.DecodeString(strings.TrimPrefix(encrypted, "enc:v2:"))
+	assert.NoError(t, err, "base64")
+	assert.Equal(t, 65+12+len("value")+16, len(data), "length")
+	assert.Equal(t, byte(4), data[0], "uncompressed-point")
+
+	_, err = log.DecryptField(priv, "enc:v1:"+strings.TrimPrefix(encrypted, "enc:v2:"))
+	assert.Equal(t, log.ErrInvalidEncryptedField, err, "version")
+
+}
+
+func Test_DecryptField_Invalid(t *testing.T) {
+
+	priv, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	for _, value := range []string{"plain", "enc:v2:!!!", "enc:v2:AAAA"} {
+		_, err := log.DecryptField(priv, value)
+		assert.Equal(t, log.ErrInvalidEncryptedField, err, value)
+	}
+
+}
+
+func Test_ParseEncryptionKey(t *testing.T) {
+
+	priv, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	der, _ := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+	data := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+	pub, err := log.ParseEncryptionKey(data)
+	assert.NoError(t, err, "parse")
+	assert.Equal(t, priv.PublicKey.X, pub.X, "x")
+
+	p384, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	der, _ = x509.MarshalPKIXPublicKey(&p384.PublicKey)
+	_, err = log.ParseEncryptionKey(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	assert.Error(t, err, "p384")
+
+	_, err = log.ParseEncryptionKey([]byte("not a key"))
+	assert.Error(t, err, "not-pem")
+
+}
+
+func Test_EncryptedFields(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+	defer resetEncryptionConfig()
+
+	priv, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	log.EncryptionKey = &priv.PublicKey
+	log.EncryptedFields = []string{"card", "ssn"}
+	log.Format = log.FormatDatadog
+
+	fields := map[string]interface{}{"card": "4111-1111-1111-1111", "user": "john"}
+	log.WithFields(fields).Info("payment")
+
+	assert.Equal(t, "4111-1111-1111-1111", fields["card"], "caller-fields-untouched")
+	assert.NotContains(t, stdout.String(), "4111-1111", "plain-text")
+
+	var actual map[string]interface{}
+	assert.NoError(t, json.Unmarshal(stdout.Bytes(), &actual), "json")
+	assert.Equal(t, "john", actual["user"], "user")
+
+	decrypted, err := log.DecryptField(priv, actual["card"].(string))
+	assert.NoError(t, err, "decrypt")
+	assert.Equal(t, "4111-1111-1111-1111", decrypted, "card")
+
+}
+
+func Test_Replay_EncryptedFields(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+	defer resetEncryptionConfig()
+
+	priv, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	log.EncryptionKey = &priv.PublicKey
+	log.EncryptedFields = []string{"card"}
+	log.Format = log.FormatDatadog
+
+	encrypted, _ := log.EncryptField(log.EncryptionKey, "5500-0000-0000-0004")
+	input := `{"level":"INFO","message":"payment","fields":{"card":"4111-1111-1111-1111"}}` + "\n" +
+		`{"level":"INFO","message":"payment","fields":{"card":"` + encrypted + `"}}`
+
+	_, err := log.Replay(strings.NewReader(input))
+	assert.NoError(t, err, "error")
+	assert.NotContains(t, stdout.String(), "4111-1111", "plain-text")
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if assert.Len(t, lines, 2, "lines") {
+		for i, expected := range []string{"4111-1111-1111-1111", "5500-0000-0000-0004"} {
+			var actual map[string]interface{}
+			assert.NoError(t, json.Unmarshal([]byte(lines[i]), &actual), "json")
+			decrypted, err := log.DecryptField(priv, actual["card"].(string))
+			assert.NoError(t, err, "decrypt")
+			assert.Equal(t, expected, decrypted, "card")
+		}
+	}
+
+}
@@ -0,0 +1,51 @@
+package log
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"time"
+)
+
+// EntryIDField is the field which holds the unique ID of an entry
+const EntryIDField = "entry.id"
+
+// EntryIDs indicates if every entry gets a random UUID in the EntryIDField field
+//
+// The ID is kept when the entry is recorded, written to the dead letter sink or replayed, so consumers of
+// at-least-once sinks can use it to deduplicate entries which are delivered more than once.
+var EntryIDs = false
+
+// newEntryID returns a random (version 4) UUID
+func newEntryID() string {
+
+	var id [16]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		binary.BigEndian.PutUint64(id[:8], uint64(time.Now().UnixNano()))
+	}
+	id[6] = (id[6] & 0x0f) | 0x40
+	id[8] = (id[8] & 0x3f) | 0x80
+
+	buf := make([]byte, 36)
+	hex.Encode(buf[0:8], id[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], id[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], id[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], id[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], id[10:])
+
+	return string(buf)
+
+}
+
+func withEntryID(fields map[string]interface{}) map[string]interface{} {
+	withID := make(map[string]interface{}, len(fields)+1)
+	for key, value := range fields {
+		withID[key] = value
+	}
+	withID[EntryIDField] = newEntryID()
+	return withID
+}
@@ -0,0 +1,91 @@
+package log_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func Test_EntryIDs(t *testing.T) {
+
+	resetLogConfig()
+	redirectOutput()
+	defer resetLogOutput()
+	defer resetAllowListConfig()
+	defer func() {
+		log.EntryIDs = false
+	}()
+
+	stdout := &bytes.Buffer{}
+	log.Stdout = stdout
+	log.Format = log.FormatDatadog
+	log.StrictJSONFields = true
+	log.EntryIDs = true
+
+	log.Info("first")
+	log.Info("second")
+
+	lines := bytes.Split(bytes.TrimSpace(stdout.Bytes()), []byte("\n"))
+	assert.Len(t, lines, 2, "lines")
+
+	ids := []string{}
+	for _, line := range lines {
+		var values map[string]interface{}
+		assert.NoError(t, json.Unmarshal(line, &values), "json")
+		id, _ := values[log.EntryIDField].(string)
+		assert.Regexp(t, uuidPattern, id, "uuid")
+		ids = append(ids, id)
+	}
+
+	assert.NotEqual(t, ids[0], ids[1], "unique")
+
+}
+
+func Test_EntryIDs_DeadLetterReplay(t *testing.T) {
+
+	resetLogConfig()
+	redirectOutput()
+	defer resetLogOutput()
+	defer resetDeadLetterConfig()
+	defer func() {
+		log.EntryIDs = false
+	}()
+
+	deadLetter := &bytes.Buffer{}
+	log.DeadLetter = deadLetter
+	log.Stdout = failingWriter{}
+	log.EntryIDs = true
+
+	log.Info("lost")
+
+	var recorded log.RecordedEntry
+	assert.NoError(t, json.Unmarshal(deadLetter.Bytes(), &recorded), "json")
+	id, _ := recorded.Fields[log.EntryIDField].(string)
+	assert.Regexp(t, uuidPattern, id, "uuid")
+
+	stdout, _ := redirectOutput()
+	_, err := log.Replay(deadLetter)
+
+	assert.NoError(t, err, "replay")
+	assert.Equal(t, "test | INFO  | lost entry.id="+id+"\n", stdout.String(), "same-id")
+
+}
+
+func Test_EntryIDs_Disabled(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+
+	log.Info("message")
+
+	assert.Equal(t, "test | INFO  | message\n", stdout.String())
+
+}
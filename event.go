@@ -0,0 +1,64 @@
+package log
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// Events is the writer to where the events should be written (defaults to Stdout when nil)
+var Events io.Writer
+
+type eventEntry struct {
+	Time          string                 `json:"time"`
+	Event         string                 `json:"event"`
+	Properties    map[string]interface{} `json:"properties"`
+	SchemaVersion int                    `json:"schema_version"`
+}
+
+// Event writes an analytics-style event with the given name and properties as a single JSON line
+//
+// Events are not filtered by DebugMode and are written to Events instead of the regular log output. When the event
+// can't be encoded or written, the error is logged and returned.
+func Event(name string, properties map[string]interface{}) error {
+
+	if properties == nil {
+		properties = map[string]interface{}{}
+	}
+
+	now := time.Now()
+
+	err := writeJSONLine("events", Events, now, eventEntry{
+		Time:          now.UTC().Format(time.RFC3339Nano),
+		Event:         name,
+		Properties:    applyAllowList(properties),
+		SchemaVersion: SchemaVersion,
+	})
+	if err != nil {
+		Error("Failed to write event", name+":", err)
+	}
+
+	return err
+
+}
+
+func writeJSONLine(sinkName string, w io.Writer, t time.Time, v interface{}) error {
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	logMutex.Lock()
+	defer logMutex.Unlock()
+
+	if w == nil {
+		w = Stdout
+	}
+
+	n, err := w.Write(append(data, '\n'))
+	health.recordWrite(sinkName, t, n, err)
+
+	return err
+
+}
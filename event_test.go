@@ -0,0 +1,91 @@
+package log_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func Test_Event(t *testing.T) {
+
+	resetLogConfig()
+	stdout, stderr := redirectOutput()
+	defer resetLogOutput()
+
+	log.Event("user_signup", map[string]interface{}{"plan": "pro", "seats": 3})
+
+	var actual map[string]interface{}
+	err := json.Unmarshal(stdout.Bytes(), &actual)
+
+	assert.NoError(t, err, "json")
+	assert.Equal(t, "user_signup", actual["event"], "event")
+	assert.Equal(t, map[string]interface{}{"plan": "pro", "seats": 3.0}, actual["properties"], "properties")
+	assert.Equal(t, "", stderr.String(), "stderr")
+
+	_, err = time.Parse(time.RFC3339Nano, actual["time"].(string))
+	assert.NoError(t, err, "time")
+
+}
+
+func Test_Event_Writer(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+
+	events := bytes.NewBufferString("")
+	log.Events = events
+	defer func() {
+		log.Events = nil
+	}()
+
+	log.Event("user_login", nil)
+
+	assert.Equal(t, "", stdout.String(), "stdout")
+	assert.Contains(t, events.String(), `"event":"user_login","properties":{}`, "events")
+
+}
+
+func Test_Event_Invalid(t *testing.T) {
+
+	resetLogConfig()
+	stdout, stderr := redirectOutput()
+	defer resetLogOutput()
+
+	err := log.Event("invalid", map[string]interface{}{"func": func() {}})
+
+	assert.Error(t, err, "error")
+	assert.Equal(t, "", stdout.String(), "stdout")
+	assert.Contains(t, stderr.String(), "test | ERROR | Failed to write event invalid:", "stderr")
+
+}
+
+func Test_Event_WriteFailed(t *testing.T) {
+
+	resetLogConfig()
+	redirectOutput()
+	defer resetLogOutput()
+	defer resetDeadLetterConfig()
+
+	deadLetter := &bytes.Buffer{}
+	log.DeadLetter = deadLetter
+	log.Stderr = failingWriter{}
+	log.Events = failingWriter{}
+	defer func() {
+		log.Events = nil
+	}()
+
+	err := log.Event("user_login", nil)
+
+	assert.EqualError(t, err, "write failed", "error")
+	assert.Contains(t, deadLetter.String(), `"message":"Failed to write event user_login: write failed"`, "dead letter")
+
+	stats, _ := log.SinkStatsFor("events")
+	assert.True(t, stats.Errors > 0, "stats")
+
+}
@@ -0,0 +1,117 @@
+package log
+
+import (
+	"fmt"
+	"sync"
+)
+
+// FatalPanic indicates if fatal errors panic with a FatalExit instead of exiting the program directly
+//
+// Exiting skips the deferred functions, so the cleanups don't run and the entries they log are lost. When enabled,
+// the deferred functions run while the panic unwinds and HandleFatal, deferred in main, exits the program once they
+// are done.
+var FatalPanic = false
+
+// FatalExit is the value of the panic raised by the fatal errors when FatalPanic is enabled
+type FatalExit struct {
+	Code int
+}
+
+// Error returns a description of the fatal exit, which is shown when the panic isn't handled by HandleFatal
+func (e FatalExit) Error() string {
+	return fmt.Sprintf("log: fatal error (exit code %d), defer log.HandleFatal() in main to exit cleanly", e.Code)
+}
+
+var exitHooksMutex = &sync.Mutex{}
+var exitHooks []*func()
+
+// AddExitHook registers fn so that it's called before the program exits because of a fatal error and returns a
+// function which unregisters it
+//
+// The hooks are called in the reverse order of their registration, as deferred functions are, and the entries are
+// flushed after them.
+func AddExitHook(fn func()) func() {
+
+	registered := &fn
+
+	exitHooksMutex.Lock()
+	exitHooks = append(exitHooks, registered)
+	exitHooksMutex.Unlock()
+
+	return func() {
+		exitHooksMutex.Lock()
+		defer exitHooksMutex.Unlock()
+		for i, hook := range exitHooks {
+			if hook == registered {
+				exitHooks = append(exitHooks[:i:i], exitHooks[i+1:]...)
+				return
+			}
+		}
+	}
+
+}
+
+// HandleFatal exits the program with the code of the FatalExit it recovers, after calling the exit hooks and
+// flushing, it has to be deferred as the first statement of main when FatalPanic is enabled
+//
+// Other panics are raised again. Recover, SafeGo and the other functions of this package which recover panics exit
+// the same way when they recover a FatalExit, as it can't reach main from another goroutine.
+func HandleFatal() {
+	if r := recover(); r != nil && !exitOnFatal(r) {
+		panic(r)
+	}
+}
+
+// exitOnFatal exits the program when r is a FatalExit and reports if it was one
+func exitOnFatal(r interface{}) bool {
+
+	fatal, ok := r.(FatalExit)
+	if !ok {
+		return false
+	}
+
+	runExitHooks()
+	std.flush()
+	OsExit(fatal.Code)
+
+	return true
+
+}
+
+// exit exits the program because of a fatal error, or panics with a FatalExit when FatalPanic is enabled
+func exit(l *Logger, code int) {
+
+	if FatalPanic {
+		// The entries are flushed first in case the panic isn't handled
+		l.flush()
+		panic(FatalExit{Code: code})
+	}
+
+	runExitHooks()
+	l.flush()
+	OsExit(code)
+
+}
+
+// runExitHooks calls the exit hooks in reverse order, a hook which panics is recovered so that the others still run
+func runExitHooks() {
+
+	exitHooksMutex.Lock()
+	hooks := exitHooks
+	exitHooksMutex.Unlock()
+
+	for i := len(hooks) - 1; i >= 0; i-- {
+		runExitHook(*hooks[i])
+	}
+
+}
+
+func runExitHook(fn func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			message := "Recovered from panic in exit hook: " + formatPanicValue(r) + "\n" + panicStack()
+			printEntry("ERROR", message, map[string]interface{}{"recovered": true})
+		}
+	}()
+	fn()
+}
@@ -0,0 +1,197 @@
+package log_test
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+// exitRecorder replaces OsExit and records the calls, in order with the other steps
+type exitRecorder struct {
+	mutex  sync.Mutex
+	steps  []string
+	exited chan int
+}
+
+func recordExits() (*exitRecorder, func()) {
+
+	r := &exitRecorder{exited: make(chan int, 1)}
+
+	oldOsExit := log.OsExit
+	log.OsExit = func(code int) {
+		r.step("exit")
+		r.exited <- code
+	}
+
+	return r, func() {
+		log.OsExit = oldOsExit
+		log.FatalPanic = false
+	}
+
+}
+
+func (r *exitRecorder) step(name string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.steps = append(r.steps, name)
+}
+
+func (r *exitRecorder) recorded() []string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]string{}, r.steps...)
+}
+
+func Test_AddExitHook(t *testing.T) {
+
+	resetLogConfig()
+	_, stderr := redirectOutput()
+	defer resetLogOutput()
+
+	recorder, restore := recordExits()
+	defer restore()
+
+	defer log.AddExitHook(func() { recorder.step("first") })()
+	defer log.AddExitHook(func() { recorder.step("second") })()
+	removed := log.AddExitHook(func() { recorder.step("removed") })
+	removed()
+
+	log.Fatal("fatal error")
+
+	assert.Equal(t, []string{"second", "first", "exit"}, recorder.recorded(), "steps")
+	assert.Equal(t, 1, <-recorder.exited, "exit-code")
+	assert.True(t, strings.HasPrefix(stderr.String(), "test | FATAL | fatal error\n"), "stderr")
+
+}
+
+func Test_AddExitHook_Panic(t *testing.T) {
+
+	resetLogConfig()
+	_, stderr := redirectOutput()
+	defer resetLogOutput()
+
+	recorder, restore := recordExits()
+	defer restore()
+
+	defer log.AddExitHook(func() { recorder.step("first") })()
+	defer log.AddExitHook(func() { panic("hook failed") })()
+
+	log.CheckError(errors.New("fatal error"))
+
+	assert.Equal(t, []string{"first", "exit"}, recorder.recorded(), "steps")
+	assert.Contains(t, stderr.String(), "Recovered from panic in exit hook: hook failed", "stderr")
+
+}
+
+func Test_FatalPanic(t *testing.T) {
+
+	type test struct {
+		name  string
+		fatal func()
+	}
+
+	var tests = []test{
+		{"fatal", func() { log.Fatal("fatal error") }},
+		{"fatalf", func() { log.Fatalf("fatal %s", "error") }},
+		{"check-error", func() { log.CheckError(errors.New("fatal error")) }},
+		{"logger", func() { log.WithField("key", "value").Fatal("fatal error") }},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			resetLogConfig()
+			stdout, stderr := redirectOutput()
+			defer resetLogOutput()
+
+			recorder, restore := recordExits()
+			defer restore()
+			log.FatalPanic = true
+
+			defer log.AddExitHook(func() { recorder.step("hook") })()
+
+			func() {
+				defer log.HandleFatal()
+				defer func() {
+					recorder.step("deferred")
+					log.Info("cleanup")
+				}()
+				tc.fatal()
+				recorder.step("after-fatal")
+			}()
+
+			assert.Equal(t, []string{"deferred", "hook", "exit"}, recorder.recorded(), "steps")
+			assert.Equal(t, 1, <-recorder.exited, "exit-code")
+			assert.Contains(t, stderr.String(), "FATAL | fatal error", "stderr")
+			assert.Equal(t, "test | INFO  | cleanup\n", stdout.String(), "stdout")
+
+		})
+	}
+
+}
+
+func Test_FatalPanic_SafeGo(t *testing.T) {
+
+	resetLogConfig()
+	_, stderr := redirectOutput()
+	defer resetLogOutput()
+
+	recorder, restore := recordExits()
+	defer restore()
+	log.FatalPanic = true
+
+	log.SafeGo(func() {
+		log.Fatal("fatal error")
+	}, func() {
+		recorder.step("restart")
+	})
+
+	select {
+	case code := <-recorder.exited:
+		assert.Equal(t, 1, code, "exit-code")
+	case <-time.After(2 * time.Second):
+		t.Fatal("not exited")
+	}
+
+	assert.Equal(t, []string{"exit"}, recorder.recorded(), "steps")
+	assert.NotContains(t, stderr.String(), "Recovered from panic", "stderr")
+
+}
+
+func Test_HandleFatal_OtherPanic(t *testing.T) {
+
+	recorder, restore := recordExits()
+	defer restore()
+
+	assert.PanicsWithValue(t, "other", func() {
+		defer log.HandleFatal()
+		panic("other")
+	}, "panic")
+
+	assert.Empty(t, recorder.recorded(), "steps")
+
+}
+
+func Test_FatalExit_Unhandled(t *testing.T) {
+
+	resetLogConfig()
+	redirectOutput()
+	defer resetLogOutput()
+
+	_, restore := recordExits()
+	defer restore()
+	log.FatalPanic = true
+
+	assert.PanicsWithValue(t, log.FatalExit{Code: 1}, func() {
+		log.Fatal("fatal error")
+	}, "panic")
+
+	assert.Equal(t, "log: fatal error (exit code 1), defer log.HandleFatal() in main to exit cleanly", log.FatalExit{Code: 1}.Error())
+
+}
@@ -0,0 +1,108 @@
+package log
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// ErrInvalidRouteValue is returned when a field value can't be used to select a sink, such as a file name with a
+// path separator
+var ErrInvalidRouteValue = errors.New("invalid route value")
+
+// FieldRoute routes the entries which have a value for Field to the sink returned by Sink for that value
+//
+// Sink is called once per value, the returned writer is reused until CloseFieldRoutes is called. When it returns an
+// error, the entry is handled as a failed write (see DeadLetter) instead of being written to the default outputs.
+type FieldRoute struct {
+	Field string
+	Sink  func(value string) (io.Writer, error)
+}
+
+// FieldRoutes are evaluated in order against every entry which isn't routed by a transform rule, the first route
+// whose field is present selects the sink
+var FieldRoutes = []FieldRoute{}
+
+// fieldRouteSinks are the writers returned by the routes, keyed by field and value
+var fieldRouteSinks = map[string]io.Writer{}
+
+// fieldRouteNames are the names of all sinks selected by the routes, including the ones which failed to open
+var fieldRouteNames = map[string]bool{}
+
+// RouteToFiles returns a FieldRoute sink which writes the entries to a file per value in dir (e.g. "<dir>/acme.log")
+func RouteToFiles(dir string, opts FileOptions) func(value string) (io.Writer, error) {
+	return func(value string) (io.Writer, error) {
+		if value == "" || value == "." || value == ".." || strings.ContainsAny(value, `/\`+"\x00") {
+			return nil, ErrInvalidRouteValue
+		}
+		return OpenFileSink(filepath.Join(dir, value+".log"), opts)
+	}
+}
+
+// CloseFieldRoutes closes the sinks opened by the field routes and removes them from the Health status
+func CloseFieldRoutes() error {
+
+	logMutex.Lock()
+	defer logMutex.Unlock()
+
+	var result error
+	for key, w := range fieldRouteSinks {
+		if c, ok := w.(io.Closer); ok {
+			if err := c.Close(); err != nil && result == nil {
+				result = err
+			}
+		}
+		delete(fieldRouteSinks, key)
+	}
+
+	for name := range fieldRouteNames {
+		health.removeSink(name)
+		delete(fieldRouteNames, name)
+	}
+
+	return result
+
+}
+
+// writerForFields returns the sink of the first field route matching the entry and must be called while holding
+// logMutex
+func writerForFields(e entry) (io.Writer, string, bool) {
+
+	for _, route := range FieldRoutes {
+
+		value, ok := e.fields[route.Field]
+		if !ok || route.Sink == nil {
+			continue
+		}
+
+		name := route.Field + "=" + fmt.Sprint(value)
+		if w, ok := fieldRouteSinks[name]; ok {
+			return w, name, true
+		}
+
+		fieldRouteNames[name] = true
+
+		w, err := route.Sink(fmt.Sprint(value))
+		if err != nil {
+			return errorWriter{err}, name, true
+		}
+
+		fieldRouteSinks[name] = w
+		return w, name, true
+
+	}
+
+	return nil, "", false
+
+}
+
+// errorWriter fails every write with its error
+type errorWriter struct {
+	err error
+}
+
+func (w errorWriter) Write(p []byte) (int, error) {
+	return 0, w.err
+}
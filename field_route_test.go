@@ -0,0 +1,134 @@
+package log_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func resetFieldRoutes() {
+	log.CloseFieldRoutes()
+	log.FieldRoutes = []log.FieldRoute{}
+}
+
+func Test_FieldRoutes(t *testing.T) {
+
+	resetLogConfig()
+	stdout, stderr := redirectOutput()
+	defer resetLogOutput()
+	defer resetFieldRoutes()
+
+	sinks := map[string]*bytes.Buffer{}
+	opened := 0
+
+	log.FieldRoutes = []log.FieldRoute{
+		{Field: "tenant", Sink: func(value string) (io.Writer, error) {
+			opened++
+			sinks[value] = &bytes.Buffer{}
+			return sinks[value], nil
+		}},
+	}
+
+	log.WithField("tenant", "acme").Info("first")
+	log.WithField("tenant", "globex").Error("second")
+	log.WithField("tenant", "acme").Warn("third")
+	log.Info("shared")
+
+	assert.Equal(t, 2, opened, "opened")
+	assert.Equal(t, "test | INFO  | first tenant=acme\ntest | WARN  | third tenant=acme\n", sinks["acme"].String(), "acme")
+	assert.Equal(t, "test | ERROR | second tenant=globex\n", sinks["globex"].String(), "globex")
+	assert.Equal(t, "test | INFO  | shared\n", stdout.String(), "stdout")
+	assert.Empty(t, stderr.String(), "stderr")
+
+}
+
+func Test_FieldRoutes_TransformRoute(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+	defer resetFieldRoutes()
+	defer resetTransformRules()
+
+	tenant := &bytes.Buffer{}
+	log.FieldRoutes = []log.FieldRoute{
+		{Field: "tenant", Sink: func(value string) (io.Writer, error) {
+			return tenant, nil
+		}},
+	}
+	log.TransformRules = []log.TransformRule{
+		{Match: log.TransformMatch{Fields: map[string]string{"audit": "*"}}, Route: "stdout"},
+	}
+
+	log.WithFields(map[string]interface{}{"tenant": "acme", "audit": true}).Info("audited")
+
+	assert.Equal(t, "test | INFO  | audited audit=true tenant=acme\n", stdout.String(), "stdout")
+	assert.Empty(t, tenant.String(), "tenant")
+
+}
+
+func Test_FieldRoutes_SinkError(t *testing.T) {
+
+	resetLogConfig()
+	stdout, stderr := redirectOutput()
+	defer resetLogOutput()
+	defer resetFieldRoutes()
+	defer func() {
+		log.DeadLetter = nil
+	}()
+
+	deadLetter := &bytes.Buffer{}
+	log.DeadLetter = deadLetter
+	log.FieldRoutes = []log.FieldRoute{
+		{Field: "tenant", Sink: func(value string) (io.Writer, error) {
+			return nil, errors.New("unavailable")
+		}},
+	}
+
+	log.WithField("tenant", "acme").Info("message")
+
+	assert.Empty(t, stdout.String(), "stdout")
+	assert.Empty(t, stderr.String(), "stderr")
+	assert.Contains(t, deadLetter.String(), `"message":"message"`, "dead-letter")
+
+}
+
+func Test_RouteToFiles(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+	defer resetFieldRoutes()
+
+	dir, _ := ioutil.TempDir("", "go-log")
+	defer os.RemoveAll(dir)
+
+	log.FieldRoutes = []log.FieldRoute{
+		{Field: "tenant", Sink: log.RouteToFiles(filepath.Join(dir, "tenants"), log.FileOptions{CreateDirs: true})},
+	}
+
+	log.WithField("tenant", "acme").Info("acme")
+	log.WithField("tenant", "globex").Info("globex")
+	log.WithField("tenant", "../escape").Info("escape")
+
+	assert.NoError(t, log.CloseFieldRoutes(), "close")
+
+	actual, _ := ioutil.ReadFile(filepath.Join(dir, "tenants", "acme.log"))
+	assert.Equal(t, "test | INFO  | acme tenant=acme\n", string(actual), "acme")
+
+	actual, _ = ioutil.ReadFile(filepath.Join(dir, "tenants", "globex.log"))
+	assert.Equal(t, "test | INFO  | globex tenant=globex\n", string(actual), "globex")
+
+	_, err := os.Stat(filepath.Join(dir, "escape.log"))
+	assert.True(t, os.IsNotExist(err), "escape")
+	assert.Empty(t, stdout.String(), "stdout")
+
+}
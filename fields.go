@@ -0,0 +1,16 @@
+package log
+
+// WithField returns a logger which adds the field to all its entries and uses the package settings
+func WithField(key string, value interface{}) *Logger {
+	return std.WithField(key, value)
+}
+
+// WithFields returns a logger which adds the fields to all its entries and uses the package settings
+//
+// The fields are shown as key=value pairs in the text format and as JSON keys in the Datadog format:
+//
+//	logger := log.WithFields(map[string]interface{}{"request_id": id, "user_id": user})
+//	logger.Info("Order placed")
+func WithFields(fields map[string]interface{}) *Logger {
+	return std.WithFields(fields)
+}
@@ -0,0 +1,78 @@
+package log_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func Test_WithFields(t *testing.T) {
+
+	resetLogConfig()
+	stdout, stderr := redirectOutput()
+	defer resetLogOutput()
+
+	logger := log.WithFields(map[string]interface{}{"request_id": "abc", "user_id": 42})
+	logger.Info("order placed")
+	logger.WithField("order_id", "o-1").Error("payment failed")
+	log.Info("plain")
+
+	log.DebugMode = true
+	logger.Debug("follows globals")
+
+	assert.Equal(t, "test | INFO  | order placed request_id=abc user_id=42\ntest | INFO  | plain\ntest | DEBUG | follows globals request_id=abc user_id=42\n", stdout.String(), "stdout")
+	assert.Equal(t, "test | ERROR | payment failed order_id=o-1 request_id=abc user_id=42\n", stderr.String(), "stderr")
+
+}
+
+func Test_WithFields_Precedence(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+
+	parent := log.WithField("component", "api")
+	child := parent.WithField("component", "worker")
+
+	parent.Info("parent")
+	child.Info("child")
+
+	assert.Equal(t, "test | INFO  | parent component=api\ntest | INFO  | child component=worker\n", stdout.String())
+
+}
+
+func Test_WithFields_Instance(t *testing.T) {
+
+	stdout := &bytes.Buffer{}
+	logger := log.New(log.WithOutput(stdout, stdout)).WithField("library", "cache")
+
+	logger.Warn("evicted")
+
+	assert.Equal(t, "evicted library=cache\n", stdout.String())
+
+}
+
+func Test_WithFields_Datadog(t *testing.T) {
+
+	resetLogConfig()
+	redirectOutput()
+	defer resetLogOutput()
+	defer resetAllowListConfig()
+
+	stdout := &bytes.Buffer{}
+	log.Stdout = stdout
+	log.Format = log.FormatDatadog
+
+	log.WithFields(map[string]interface{}{"request_id": "abc", "user_id": 42}).Info("order placed")
+
+	var values map[string]interface{}
+	assert.NoError(t, json.Unmarshal(stdout.Bytes(), &values), "json")
+	assert.Equal(t, "abc", values["request_id"], "request_id")
+	assert.Equal(t, float64(42), values["user_id"], "user_id")
+	assert.Equal(t, "order placed", values["message"], "message")
+
+}
@@ -0,0 +1,177 @@
+package log
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// ErrSinkClosed is returned when writing to a sink which is closed
+var ErrSinkClosed = errors.New("sink is closed")
+
+// AtomicWriteLimit is the size up to which an entry written to a FileSink is written using a single write system
+// call on a file opened with O_APPEND, so that processes appending to the same file never interleave within an entry
+//
+// Larger entries are written while holding the advisory lock on the file (on the platforms which support it).
+var AtomicWriteLimit = 4096
+
+// FileMode defines what happens when a log file already exists
+type FileMode int
+
+const (
+	// FileAppend appends to an existing file
+	FileAppend FileMode = iota
+
+	// FileTruncate truncates an existing file
+	FileTruncate
+
+	// FileExclusive fails when the file already exists
+	FileExclusive
+)
+
+// FileOptions are the options used to open a FileSink
+type FileOptions struct {
+	// Mode defines what happens when the file already exists (defaults to FileAppend)
+	Mode FileMode
+
+	// Perm are the permissions of the file when it's created (defaults to 0644)
+	Perm os.FileMode
+
+	// IgnoreUmask applies Perm exactly, regardless of the umask of the process
+	IgnoreUmask bool
+
+	// CreateDirs creates the missing parent directories
+	CreateDirs bool
+
+	// DirPerm are the permissions of the created parent directories (defaults to 0755)
+	DirPerm os.FileMode
+
+	// Lock takes an exclusive advisory lock on the file around each write, for files which are shared by multiple
+	// processes (not supported on all platforms)
+	Lock bool
+}
+
+// FileSink is a writer which writes the log entries to a file
+//
+// It can be used as Stdout, Stderr or Events. Each entry is passed to Write as a single buffer, see
+// AtomicWriteLimit for the guarantees this gives when multiple processes write to the same file.
+type FileSink struct {
+	mutex sync.Mutex
+	path  string
+	opts  FileOptions
+	file  *os.File
+}
+
+// OpenFileSink opens the file at path for writing log entries
+func OpenFileSink(path string, opts FileOptions) (*FileSink, error) {
+
+	if opts.Perm == 0 {
+		opts.Perm = 0644
+	}
+	if opts.DirPerm == 0 {
+		opts.DirPerm = 0755
+	}
+
+	if opts.CreateDirs {
+		if err := os.MkdirAll(filepath.Dir(path), opts.DirPerm); err != nil {
+			return nil, err
+		}
+	}
+
+	s := &FileSink{path: path, opts: opts}
+	if err := s.open(opts.Mode); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+
+}
+
+// Path returns the path of the file
+func (s *FileSink) Path() string {
+	return s.path
+}
+
+// Reopen closes and reopens the file, for files which are renamed by an external tool (such as logrotate)
+func (s *FileSink) Reopen() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.file == nil {
+		return ErrSinkClosed
+	}
+	if err := s.file.Close(); err != nil {
+		return err
+	}
+	s.file = nil
+	return s.open(FileAppend)
+}
+
+// open must be called while holding the mutex (or before the sink is shared)
+func (s *FileSink) open(mode FileMode) error {
+
+	flags := os.O_WRONLY | os.O_CREATE
+	switch mode {
+	case FileTruncate:
+		flags |= os.O_TRUNC | os.O_APPEND
+	case FileExclusive:
+		flags |= os.O_EXCL | os.O_APPEND
+	default:
+		flags |= os.O_APPEND
+	}
+
+	file, err := openFile(s.path, flags, s.opts.Perm)
+	if err != nil {
+		return err
+	}
+
+	if s.opts.IgnoreUmask {
+		if err := file.Chmod(s.opts.Perm); err != nil {
+			file.Close()
+			return err
+		}
+	}
+
+	s.file = file
+	return nil
+
+}
+
+// Write writes p to the file
+func (s *FileSink) Write(p []byte) (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.file == nil {
+		return 0, ErrSinkClosed
+	}
+	if !s.opts.Lock && len(p) <= AtomicWriteLimit {
+		return s.file.Write(p)
+	}
+	if err := lockFile(s.file); err != nil {
+		return 0, err
+	}
+	defer unlockFile(s.file)
+	return s.file.Write(p)
+}
+
+// Sync commits the contents of the file to stable storage
+func (s *FileSink) Sync() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.file == nil {
+		return ErrSinkClosed
+	}
+	return s.file.Sync()
+}
+
+// Close closes the file
+func (s *FileSink) Close() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
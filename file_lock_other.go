@@ -0,0 +1,16 @@
+//go:build tinygo || (!darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !windows)
+// +build tinygo !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!windows
+
+package log
+
+import (
+	"os"
+)
+
+func lockFile(file *os.File) error {
+	return nil
+}
+
+func unlockFile(file *os.File) error {
+	return nil
+}
@@ -0,0 +1,18 @@
+//go:build (darwin || dragonfly || freebsd || linux || netbsd || openbsd) && !tinygo
+// +build darwin dragonfly freebsd linux netbsd openbsd
+// +build !tinygo
+
+package log
+
+import (
+	"os"
+	"syscall"
+)
+
+func lockFile(file *os.File) error {
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_EX)
+}
+
+func unlockFile(file *os.File) error {
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+}
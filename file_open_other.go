@@ -0,0 +1,12 @@
+//go:build !windows || tinygo
+// +build !windows tinygo
+
+package log
+
+import (
+	"os"
+)
+
+func openFile(path string, flag int, perm os.FileMode) (*os.File, error) {
+	return os.OpenFile(path, flag, perm)
+}
@@ -0,0 +1,228 @@
+package log_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func writeToFileSink(t *testing.T, path string, opts log.FileOptions, message string) error {
+	sink, err := log.OpenFileSink(path, opts)
+	if err != nil {
+		return err
+	}
+	defer sink.Close()
+
+	log.Stdout = sink
+	defer resetLogOutput()
+
+	log.Info(message)
+	return nil
+}
+
+func Test_FileSink_Modes(t *testing.T) {
+
+	type test struct {
+		name     string
+		mode     log.FileMode
+		expected string
+		err      bool
+	}
+
+	var tests = []test{
+		{"append", log.FileAppend, "existing\ntest | INFO  | message\n", false},
+		{"truncate", log.FileTruncate, "test | INFO  | message\n", false},
+		{"exclusive", log.FileExclusive, "existing\n", true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			resetLogConfig()
+
+			dir, _ := ioutil.TempDir("", "go-log")
+			defer os.RemoveAll(dir)
+
+			path := filepath.Join(dir, "app.log")
+			ioutil.WriteFile(path, []byte("existing\n"), 0644)
+
+			err := writeToFileSink(t, path, log.FileOptions{Mode: tc.mode}, "message")
+			assert.Equal(t, tc.err, err != nil, "error")
+
+			actual, _ := ioutil.ReadFile(path)
+			assert.Equal(t, tc.expected, string(actual), "contents")
+
+		})
+	}
+
+}
+
+func Test_FileSink_CreateDirs(t *testing.T) {
+
+	resetLogConfig()
+
+	dir, _ := ioutil.TempDir("", "go-log")
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "logs", "app", "app.log")
+
+	err := writeToFileSink(t, path, log.FileOptions{}, "message")
+	assert.Error(t, err, "without-create-dirs")
+
+	err = writeToFileSink(t, path, log.FileOptions{CreateDirs: true, Mode: log.FileExclusive, Perm: 0600, IgnoreUmask: true}, "message")
+	assert.NoError(t, err, "with-create-dirs")
+
+	actual, _ := ioutil.ReadFile(path)
+	assert.Equal(t, "test | INFO  | message\n", string(actual), "contents")
+
+	if runtime.GOOS != "windows" {
+		info, _ := os.Stat(path)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm(), "perm")
+	}
+
+}
+
+func Test_FileSink_Closed(t *testing.T) {
+
+	dir, _ := ioutil.TempDir("", "go-log")
+	defer os.RemoveAll(dir)
+
+	sink, err := log.OpenFileSink(filepath.Join(dir, "app.log"), log.FileOptions{})
+	assert.NoError(t, err, "open")
+	assert.Equal(t, filepath.Join(dir, "app.log"), sink.Path(), "path")
+
+	assert.NoError(t, sink.Close(), "close")
+	assert.NoError(t, sink.Close(), "close-twice")
+
+	_, err = sink.Write([]byte("message\n"))
+	assert.Equal(t, log.ErrSinkClosed, err, "write")
+
+}
+
+func Test_FileSink_Lock(t *testing.T) {
+
+	resetLogConfig()
+
+	dir, _ := ioutil.TempDir("", "go-log")
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "app.log")
+
+	sinks := []*log.FileSink{}
+	for i := 0; i < 2; i++ {
+		sink, err := log.OpenFileSink(path, log.FileOptions{Lock: true})
+		assert.NoError(t, err, "open")
+		defer sink.Close()
+		sinks = append(sinks, sink)
+	}
+
+	done := make(chan struct{})
+	for _, sink := range sinks {
+		go func(sink *log.FileSink) {
+			for i := 0; i < 100; i++ {
+				sink.Write([]byte("0123456789\n"))
+			}
+			done <- struct{}{}
+		}(sink)
+	}
+	<-done
+	<-done
+
+	actual, _ := ioutil.ReadFile(path)
+	lines := strings.Split(strings.TrimSpace(string(actual)), "\n")
+
+	assert.Len(t, lines, 200, "lines")
+	for _, line := range lines {
+		assert.Equal(t, "0123456789", line, "line")
+	}
+
+}
+
+type countingWriter struct {
+	writes []string
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.writes = append(w.writes, string(p))
+	return len(p), nil
+}
+
+func Test_SingleWritePerEntry(t *testing.T) {
+
+	resetLogConfig()
+	redirectOutput()
+	defer resetLogOutput()
+	defer resetCallerConfig()
+
+	w := &countingWriter{}
+	log.Stdout = w
+	log.ReportCaller = true
+	log.DebugMode = true
+
+	log.Info("info")
+	log.DebugDump(map[string]string{"hello": "world"}, "prefix")
+	log.LogBreakerStateChange("db", log.BreakerClosed, log.BreakerOpen, 0.5)
+	log.Event("event", map[string]interface{}{"key": "value"})
+
+	assert.Len(t, w.writes, 4, "writes")
+	for _, write := range w.writes {
+		assert.True(t, strings.HasSuffix(write, "\n"), "complete-entry")
+	}
+
+}
+
+func Test_FileSink_LargeEntry(t *testing.T) {
+
+	resetLogConfig()
+	defer resetLogOutput()
+
+	dir, _ := ioutil.TempDir("", "go-log")
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "app.log")
+
+	sink, err := log.OpenFileSink(path, log.FileOptions{Mode: log.FileTruncate})
+	assert.NoError(t, err, "open")
+	defer sink.Close()
+
+	log.Stdout = sink
+	large := strings.Repeat("x", log.AtomicWriteLimit*2)
+	log.Info(large)
+
+	actual, _ := ioutil.ReadFile(path)
+	assert.Equal(t, "test | INFO  | "+large+"\n", string(actual), "contents")
+
+}
+
+func Test_FileSink_Reopen(t *testing.T) {
+
+	dir, _ := ioutil.TempDir("", "go-log")
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "app.log")
+
+	sink, err := log.OpenFileSink(path, log.FileOptions{})
+	assert.NoError(t, err, "open")
+
+	sink.Write([]byte("first\n"))
+	assert.NoError(t, os.Rename(path, path+".1"), "rename")
+	assert.NoError(t, sink.Reopen(), "reopen")
+	sink.Write([]byte("second\n"))
+
+	assert.NoError(t, sink.Close(), "close")
+	assert.Equal(t, log.ErrSinkClosed, sink.Reopen(), "reopen-closed")
+
+	actual, _ := ioutil.ReadFile(path + ".1")
+	assert.Equal(t, "first\n", string(actual), "rotated")
+
+	actual, _ = ioutil.ReadFile(path)
+	assert.Equal(t, "second\n", string(actual), "current")
+
+}
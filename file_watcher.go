@@ -0,0 +1,171 @@
+package log
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+type watchedFile struct {
+	modTime time.Time
+	size    int64
+	hash    string
+}
+
+// WatchFiles polls the given files and directories every interval and logs their changes as info
+//
+// Directories are watched non-recursively. Each entry has the fields "path", "change" (created, modified or
+// deleted) and the SHA-256 hashes of the contents ("sha256" and "previous_sha256"), so drift of configuration files
+// can be detected and verified. Files whose modification time changes while their contents stay the same are not
+// logged. Calling the returned function stops watching, it returns once the changes are no longer logged.
+func WatchFiles(paths []string, interval time.Duration) (func(), error) {
+
+	for _, path := range paths {
+		if _, err := os.Stat(path); err != nil {
+			return nil, err
+		}
+	}
+
+	files := scanWatchedFiles(paths, nil)
+
+	stop := poll(interval, func() {
+		current := scanWatchedFiles(paths, files)
+		logFileChanges(files, current)
+		files = current
+	})
+
+	return stop, nil
+
+}
+
+// poll calls fn every interval in the background until the returned function is called, which waits for fn to
+// return when it's running
+func poll(interval time.Duration, fn func()) func() {
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	ticker := time.NewTicker(interval)
+
+	go func() {
+		defer close(done)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				fn()
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(stop) })
+		<-done
+	}
+
+}
+
+// scanWatchedFiles returns the state of the files in paths, hashes are reused from previous for unchanged files
+func scanWatchedFiles(paths []string, previous map[string]watchedFile) map[string]watchedFile {
+
+	files := map[string]watchedFile{}
+
+	add := func(path string, info os.FileInfo) {
+		file := watchedFile{modTime: info.ModTime(), size: info.Size()}
+		if old, ok := previous[path]; ok && old.modTime.Equal(file.modTime) && old.size == file.size {
+			file.hash = old.hash
+		} else {
+			file.hash = hashFile(path)
+		}
+		files[path] = file
+	}
+
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if !info.IsDir() {
+			add(path, info)
+			continue
+		}
+		children, err := ioutil.ReadDir(path)
+		if err != nil {
+			continue
+		}
+		for _, child := range children {
+			if !child.IsDir() {
+				add(filepath.Join(path, child.Name()), child)
+			}
+		}
+	}
+
+	return files
+
+}
+
+func hashFile(path string) string {
+
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return ""
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+
+}
+
+func logFileChanges(previous map[string]watchedFile, current map[string]watchedFile) {
+
+	paths := make([]string, 0, len(previous)+len(current))
+	for path := range previous {
+		paths = append(paths, path)
+	}
+	for path := range current {
+		if _, ok := previous[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+
+		old, existed := previous[path]
+		file, exists := current[path]
+
+		fields := map[string]interface{}{"path": path}
+
+		switch {
+		case !existed:
+			fields["change"] = "created"
+			fields["sha256"] = file.hash
+		case !exists:
+			fields["change"] = "deleted"
+			fields["previous_sha256"] = old.hash
+		case old.hash != file.hash:
+			fields["change"] = "modified"
+			fields["sha256"] = file.hash
+			fields["previous_sha256"] = old.hash
+		default:
+			continue
+		}
+
+		printEntry("INFO", "File changed", fields)
+
+	}
+
+}
@@ -0,0 +1,63 @@
+package log_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func waitForOutput(b *syncBuffer, substr string) bool {
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if strings.Contains(b.String(), substr) {
+			return true
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return false
+}
+
+func Test_WatchFiles(t *testing.T) {
+
+	resetLogConfig()
+	redirectOutput()
+	defer resetLogOutput()
+
+	stdout := &syncBuffer{}
+	log.Stdout = stdout
+
+	dir, _ := ioutil.TempDir("", "go-log")
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "app.conf")
+	ioutil.WriteFile(path, []byte("a"), 0644)
+
+	stop, err := log.WatchFiles([]string{dir}, 5*time.Millisecond)
+	assert.NoError(t, err, "watch")
+	defer stop()
+
+	created := filepath.Join(dir, "new.conf")
+	ioutil.WriteFile(created, []byte("b"), 0644)
+	assert.True(t, waitForOutput(stdout, "change=created path="+created+" sha256=3e23e8160039594a33894f6564e1b1348bbd7a0088d42c4acb73eeaed59c009d"), "created")
+
+	ioutil.WriteFile(path, []byte("changed"), 0644)
+	assert.True(t, waitForOutput(stdout, "change=modified path="+path+" previous_sha256=ca978112ca1bbdcafac231b39a23dc4da786eff8147c4e72b9807785afee48bb sha256="), "modified")
+
+	os.Remove(created)
+	assert.True(t, waitForOutput(stdout, "change=deleted path="+created+" previous_sha256=3e23e8160039594a33894f6564e1b1348bbd7a0088d42c4acb73eeaed59c009d"), "deleted")
+
+	stop()
+
+}
+
+func Test_WatchFiles_Missing(t *testing.T) {
+	_, err := log.WatchFiles([]string{filepath.Join("missing", "file")}, time.Second)
+	assert.Error(t, err)
+}
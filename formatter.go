@@ -0,0 +1,115 @@
+package log
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// OutputFormat defines how the log entries are formatted
+type OutputFormat int
+
+const (
+	// FormatText formats the entries as plain text lines
+	FormatText OutputFormat = iota
+
+	// FormatDatadog formats the entries as JSON lines using the Datadog reserved and standard attributes
+	FormatDatadog
+
+	// FormatLogfmt formats the entries as logfmt lines (level=info ts=... msg="..." key=value)
+	FormatLogfmt
+)
+
+var formatNames = map[OutputFormat]string{
+	FormatText:    "text",
+	FormatDatadog: "datadog",
+	FormatLogfmt:  "logfmt",
+}
+
+// Format is the format in which the log entries are written
+var Format = FormatText
+
+// String returns the name of the format (e.g. "logfmt")
+func (f OutputFormat) String() string {
+	if name, ok := formatNames[f]; ok {
+		return name
+	}
+	return fmt.Sprintf("OutputFormat(%d)", int(f))
+}
+
+// ParseFormat returns the format with the given name ("text", "datadog" or "logfmt")
+func ParseFormat(name string) (OutputFormat, error) {
+	for format, formatName := range formatNames {
+		if strings.EqualFold(strings.TrimSpace(name), formatName) {
+			return format, nil
+		}
+	}
+	return FormatText, fmt.Errorf("unknown format: %q", name)
+}
+
+func formatEntry(e entry, timestamp bool) []byte {
+	return formatEntryAs(e, Format, timestamp)
+}
+
+func formatEntryAs(e entry, format OutputFormat, timestamp bool) []byte {
+	switch format {
+	case FormatDatadog:
+		return formatDatadog(e, timestamp)
+	case FormatLogfmt:
+		return formatLogfmt(e, timestamp)
+	default:
+		return formatText(e, timestamp)
+	}
+}
+
+func formatText(e entry, timestamp bool) []byte {
+
+	message := e.message
+
+	if len(e.fields) > 0 {
+		message += " " + formatTextFields(e.fields)
+	}
+
+	if e.caller != "" {
+		message = e.caller + " | " + message
+	}
+
+	if e.goroutine != 0 {
+		message = "g:" + strconv.FormatUint(e.goroutine, 10) + " | " + message
+	}
+
+	if timestamp {
+		level := fmt.Sprintf("%-5s", e.level)
+		if e.color != "" {
+			level = e.color + level + ansiReset
+		}
+		message = formatTimestamp(e) + " | " + level + " | " + message
+	} else if e.color != "" {
+		message = e.color + message + ansiReset
+	}
+
+	return []byte(message + "\n")
+
+}
+
+func formatTextFields(fields map[string]interface{}) string {
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		value := formatValue(fields[key])
+		if value == "" || strings.ContainsAny(value, " \t\r\n\"=") {
+			value = strconv.Quote(value)
+		}
+		pairs = append(pairs, key+"="+value)
+	}
+
+	return strings.Join(pairs, " ")
+
+}
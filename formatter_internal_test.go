@@ -0,0 +1,30 @@
+package log
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_formatTextFields(t *testing.T) {
+
+	type test struct {
+		name     string
+		fields   map[string]interface{}
+		expected string
+	}
+
+	var tests = []test{
+		{"single", map[string]interface{}{"recovered": true}, "recovered=true"},
+		{"sorted", map[string]interface{}{"b": 2, "a": "1"}, "a=1 b=2"},
+		{"quoted", map[string]interface{}{"msg": "hello world", "empty": ""}, `empty="" msg="hello world"`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := formatTextFields(tc.fields)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+
+}
@@ -0,0 +1,188 @@
+package log
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// StackFrame is a frame of a stack trace as passed to the FrameResolver
+type StackFrame struct {
+	Function string
+	File     string
+	Line     int
+	PC       uintptr
+}
+
+// FrameResolver rewrites the frames of the stack traces before they are formatted, e.g. to map the names of a binary
+// built with an obfuscator back to the original names using its symbol map, so that the stack traces of production
+// builds can be resolved
+//
+// ResolveFrame must be safe for concurrent use and returns the frame unchanged when it can't resolve it.
+type FrameResolver interface {
+	ResolveFrame(frame StackFrame) StackFrame
+}
+
+// FrameResolverFunc is an adapter to use an ordinary function as a FrameResolver
+type FrameResolverFunc func(frame StackFrame) StackFrame
+
+// ResolveFrame calls f(frame)
+func (f FrameResolverFunc) ResolveFrame(frame StackFrame) StackFrame {
+	return f(frame)
+}
+
+var frameResolverMutex = &sync.RWMutex{}
+var frameResolver FrameResolver
+
+// SetFrameResolver installs the resolver used for the frames of the stack traces (nil removes it)
+func SetFrameResolver(r FrameResolver) {
+	frameResolverMutex.Lock()
+	frameResolver = r
+	frameResolverMutex.Unlock()
+}
+
+func currentFrameResolver() FrameResolver {
+	frameResolverMutex.RLock()
+	defer frameResolverMutex.RUnlock()
+	return frameResolver
+}
+
+// resolveFrame applies the installed FrameResolver to the frame
+func resolveFrame(frame runtime.Frame) runtime.Frame {
+
+	r := currentFrameResolver()
+	if r == nil {
+		return frame
+	}
+
+	resolved := r.ResolveFrame(StackFrame{Function: frame.Function, File: frame.File, Line: frame.Line, PC: frame.PC})
+
+	frame.Function, frame.File, frame.Line = resolved.Function, resolved.File, resolved.Line
+	return frame
+
+}
+
+// SymbolMap is a FrameResolver which replaces the obfuscated names in the frames by the original names
+type SymbolMap struct {
+	names map[string]string
+}
+
+// ReadSymbolMap reads a symbol map with a line for each obfuscated name followed by its original name, separated by
+// whitespace
+//
+// The names can be package paths, identifiers, functions (e.g. "pkg.Func") or file paths. Empty lines and lines
+// starting with "#" are skipped.
+func ReadSymbolMap(r io.Reader) (*SymbolMap, error) {
+
+	m := &SymbolMap{names: map[string]string{}}
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Fields(text)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("symbol map line %d: expected an obfuscated and an original name", line)
+		}
+		m.names[fields[0]] = fields[1]
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+
+}
+
+// LoadSymbolMap reads the symbol map from the file at path, see ReadSymbolMap
+func LoadSymbolMap(path string) (*SymbolMap, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadSymbolMap(f)
+}
+
+// ResolveFrame replaces the function and the file of the frame when they are in the map, otherwise their package
+// path, identifiers and file name are replaced separately
+func (m *SymbolMap) ResolveFrame(frame StackFrame) StackFrame {
+	frame.Function = m.resolveFunction(frame.Function)
+	frame.File = m.resolveFile(frame.File)
+	return frame
+}
+
+func (m *SymbolMap) resolveFunction(function string) string {
+
+	if original, ok := m.names[function]; ok {
+		return original
+	}
+
+	dir, last := path.Split(function)
+	i := strings.Index(last, ".")
+	if i < 0 {
+		return function
+	}
+	pkg, name := dir+last[:i], last[i+1:]
+
+	if original, ok := m.names[pkg]; ok {
+		pkg = original
+	}
+
+	return pkg + "." + m.resolveIdentifiers(name)
+
+}
+
+// resolveIdentifiers replaces the identifiers of a function name without its package (e.g. "(*T).Method.func1")
+func (m *SymbolMap) resolveIdentifiers(name string) string {
+
+	var b strings.Builder
+	start := -1
+
+	flush := func(end int) {
+		if start >= 0 {
+			identifier := name[start:end]
+			if original, ok := m.names[identifier]; ok {
+				identifier = original
+			}
+			b.WriteString(identifier)
+			start = -1
+		}
+	}
+
+	for i, c := range name {
+		if c == '.' || c == '(' || c == ')' || c == '*' || c == '[' || c == ']' {
+			flush(i)
+			b.WriteRune(c)
+		} else if start < 0 {
+			start = i
+		}
+	}
+	flush(len(name))
+
+	return b.String()
+
+}
+
+func (m *SymbolMap) resolveFile(file string) string {
+
+	if original, ok := m.names[file]; ok {
+		return original
+	}
+
+	dir, base := path.Split(strings.Replace(file, "\\", "/", -1))
+	if original, ok := m.names[base]; ok {
+		return dir + original
+	}
+
+	return file
+
+}
@@ -0,0 +1,130 @@
+package log_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+const testSymbolMap = `
+# obfuscated original
+Kx9f.Lq2 example.com/app/server.Start
+Kx9f example.com/app/server
+aB3 Handler
+cD4 ServeHTTP
+Zz1.go handler.go
+/build/Kx9f/Mm7.go /build/server/server.go
+`
+
+func Test_SymbolMap(t *testing.T) {
+
+	m, err := log.ReadSymbolMap(strings.NewReader(testSymbolMap))
+	assert.NoError(t, err, "read")
+
+	type test struct {
+		name     string
+		frame    log.StackFrame
+		expected log.StackFrame
+	}
+
+	var tests = []test{
+		{
+			"function",
+			log.StackFrame{Function: "Kx9f.Lq2", File: "/build/Kx9f/Mm7.go", Line: 10, PC: 1},
+			log.StackFrame{Function: "example.com/app/server.Start", File: "/build/server/server.go", Line: 10, PC: 1},
+		},
+		{
+			"method",
+			log.StackFrame{Function: "Kx9f.(*aB3).cD4.func1", File: "/build/Kx9f/Zz1.go", Line: 20},
+			log.StackFrame{Function: "example.com/app/server.(*Handler).ServeHTTP.func1", File: "/build/Kx9f/handler.go", Line: 20},
+		},
+		{
+			"package-path",
+			log.StackFrame{Function: "github.com/lib/Kx9f.aB3", File: "/mod/lib/other.go", Line: 30},
+			log.StackFrame{Function: "github.com/lib/Kx9f.Handler", File: "/mod/lib/other.go", Line: 30},
+		},
+		{
+			"unknown",
+			log.StackFrame{Function: "net/http.(*conn).serve", File: "/go/src/net/http/server.go", Line: 40},
+			log.StackFrame{Function: "net/http.(*conn).serve", File: "/go/src/net/http/server.go", Line: 40},
+		},
+		{
+			"no-package",
+			log.StackFrame{Function: "main", File: `C:\build\Zz1.go`, Line: 50},
+			log.StackFrame{Function: "main", File: "C:/build/handler.go", Line: 50},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := m.ResolveFrame(tc.frame)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+
+}
+
+func Test_ReadSymbolMap_Invalid(t *testing.T) {
+
+	_, err := log.ReadSymbolMap(strings.NewReader("Kx9f example.com/app/server\ninvalid\n"))
+	assert.EqualError(t, err, "symbol map line 2: expected an obfuscated and an original name")
+
+	_, err = log.LoadSymbolMap("testdata/missing.map")
+	assert.Error(t, err, "missing")
+
+}
+
+func Test_SetFrameResolver(t *testing.T) {
+
+	resetLogConfig()
+	_, stderr := redirectOutput()
+	defer resetLogOutput()
+	defer log.SetFrameResolver(nil)
+
+	log.SetFrameResolver(log.FrameResolverFunc(func(frame log.StackFrame) log.StackFrame {
+		if strings.HasSuffix(frame.Function, ".Test_SetFrameResolver") {
+			frame.Function = "example.com/app.Resolved"
+			frame.File = "/src/app/resolved.go"
+			frame.Line = 99
+		}
+		return frame
+	}))
+
+	log.StackTrace(errors.New("my error"))
+
+	actual := stderr.String()
+	assert.True(t, strings.HasPrefix(actual, "test | ERROR | *errors.fundamental my error\n"), "header")
+	assert.Contains(t, actual, "/src/app/resolved.go:99 (0x", "resolved")
+	assert.NotContains(t, actual, "frame_resolver_test.go", "original")
+
+	log.SetFrameResolver(nil)
+	stderr.Reset()
+
+	log.StackTrace(errors.New("my error"))
+	assert.Contains(t, stderr.String(), "frame_resolver_test.go", "removed")
+
+}
+
+func Test_SetFrameResolver_Recover(t *testing.T) {
+
+	resetLogConfig()
+	_, stderr := redirectOutput()
+	defer resetLogOutput()
+	defer log.SetFrameResolver(nil)
+
+	log.SetFrameResolver(log.FrameResolverFunc(func(frame log.StackFrame) log.StackFrame {
+		if strings.HasSuffix(frame.Function, ".panicking") {
+			frame.File = "/src/app/resolved.go"
+		}
+		return frame
+	}))
+
+	panicking("boom")
+
+	assert.Contains(t, stderr.String(), "/src/app/resolved.go:", "resolved")
+
+}
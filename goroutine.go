@@ -0,0 +1,31 @@
+package log
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+)
+
+// PrintGoroutineID indicates if the ID of the calling goroutine should be included in the log messages
+var PrintGoroutineID = false
+
+// GoroutineID returns the ID of the calling goroutine (or 0 if it can't be determined)
+//
+// The ID is parsed from the header of the goroutine's stack trace ("goroutine 123 [running]:")
+func GoroutineID() uint64 {
+	buf := make([]byte, 64)
+	buf = buf[:runtime.Stack(buf, false)]
+	return parseGoroutineID(buf)
+}
+
+func parseGoroutineID(stack []byte) uint64 {
+	stack = bytes.TrimPrefix(stack, []byte("goroutine "))
+	if i := bytes.IndexByte(stack, ' '); i >= 0 {
+		stack = stack[:i]
+	}
+	id, err := strconv.ParseUint(string(stack), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return id
+}
@@ -0,0 +1,30 @@
+package log
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseGoroutineID(t *testing.T) {
+
+	type test struct {
+		name     string
+		stack    string
+		expected uint64
+	}
+
+	var tests = []test{
+		{"valid", "goroutine 123 [running]:\nmain.main()", 123},
+		{"invalid", "something else", 0},
+		{"empty", "", 0},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := parseGoroutineID([]byte(tc.stack))
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+
+}
@@ -0,0 +1,48 @@
+package log_test
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func Test_GoroutineID(t *testing.T) {
+
+	id := log.GoroutineID()
+	assert.NotZero(t, id, "id")
+	assert.Equal(t, id, log.GoroutineID(), "stable")
+
+	var other uint64
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		other = log.GoroutineID()
+	}()
+	wg.Wait()
+
+	assert.NotZero(t, other, "other")
+	assert.NotEqual(t, id, other, "other")
+
+}
+
+func Test_PrintGoroutineID(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+	defer func() {
+		log.PrintGoroutineID = false
+	}()
+
+	log.PrintGoroutineID = true
+	log.Info("info")
+
+	expected := "test | INFO  | g:" + strconv.FormatUint(log.GoroutineID(), 10) + " | info\n"
+	assert.Equal(t, expected, stdout.String())
+
+}
@@ -0,0 +1,86 @@
+package grpcinterceptor
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+)
+
+// UnaryClientInterceptor returns an interceptor which logs each unary call made by the client
+//
+// The request ID of the context (see log.ContextWithRequestID) is sent in the RequestIDMetadataKey, which correlates
+// the logs across services when the called service uses UnaryServerInterceptor.
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req interface{}, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+
+		ctx = outgoingContext(ctx)
+
+		p := &peer.Peer{}
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, append(opts, grpc.Peer(p))...)
+
+		logCall(ctx, ClientMessage, clientFields(method, TypeUnary, p), start, err)
+
+		return err
+
+	}
+}
+
+// StreamClientInterceptor returns an interceptor which logs each streaming call made by the client when the stream
+// fails to open or when receiving from it returns an error (io.EOF being logged as a success)
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+
+		ctx = outgoingContext(ctx)
+
+		p := &peer.Peer{}
+		start := time.Now()
+		cs, err := streamer(ctx, desc, cc, method, append(opts, grpc.Peer(p))...)
+		if err != nil {
+			logCall(ctx, ClientMessage, clientFields(method, TypeStream, p), start, err)
+			return nil, err
+		}
+
+		return &clientStream{ClientStream: cs, ctx: ctx, method: method, peer: p, start: start}, nil
+
+	}
+}
+
+func clientFields(method string, callType string, p *peer.Peer) map[string]interface{} {
+	fields := map[string]interface{}{
+		FieldMethod: method,
+		FieldType:   callType,
+	}
+	if address := peerAddress(p); address != "" {
+		fields[FieldPeer] = address
+	}
+	return fields
+}
+
+// clientStream logs the call once receiving from the stream returns an error
+type clientStream struct {
+	grpc.ClientStream
+	ctx    context.Context
+	method string
+	peer   *peer.Peer
+	start  time.Time
+	once   sync.Once
+}
+
+func (s *clientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil {
+		s.once.Do(func() {
+			logged := err
+			if err == io.EOF {
+				logged = nil
+			}
+			logCall(s.ctx, ClientMessage, clientFields(s.method, TypeStream, s.peer), s.start, logged)
+		})
+	}
+	return err
+}
@@ -0,0 +1,11 @@
+module github.com/pieterclaerhout/go-log/grpcinterceptor
+
+go 1.13
+
+require (
+	github.com/pieterclaerhout/go-log v0.0.0
+	github.com/stretchr/testify v1.4.0
+	google.golang.org/grpc v1.29.1
+)
+
+replace github.com/pieterclaerhout/go-log => ../
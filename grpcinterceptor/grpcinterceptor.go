@@ -0,0 +1,154 @@
+// Package grpcinterceptor contains gRPC interceptors which log calls using github.com/pieterclaerhout/go-log
+//
+// It mirrors the HTTP middleware of github.com/pieterclaerhout/go-log/httplog and is a separate module, so that only
+// the programs using it depend on gRPC.
+//
+//	server := grpc.NewServer(
+//		grpc.UnaryInterceptor(grpcinterceptor.UnaryServerInterceptor()),
+//		grpc.StreamInterceptor(grpcinterceptor.StreamServerInterceptor()),
+//	)
+package grpcinterceptor
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+// The fields of the logged calls
+const (
+	FieldMethod   = "method"
+	FieldType     = "type"
+	FieldPeer     = "peer"
+	FieldCode     = "code"
+	FieldDuration = "duration_ms"
+	FieldError    = "error"
+)
+
+// The values of FieldType
+const (
+	TypeUnary  = "unary"
+	TypeStream = "stream"
+)
+
+// RequestIDMetadataKey is the metadata key used to read the incoming request ID and to send it with outgoing calls
+var RequestIDMetadataKey = "x-request-id"
+
+// TraceMetadataKeys are the incoming metadata keys added as fields to the logged calls, with their dashes replaced by
+// underscores (e.g. "trace_parent")
+var TraceMetadataKeys = []string{"traceparent", "tracestate", "x-b3-traceid", "x-b3-spanid", "x-datadog-trace-id"}
+
+// ServerMessage is the message of the entries logged for the calls handled by the server
+var ServerMessage = "gRPC server call"
+
+// ClientMessage is the message of the entries logged for the calls made by the client
+var ClientMessage = "gRPC client call"
+
+// CodeToLevel returns the level at which a call finishing with the code is logged
+var CodeToLevel = DefaultCodeToLevel
+
+// DefaultCodeToLevel logs successful calls as info, the errors caused by the caller as warnings and the others as
+// errors
+func DefaultCodeToLevel(code codes.Code) log.Level {
+	switch code {
+	case codes.OK:
+		return log.InfoLevel
+	case codes.Canceled, codes.InvalidArgument, codes.NotFound, codes.AlreadyExists, codes.PermissionDenied,
+		codes.Unauthenticated, codes.ResourceExhausted, codes.FailedPrecondition, codes.Aborted, codes.OutOfRange:
+		return log.WarnLevel
+	default:
+		return log.ErrorLevel
+	}
+}
+
+// incomingFields returns the request ID and the trace fields of the incoming metadata, a request ID is generated
+// using log.NewRequestID when the call doesn't have one
+func incomingFields(ctx context.Context) (string, map[string]interface{}) {
+
+	fields := map[string]interface{}{}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	requestID := firstValue(md, RequestIDMetadataKey)
+	if requestID == "" {
+		requestID = log.NewRequestID()
+	}
+
+	for _, key := range TraceMetadataKeys {
+		if value := firstValue(md, key); value != "" {
+			fields[strings.Replace(key, "-", "_", -1)] = value
+		}
+	}
+
+	return requestID, fields
+
+}
+
+func firstValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// outgoingContext adds the request ID of the context to the outgoing metadata unless it was already set
+func outgoingContext(ctx context.Context) context.Context {
+
+	requestID := log.RequestIDFromContext(ctx)
+	if requestID == "" {
+		return ctx
+	}
+
+	if md, ok := metadata.FromOutgoingContext(ctx); ok && len(md.Get(RequestIDMetadataKey)) > 0 {
+		return ctx
+	}
+
+	return metadata.AppendToOutgoingContext(ctx, RequestIDMetadataKey, requestID)
+
+}
+
+// peerAddress returns the address of the peer of the call, if it's known
+func peerAddress(p *peer.Peer) string {
+	if p == nil || p.Addr == nil {
+		return ""
+	}
+	return p.Addr.String()
+}
+
+// logCall logs the outcome of a call at the level of its status code
+func logCall(ctx context.Context, message string, fields map[string]interface{}, start time.Time, err error) {
+
+	code := status.Code(err)
+
+	fields[FieldCode] = code.String()
+	fields[FieldDuration] = float64(time.Since(start).Microseconds()) / 1000
+	if err != nil {
+		fields[FieldError] = status.Convert(err).Message()
+	}
+
+	logger := log.FromContext(ctx).WithFields(fields)
+
+	switch CodeToLevel(code) {
+	case log.TraceLevel:
+		logger.Trace(message)
+	case log.DebugLevel:
+		logger.Debug(message)
+	case log.InfoLevel:
+		logger.Info(message)
+	case log.NoticeLevel:
+		logger.Notice(message)
+	case log.WarnLevel:
+		logger.Warn(message)
+	default:
+		// Fatal would exit the program, which an interceptor shouldn't do
+		logger.Error(message)
+	}
+
+}
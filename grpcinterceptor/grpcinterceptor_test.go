@@ -0,0 +1,226 @@
+package grpcinterceptor_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/pieterclaerhout/go-log"
+	"github.com/pieterclaerhout/go-log/grpcinterceptor"
+)
+
+type syncBuffer struct {
+	mutex sync.Mutex
+	buf   bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buf.Write(p)
+}
+
+// entries returns the logged entries with the given message
+func (b *syncBuffer) entries(message string) []map[string]interface{} {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	entries := []map[string]interface{}{}
+	for _, line := range strings.Split(strings.TrimSpace(b.buf.String()), "\n") {
+		var entry map[string]interface{}
+		if json.Unmarshal([]byte(line), &entry) == nil && entry["message"] == message {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// startServer starts a health server using the interceptors and returns a client connected to it
+func startServer(t *testing.T) (healthpb.HealthClient, *syncBuffer, func()) {
+
+	output := &syncBuffer{}
+	log.Stdout = output
+	log.Stderr = output
+	log.Format = log.FormatDatadog
+
+	listener := bufconn.Listen(1024 * 1024)
+
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(grpcinterceptor.UnaryServerInterceptor()),
+		grpc.StreamInterceptor(grpcinterceptor.StreamServerInterceptor()),
+	)
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	go server.Serve(listener)
+
+	conn, err := grpc.Dial(
+		"bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+			return listener.Dial()
+		}),
+		grpc.WithInsecure(),
+		grpc.WithUnaryInterceptor(grpcinterceptor.UnaryClientInterceptor()),
+		grpc.WithStreamInterceptor(grpcinterceptor.StreamClientInterceptor()),
+	)
+	assert.NoError(t, err, "dial")
+
+	return healthpb.NewHealthClient(conn), output, func() {
+		conn.Close()
+		server.Stop()
+		log.Stdout = os.Stdout
+		log.Stderr = os.Stderr
+		log.Format = log.FormatText
+	}
+
+}
+
+func Test_Unary(t *testing.T) {
+
+	client, output, stop := startServer(t)
+	defer stop()
+
+	ctx := log.ContextWithRequestID(context.Background(), "request-1")
+	ctx = metadata.AppendToOutgoingContext(ctx, "traceparent", "00-trace-span-01")
+
+	var header metadata.MD
+	resp, err := client.Check(ctx, &healthpb.HealthCheckRequest{}, grpc.Header(&header))
+	assert.NoError(t, err, "check")
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.Status, "status")
+	assert.Equal(t, []string{"request-1"}, header.Get("x-request-id"), "header")
+
+	server := output.entries(grpcinterceptor.ServerMessage)
+	if assert.Len(t, server, 1, "server") {
+		assert.Equal(t, "info", server[0]["status"], "server-level")
+		assert.Equal(t, "/grpc.health.v1.Health/Check", server[0]["method"], "server-method")
+		assert.Equal(t, "unary", server[0]["type"], "server-type")
+		assert.Equal(t, "OK", server[0]["code"], "server-code")
+		assert.Equal(t, "request-1", server[0]["request_id"], "server-request-id")
+		assert.Equal(t, "00-trace-span-01", server[0]["traceparent"], "server-traceparent")
+		assert.Contains(t, server[0], "peer", "server-peer")
+		assert.Contains(t, server[0], "duration_ms", "server-duration")
+	}
+
+	calls := output.entries(grpcinterceptor.ClientMessage)
+	if assert.Len(t, calls, 1, "client") {
+		assert.Equal(t, "info", calls[0]["status"], "client-level")
+		assert.Equal(t, "/grpc.health.v1.Health/Check", calls[0]["method"], "client-method")
+		assert.Equal(t, "OK", calls[0]["code"], "client-code")
+		assert.Equal(t, "request-1", calls[0]["request_id"], "client-request-id")
+		assert.Equal(t, "bufconn", calls[0]["peer"], "client-peer")
+	}
+
+}
+
+func Test_Unary_Error(t *testing.T) {
+
+	client, output, stop := startServer(t)
+	defer stop()
+
+	_, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "unknown"})
+	assert.Equal(t, codes.NotFound, status.Code(err), "code")
+
+	server := output.entries(grpcinterceptor.ServerMessage)
+	if assert.Len(t, server, 1, "server") {
+		assert.Equal(t, "warn", server[0]["status"], "level")
+		assert.Equal(t, "NotFound", server[0]["code"], "code")
+		assert.Equal(t, "unknown service", server[0]["error"], "error")
+		assert.Len(t, server[0]["request_id"], 26, "generated-request-id")
+	}
+
+}
+
+func Test_Stream(t *testing.T) {
+
+	client, output, stop := startServer(t)
+	defer stop()
+
+	ctx, cancel := context.WithCancel(log.ContextWithRequestID(context.Background(), "request-2"))
+
+	stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{})
+	assert.NoError(t, err, "watch")
+
+	resp, err := stream.Recv()
+	assert.NoError(t, err, "recv")
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.Status, "status")
+
+	cancel()
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Canceled, status.Code(err), "canceled")
+
+	assert.Eventually(t, func() bool {
+		return len(output.entries(grpcinterceptor.ServerMessage)) == 1
+	}, 2*time.Second, 10*time.Millisecond, "server")
+
+	server := output.entries(grpcinterceptor.ServerMessage)[0]
+	assert.Equal(t, "/grpc.health.v1.Health/Watch", server["method"], "server-method")
+	assert.Equal(t, "stream", server["type"], "server-type")
+	assert.Equal(t, "request-2", server["request_id"], "server-request-id")
+
+	calls := output.entries(grpcinterceptor.ClientMessage)
+	if assert.Len(t, calls, 1, "client") {
+		assert.Equal(t, "warn", calls[0]["status"], "client-level")
+		assert.Equal(t, "Canceled", calls[0]["code"], "client-code")
+		assert.Equal(t, "stream", calls[0]["type"], "client-type")
+	}
+
+}
+
+func Test_CodeToLevel(t *testing.T) {
+
+	defer func() {
+		grpcinterceptor.CodeToLevel = grpcinterceptor.DefaultCodeToLevel
+	}()
+	grpcinterceptor.CodeToLevel = func(code codes.Code) log.Level {
+		return log.ErrorLevel
+	}
+
+	client, output, stop := startServer(t)
+	defer stop()
+
+	_, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	assert.NoError(t, err, "check")
+
+	server := output.entries(grpcinterceptor.ServerMessage)
+	if assert.Len(t, server, 1, "server") {
+		assert.Equal(t, "error", server[0]["status"], "level")
+	}
+
+}
+
+func Test_DefaultCodeToLevel(t *testing.T) {
+
+	type test struct {
+		code     codes.Code
+		expected log.Level
+	}
+
+	var tests = []test{
+		{codes.OK, log.InfoLevel},
+		{codes.NotFound, log.WarnLevel},
+		{codes.Unauthenticated, log.WarnLevel},
+		{codes.Internal, log.ErrorLevel},
+		{codes.Unavailable, log.ErrorLevel},
+		{codes.Unknown, log.ErrorLevel},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.code.String(), func(t *testing.T) {
+			actual := grpcinterceptor.DefaultCodeToLevel(tc.code)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+
+}
@@ -0,0 +1,78 @@
+package grpcinterceptor
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+// UnaryServerInterceptor returns an interceptor which logs each unary call handled by the server
+//
+// The request ID is taken from the RequestIDMetadataKey or generated when the call doesn't have one. It is added to
+// the context of the handler, so that log.FromContext includes it, and returned in the response header. The values
+// of the TraceMetadataKeys are added to the logged entry.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+
+		ctx, fields := serverContext(ctx, info.FullMethod, TypeUnary)
+		grpc.SetHeader(ctx, metadata.Pairs(RequestIDMetadataKey, log.RequestIDFromContext(ctx)))
+
+		start := time.Now()
+		resp, err := handler(ctx, req)
+
+		logCall(ctx, ServerMessage, fields, start, err)
+
+		return resp, err
+
+	}
+}
+
+// StreamServerInterceptor returns an interceptor which logs each streaming call handled by the server when it
+// finishes, the request ID and the trace fields are handled as for UnaryServerInterceptor
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+
+		ctx, fields := serverContext(ss.Context(), info.FullMethod, TypeStream)
+		ss.SetHeader(metadata.Pairs(RequestIDMetadataKey, log.RequestIDFromContext(ctx)))
+
+		start := time.Now()
+		err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+
+		logCall(ctx, ServerMessage, fields, start, err)
+
+		return err
+
+	}
+}
+
+// serverContext returns the context of the handler with the request ID of the call and the fields of its entry
+func serverContext(ctx context.Context, method string, callType string) (context.Context, map[string]interface{}) {
+
+	requestID, fields := incomingFields(ctx)
+
+	fields[FieldMethod] = method
+	fields[FieldType] = callType
+
+	p, _ := peer.FromContext(ctx)
+	if address := peerAddress(p); address != "" {
+		fields[FieldPeer] = address
+	}
+
+	return log.ContextWithRequestID(ctx, requestID), fields
+
+}
+
+// serverStream replaces the context of the stream with the one carrying the request ID
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,183 @@
+package log
+
+import (
+	"sync"
+	"time"
+)
+
+// HealthMaxErrorRate is the number of errors per second (averaged over the last minute) above which the log
+// pipeline is reported as unhealthy (0 disables the check)
+var HealthMaxErrorRate = 0.0
+
+// HealthStatus describes the health of the log pipeline
+type HealthStatus struct {
+	Healthy       bool         `json:"healthy"`
+	LastErrorTime time.Time    `json:"last_error_time"`
+	ErrorCount    uint64       `json:"error_count"`
+	ErrorRate     float64      `json:"error_rate"`
+	Dropped       uint64       `json:"dropped"`
+	Sinks         []SinkHealth `json:"sinks"`
+}
+
+// SinkHealth describes the health of a single output of the log pipeline
+type SinkHealth struct {
+	Name          string    `json:"name"`
+	Healthy       bool      `json:"healthy"`
+	LastError     string    `json:"last_error,omitempty"`
+	LastErrorTime time.Time `json:"last_error_time"`
+}
+
+type healthState struct {
+	mutex         sync.Mutex
+	lastErrorTime time.Time
+	errorCount    uint64
+	errorBuckets  [60]errorBucket
+	dropped       uint64
+	sinks         []*sinkState
+}
+
+type errorBucket struct {
+	second int64
+	count  uint64
+}
+
+var health = &healthState{}
+
+// Health returns the current health of the log pipeline
+func Health() HealthStatus {
+
+	health.mutex.Lock()
+	defer health.mutex.Unlock()
+
+	status := HealthStatus{
+		Healthy:       true,
+		LastErrorTime: health.lastErrorTime,
+		ErrorCount:    health.errorCount,
+		ErrorRate:     health.errorRate(time.Now()),
+		Dropped:       health.dropped,
+		Sinks:         []SinkHealth{},
+	}
+
+	for _, sink := range health.sinks {
+		sinkHealth := SinkHealth{
+			Name:          sink.name,
+			Healthy:       sink.lastError == nil,
+			LastErrorTime: sink.lastErrorTime,
+		}
+		if sink.lastError != nil {
+			sinkHealth.LastError = sink.lastError.Error()
+			status.Healthy = false
+		}
+		status.Sinks = append(status.Sinks, sinkHealth)
+	}
+
+	if HealthMaxErrorRate > 0 && status.ErrorRate > HealthMaxErrorRate {
+		status.Healthy = false
+	}
+
+	return status
+
+}
+
+func (h *healthState) recordError(t time.Time) {
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.lastErrorTime = t
+	h.errorCount++
+
+	second := t.Unix()
+	bucket := &h.errorBuckets[second%int64(len(h.errorBuckets))]
+	if bucket.second != second {
+		bucket.second = second
+		bucket.count = 0
+	}
+	bucket.count++
+
+}
+
+func (h *healthState) recordWrite(name string, t time.Time, n int, err error) {
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	sink := h.sink(name)
+	sink.bytes += uint64(n)
+	sink.lastError = err
+	if err != nil {
+		sink.errors++
+		sink.lastErrorTime = t
+		h.dropped++
+	} else {
+		sink.entries++
+	}
+
+}
+
+func (h *healthState) recordRetry(name string) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.sink(name).retries++
+}
+
+func (h *healthState) recordQueueDepth(name string, depth int) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.sink(name).queueDepth = depth
+}
+
+func (h *healthState) recordLatency(name string, latency time.Duration) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	sink := h.sink(name)
+	sink.latencies++
+	sink.latencyTotal += latency
+	sink.latencyLast = latency
+	if latency > sink.latencyMax {
+		sink.latencyMax = latency
+	}
+}
+
+func (h *healthState) sink(name string) *sinkState {
+	for _, sink := range h.sinks {
+		if sink.name == name {
+			return sink
+		}
+	}
+	sink := &sinkState{name: name}
+	h.sinks = append(h.sinks, sink)
+	return sink
+}
+
+func (h *healthState) removeSink(name string) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	for i, sink := range h.sinks {
+		if sink.name == name {
+			h.sinks = append(h.sinks[:i], h.sinks[i+1:]...)
+			return
+		}
+	}
+}
+
+func (h *healthState) errorRate(t time.Time) float64 {
+	now := t.Unix()
+	var count uint64
+	for _, bucket := range h.errorBuckets {
+		if now-bucket.second < int64(len(h.errorBuckets)) {
+			count += bucket.count
+		}
+	}
+	return float64(count) / float64(len(h.errorBuckets))
+}
+
+func (h *healthState) reset() {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.lastErrorTime = time.Time{}
+	h.errorCount = 0
+	h.errorBuckets = [60]errorBucket{}
+	h.dropped = 0
+	h.sinks = nil
+}
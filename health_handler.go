@@ -0,0 +1,25 @@
+//go:build !tinygo
+// +build !tinygo
+
+package log
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// HealthHandler returns an http.Handler which reports the health of the log pipeline as JSON
+//
+// It responds with status 200 when healthy and with status 503 when unhealthy
+func HealthHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := Health()
+		w.Header().Set("Content-Type", "application/json")
+		if status.Healthy {
+			w.WriteHeader(http.StatusOK)
+		} else {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		json.NewEncoder(w).Encode(status)
+	})
+}
@@ -0,0 +1,36 @@
+//go:build !tinygo
+// +build !tinygo
+
+package log_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func Test_HealthHandler(t *testing.T) {
+
+	resetLogConfig()
+	redirectOutput()
+	defer resetLogOutput()
+
+	log.Info("info")
+
+	rec := httptest.NewRecorder()
+	log.HealthHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	var status log.HealthStatus
+	err := json.Unmarshal(rec.Body.Bytes(), &status)
+
+	assert.NoError(t, err, "json")
+	assert.Equal(t, http.StatusOK, rec.Code, "status-code")
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"), "content-type")
+	assert.True(t, status.Healthy, "healthy")
+
+}
@@ -0,0 +1,88 @@
+package log_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+type failingWriter struct{}
+
+func (w failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func Test_Health_ErrorCount(t *testing.T) {
+
+	resetLogConfig()
+	redirectOutput()
+	defer resetLogOutput()
+
+	before := log.Health()
+
+	log.Error("error")
+
+	after := log.Health()
+
+	assert.Equal(t, before.ErrorCount+1, after.ErrorCount, "error-count")
+	assert.True(t, after.ErrorRate > 0, "error-rate")
+	assert.False(t, after.LastErrorTime.IsZero(), "last-error-time")
+	assert.True(t, after.Healthy, "healthy")
+
+}
+
+func Test_Health_FailingSink(t *testing.T) {
+
+	resetLogConfig()
+	redirectOutput()
+	defer resetLogOutput()
+
+	log.Stderr = failingWriter{}
+
+	before := log.Health()
+
+	log.Error("error")
+
+	after := log.Health()
+
+	assert.Equal(t, before.Dropped+1, after.Dropped, "dropped")
+	assert.False(t, after.Healthy, "healthy")
+
+	var found bool
+	for _, sink := range after.Sinks {
+		if sink.Name == "stderr" {
+			found = true
+			assert.False(t, sink.Healthy, "sink-healthy")
+			assert.Equal(t, "write failed", sink.LastError, "sink-last-error")
+		}
+	}
+	assert.True(t, found, "sink-found")
+
+	redirectOutput()
+	log.Error("error")
+
+	assert.True(t, log.Health().Healthy, "recovered")
+
+}
+
+func Test_Health_MaxErrorRate(t *testing.T) {
+
+	resetLogConfig()
+	redirectOutput()
+	defer resetLogOutput()
+
+	oldMaxErrorRate := log.HealthMaxErrorRate
+	defer func() {
+		log.HealthMaxErrorRate = oldMaxErrorRate
+	}()
+
+	log.HealthMaxErrorRate = 0.001
+
+	log.Error("error")
+
+	assert.False(t, log.Health().Healthy, "healthy")
+
+}
@@ -0,0 +1,70 @@
+package log
+
+import (
+	"fmt"
+	"time"
+)
+
+// Hook receives each entry which is written, e.g. to forward errors to Sentry or to keep an audit trail
+//
+// Fire is called after the entry was written to the outputs, without holding any lock, so hooks can log
+// themselves. The fields must not be modified. A hook which panics is recovered and the panic is logged as an
+// error (which isn't passed to the hooks).
+type Hook interface {
+	Fire(level Level, t time.Time, message string, fields map[string]interface{})
+}
+
+// HookFunc is an adapter to use an ordinary function as a Hook
+type HookFunc func(level Level, t time.Time, message string, fields map[string]interface{})
+
+// Fire calls f(level, t, message, fields)
+func (f HookFunc) Fire(level Level, t time.Time, message string, fields map[string]interface{}) {
+	f(level, t, message, fields)
+}
+
+type registeredHook struct {
+	hook Hook
+}
+
+// hooks are guarded by logMutex, they are replaced (not modified) so that they can be fired without holding it
+var hooks []*registeredHook
+
+// AddHook registers the hook so that it's fired for each entry which is written and returns a function which
+// unregisters it
+func AddHook(h Hook) func() {
+
+	registered := &registeredHook{hook: h}
+
+	logMutex.Lock()
+	hooks = append(hooks[:len(hooks):len(hooks)], registered)
+	logMutex.Unlock()
+
+	return func() {
+		logMutex.Lock()
+		defer logMutex.Unlock()
+		for i, hook := range hooks {
+			if hook == registered {
+				hooks = append(hooks[:i:i], hooks[i+1:]...)
+				return
+			}
+		}
+	}
+
+}
+
+func fireHooks(hs []*registeredHook, e entry) {
+	level := levelOf(e.level)
+	for _, h := range hs {
+		fireHook(h.hook, level, e)
+	}
+}
+
+func fireHook(h Hook, level Level, e entry) {
+	defer func() {
+		if r := recover(); r != nil && !exitOnFatal(r) {
+			message := "Recovered from panic in hook: " + formatPanicValue(r) + "\n" + panicStack()
+			std.logEntry("ERROR", message, map[string]interface{}{"hook": fmt.Sprintf("%T", h), "recovered": true}, false)
+		}
+	}()
+	h.Fire(level, e.time, e.message, e.fields)
+}
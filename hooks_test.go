@@ -0,0 +1,90 @@
+package log_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+type firedEntry struct {
+	level   log.Level
+	message string
+	fields  map[string]interface{}
+}
+
+func Test_AddHook(t *testing.T) {
+
+	resetLogConfig()
+	redirectOutput()
+	defer resetLogOutput()
+
+	fired := []firedEntry{}
+	remove := log.AddHook(log.HookFunc(func(level log.Level, ts time.Time, message string, fields map[string]interface{}) {
+		assert.False(t, ts.IsZero(), "time")
+		fired = append(fired, firedEntry{level, message, fields})
+	}))
+
+	log.Debug("not written")
+	log.Info("info")
+	log.WithField("order_id", "o-1").Error("error")
+
+	remove()
+	log.Warn("after remove")
+
+	assert.Equal(t, []firedEntry{
+		{log.InfoLevel, "info", nil},
+		{log.ErrorLevel, "error", map[string]interface{}{"order_id": "o-1"}},
+	}, fired)
+
+}
+
+func Test_AddHook_Logging(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+
+	remove := log.AddHook(log.HookFunc(func(level log.Level, ts time.Time, message string, fields map[string]interface{}) {
+		if level == log.WarnLevel {
+			log.Info("hook saw:", message)
+		}
+	}))
+	defer remove()
+
+	log.Warn("warn")
+
+	assert.Equal(t, "test | WARN  | warn\ntest | INFO  | hook saw: warn\n", stdout.String())
+
+}
+
+func Test_AddHook_Panic(t *testing.T) {
+
+	resetLogConfig()
+	stdout, stderr := redirectOutput()
+	defer resetLogOutput()
+
+	fired := 0
+	removePanicking := log.AddHook(log.HookFunc(func(level log.Level, ts time.Time, message string, fields map[string]interface{}) {
+		panic("bad hook")
+	}))
+	defer removePanicking()
+
+	removeCounting := log.AddHook(log.HookFunc(func(level log.Level, ts time.Time, message string, fields map[string]interface{}) {
+		fired++
+	}))
+	defer removeCounting()
+
+	assert.NotPanics(t, func() {
+		log.Info("info")
+	})
+
+	assert.Equal(t, "test | INFO  | info\n", stdout.String(), "stdout")
+	assert.True(t, strings.HasPrefix(stderr.String(), "test | ERROR | Recovered from panic in hook: bad hook\n"), "stderr")
+	assert.Contains(t, stderr.String(), "hook=log.HookFunc recovered=true", "fields")
+	assert.Equal(t, 1, fired, "other-hooks")
+
+}
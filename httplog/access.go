@@ -0,0 +1,117 @@
+package httplog
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AccessFormat is the format of the lines written to the AccessLog
+type AccessFormat int
+
+const (
+	// AccessLogCommon is the Common Log Format: host ident user [time] "request" status size
+	AccessLogCommon AccessFormat = iota
+
+	// AccessLogCombined is the Combined Log Format, which adds the referer and the user agent to the Common Log Format
+	AccessLogCombined
+)
+
+// accessTimeFormat is the format of the time in the access log lines
+const accessTimeFormat = "02/Jan/2006:15:04:05 -0700"
+
+// AccessLog is the writer to which Middleware writes a line for each request in the AccessLogFormat, so that log
+// analyzers such as GoAccess or AWStats can process them (disabled when nil)
+var AccessLog io.Writer
+
+// AccessLogFormat is the format of the lines written to the AccessLog
+var AccessLogFormat = AccessLogCombined
+
+var accessLogMutex = &sync.Mutex{}
+
+// writeAccessLine writes the line of the request to the AccessLog
+func writeAccessLine(r *http.Request, start time.Time, status int, size int) {
+
+	if AccessLog == nil {
+		return
+	}
+
+	line := formatAccessLine(r, start, status, size, AccessLogFormat)
+
+	accessLogMutex.Lock()
+	defer accessLogMutex.Unlock()
+
+	io.WriteString(AccessLog, line)
+
+}
+
+// formatAccessLine formats the request as Apache does, the fields which aren't known are written as "-"
+func formatAccessLine(r *http.Request, start time.Time, status int, size int, format AccessFormat) string {
+
+	host := r.RemoteAddr
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	user := "-"
+	if username, _, ok := r.BasicAuth(); ok && username != "" {
+		user = escapeAccessField(username)
+	}
+
+	uri := r.RequestURI
+	if uri == "" {
+		uri = r.URL.RequestURI()
+	}
+
+	bytes := "-"
+	if size > 0 {
+		bytes = strconv.Itoa(size)
+	}
+
+	line := fmt.Sprintf(
+		"%s - %s [%s] \"%s %s %s\" %d %s",
+		orDash(host), user, start.Format(accessTimeFormat),
+		escapeAccessField(r.Method), escapeAccessField(uri), escapeAccessField(r.Proto),
+		status, bytes,
+	)
+
+	if format == AccessLogCombined {
+		line += fmt.Sprintf(" \"%s\" \"%s\"", orDash(escapeAccessField(r.Referer())), orDash(escapeAccessField(r.UserAgent())))
+	}
+
+	return line + "\n"
+
+}
+
+// escapeAccessField escapes the quotes, backslashes and non-printable characters as Apache does, so that a value
+// can't break the fields of the line
+func escapeAccessField(value string) string {
+
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case c == '"' || c == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c < 0x20 || c >= 0x7f:
+			fmt.Fprintf(&b, "\\x%02x", c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
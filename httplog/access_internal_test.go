@@ -0,0 +1,59 @@
+package httplog
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_formatAccessLine(t *testing.T) {
+
+	start := time.Date(2000, 10, 10, 13, 55, 36, 0, time.FixedZone("", -7*60*60))
+
+	r := httptest.NewRequest(http.MethodGet, "/apache_pb.gif?a=1", nil)
+	r.RemoteAddr = "127.0.0.1:54321"
+	r.SetBasicAuth("frank", "secret")
+	r.Header.Set("Referer", "http://www.example.com/start.html")
+	r.Header.Set("User-Agent", `Mozilla/4.08 [en] "quoted"`)
+
+	type test struct {
+		name     string
+		r        *http.Request
+		status   int
+		size     int
+		format   AccessFormat
+		expected string
+	}
+
+	var tests = []test{
+		{
+			"common", r, 200, 2326, AccessLogCommon,
+			`127.0.0.1 - frank [10/Oct/2000:13:55:36 -0700] "GET /apache_pb.gif?a=1 HTTP/1.1" 200 2326` + "\n",
+		},
+		{
+			"combined", r, 200, 2326, AccessLogCombined,
+			`127.0.0.1 - frank [10/Oct/2000:13:55:36 -0700] "GET /apache_pb.gif?a=1 HTTP/1.1" 200 2326 "http://www.example.com/start.html" "Mozilla/4.08 [en] \"quoted\""` + "\n",
+		},
+		{
+			"empty", &http.Request{Method: "HEAD", URL: r.URL, Proto: "HTTP/1.0", Header: http.Header{}}, 304, 0, AccessLogCombined,
+			`- - - [10/Oct/2000:13:55:36 -0700] "HEAD /apache_pb.gif?a=1 HTTP/1.0" 304 - "-" "-"` + "\n",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := formatAccessLine(tc.r, start, tc.status, tc.size, tc.format)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+
+}
+
+func Test_escapeAccessField(t *testing.T) {
+	assert.Equal(t, "plain", escapeAccessField("plain"))
+	assert.Equal(t, `a\"b\\c`, escapeAccessField(`a"b\c`))
+	assert.Equal(t, `line\x0abreak\xc3\xa9`, escapeAccessField("line\nbreaké"))
+}
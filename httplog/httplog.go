@@ -0,0 +1,164 @@
+// Package httplog contains HTTP middleware which logs requests using github.com/pieterclaerhout/go-log
+package httplog
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+// The fields which can be included in the canonical request line
+const (
+	FieldMethod        = "method"
+	FieldPath          = "path"
+	FieldStatus        = "status"
+	FieldLatencyBucket = "latency_bucket"
+	FieldUserAgent     = "user_agent"
+	FieldRemoteAddr    = "remote_addr"
+	FieldResponseSize  = "response_size"
+	FieldErrorClass    = "error_class"
+)
+
+// FieldRequestID is the field containing the request ID, which is always included in the canonical request line
+const FieldRequestID = "request_id"
+
+// RequestIDHeader is the header used to read the incoming request ID and to return it in the response
+var RequestIDHeader = "X-Request-ID"
+
+// EventName is the name of the event used for the canonical request line
+var EventName = "http_request"
+
+// CanonicalFields are the request fields included in the canonical request line
+//
+// The duration (as "duration_ms") and the fields added by the handlers via log.AddWideField are always included
+var CanonicalFields = []string{FieldMethod, FieldPath, FieldStatus, FieldResponseSize, FieldErrorClass}
+
+// LatencyBuckets are the upper bounds used to classify the request latency for FieldLatencyBucket
+var LatencyBuckets = []time.Duration{
+	10 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	5 * time.Second,
+}
+
+// Middleware wraps next so that a single canonical line is logged for each request
+//
+// A wide event is added to the request context so that handlers can add fields to the line using
+// log.AddWideField. The request ID is taken from the RequestIDHeader or generated using log.NewRequestID when the
+// request doesn't have one. It is added to the request context and returned in the response headers.
+//
+// When AccessLog is set, a line in the AccessLogFormat is written to it as well.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		requestID := r.Header.Get(RequestIDHeader)
+		if requestID == "" {
+			requestID = log.NewRequestID()
+		}
+		w.Header().Set(RequestIDHeader, requestID)
+
+		ctx, event := log.NewWideEvent(log.ContextWithRequestID(r.Context(), requestID), EventName)
+		event.Set(FieldRequestID, requestID)
+		rw := &responseWriter{ResponseWriter: w, status: http.StatusOK}
+		start := time.Now()
+
+		next.ServeHTTP(rw, r.WithContext(ctx))
+
+		values := map[string]interface{}{
+			FieldMethod:        r.Method,
+			FieldPath:          r.URL.Path,
+			FieldStatus:        rw.status,
+			FieldLatencyBucket: latencyBucket(time.Since(start)),
+			FieldUserAgent:     r.UserAgent(),
+			FieldRemoteAddr:    r.RemoteAddr,
+			FieldResponseSize:  rw.size,
+			FieldErrorClass:    errorClass(rw.status),
+		}
+
+		for _, field := range CanonicalFields {
+			if value, ok := values[field]; ok {
+				event.Set(field, value)
+			}
+		}
+
+		event.Finish()
+
+		writeAccessLine(r, start, rw.status, rw.size)
+
+	})
+}
+
+type responseWriter struct {
+	http.ResponseWriter
+	status      int
+	size        int
+	wroteHeader bool
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	n, err := w.ResponseWriter.Write(b)
+	w.size += n
+	return n, err
+}
+
+// Flush sends the buffered data to the client when the underlying ResponseWriter supports it, as used for streaming
+// responses such as server-sent events
+func (w *responseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		w.wroteHeader = true
+		flusher.Flush()
+	}
+}
+
+// Hijack takes over the connection of the underlying ResponseWriter, as used for websocket upgrades
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("httplog: the response writer doesn't support hijacking")
+	}
+	w.wroteHeader = true
+	return hijacker.Hijack()
+}
+
+// Unwrap returns the underlying ResponseWriter, as used by http.ResponseController
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func latencyBucket(d time.Duration) string {
+	for _, bucket := range LatencyBuckets {
+		if d < bucket {
+			return "<" + bucket.String()
+		}
+	}
+	if len(LatencyBuckets) == 0 {
+		return ""
+	}
+	return ">=" + LatencyBuckets[len(LatencyBuckets)-1].String()
+}
+
+func errorClass(status int) string {
+	switch {
+	case status >= 500:
+		return "server_error"
+	case status >= 400:
+		return "client_error"
+	default:
+		return ""
+	}
+}
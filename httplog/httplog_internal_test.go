@@ -0,0 +1,46 @@
+package httplog
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_latencyBucket(t *testing.T) {
+
+	type test struct {
+		name     string
+		duration time.Duration
+		expected string
+	}
+
+	var tests = []test{
+		{"fast", time.Millisecond, "<10ms"},
+		{"boundary", 10 * time.Millisecond, "<50ms"},
+		{"slow", 2 * time.Second, "<5s"},
+		{"very-slow", time.Minute, ">=5s"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := latencyBucket(tc.duration)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+
+}
+
+func Test_errorClass(t *testing.T) {
+	assert.Equal(t, "", errorClass(200))
+	assert.Equal(t, "", errorClass(302))
+	assert.Equal(t, "client_error", errorClass(404))
+	assert.Equal(t, "server_error", errorClass(503))
+}
+
+func Test_responseWriter_Unwrap(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	rw := &responseWriter{ResponseWriter: recorder}
+	assert.Equal(t, recorder, rw.Unwrap())
+}
@@ -0,0 +1,192 @@
+package httplog_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+	"github.com/pieterclaerhout/go-log/httplog"
+)
+
+func serve(t *testing.T, handler http.Handler, r *http.Request) map[string]interface{} {
+
+	stdout := bytes.NewBufferString("")
+	log.Stdout = stdout
+	defer func() {
+		log.Stdout = os.Stdout
+	}()
+
+	httplog.Middleware(handler).ServeHTTP(httptest.NewRecorder(), r)
+
+	var actual map[string]interface{}
+	err := json.Unmarshal(stdout.Bytes(), &actual)
+	assert.NoError(t, err, "json")
+
+	return actual["properties"].(map[string]interface{})
+
+}
+
+func Test_Middleware(t *testing.T) {
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		log.AddWideField(r.Context(), "user_id", "42")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("not found"))
+	})
+
+	actual := serve(t, handler, httptest.NewRequest(http.MethodGet, "/users/42", nil))
+
+	assert.Equal(t, "GET", actual["method"], "method")
+	assert.Equal(t, "/users/42", actual["path"], "path")
+	assert.Equal(t, 404.0, actual["status"], "status")
+	assert.Equal(t, 9.0, actual["response_size"], "response_size")
+	assert.Equal(t, "client_error", actual["error_class"], "error_class")
+	assert.Equal(t, "42", actual["user_id"], "user_id")
+	assert.Contains(t, actual, "duration_ms", "duration")
+	assert.NotContains(t, actual, "user_agent", "user_agent")
+
+}
+
+func Test_Middleware_CanonicalFields(t *testing.T) {
+
+	oldFields := httplog.CanonicalFields
+	defer func() {
+		httplog.CanonicalFields = oldFields
+	}()
+
+	httplog.CanonicalFields = []string{httplog.FieldUserAgent, httplog.FieldLatencyBucket, httplog.FieldStatus}
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	})
+
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	r.Header.Set("User-Agent", "test-agent")
+
+	actual := serve(t, handler, r)
+
+	assert.Equal(t, "test-agent", actual["user_agent"], "user_agent")
+	assert.Equal(t, "<10ms", actual["latency_bucket"], "latency_bucket")
+	assert.Equal(t, 200.0, actual["status"], "status")
+	assert.NotContains(t, actual, "method", "method")
+
+}
+
+func Test_Middleware_RequestID(t *testing.T) {
+
+	var fromContext string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fromContext = log.RequestIDFromContext(r.Context())
+	})
+
+	generated := serve(t, handler, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Len(t, generated["request_id"], 26, "generated")
+	assert.Equal(t, generated["request_id"], fromContext, "generated-context")
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("X-Request-ID", "incoming-id")
+
+	incoming := serve(t, handler, r)
+
+	assert.Equal(t, "incoming-id", incoming["request_id"], "incoming")
+	assert.Equal(t, "incoming-id", fromContext, "incoming-context")
+
+}
+
+func Test_Middleware_AccessLog(t *testing.T) {
+
+	accessLog := bytes.NewBufferString("")
+	httplog.AccessLog = accessLog
+	defer func() {
+		httplog.AccessLog = nil
+		httplog.AccessLogFormat = httplog.AccessLogCombined
+	}()
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("created"))
+	})
+
+	r := httptest.NewRequest(http.MethodPost, "/users", nil)
+	r.Header.Set("User-Agent", "test-agent")
+	serve(t, handler, r)
+
+	assert.Regexp(t, `^192\.0\.2\.1 - - \[[^\]]+\] "POST /users HTTP/1\.1" 201 7 "-" "test-agent"\n$`, accessLog.String(), "combined")
+
+	accessLog.Reset()
+	httplog.AccessLogFormat = httplog.AccessLogCommon
+	serve(t, handler, r)
+
+	assert.Regexp(t, `^192\.0\.2\.1 - - \[[^\]]+\] "POST /users HTTP/1\.1" 201 7\n$`, accessLog.String(), "common")
+
+}
+
+func Test_Middleware_Flush(t *testing.T) {
+
+	log.Stdout = bytes.NewBufferString("")
+	defer func() {
+		log.Stdout = os.Stdout
+	}()
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		assert.True(t, ok, "flusher")
+		w.Write([]byte("data: ping\n\n"))
+		flusher.Flush()
+	})
+
+	recorder := httptest.NewRecorder()
+	httplog.Middleware(handler).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/events", nil))
+
+	assert.True(t, recorder.Flushed, "flushed")
+	assert.Equal(t, "data: ping\n\n", recorder.Body.String(), "body")
+
+}
+
+func Test_Middleware_Hijack(t *testing.T) {
+
+	log.Stdout = bytes.NewBufferString("")
+	defer func() {
+		log.Stdout = os.Stdout
+	}()
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hijacker, ok := w.(http.Hijacker)
+		assert.True(t, ok, "hijacker")
+		conn, rw, err := hijacker.Hijack()
+		if !assert.NoError(t, err, "hijack") {
+			return
+		}
+		defer conn.Close()
+		rw.WriteString("HTTP/1.1 200 OK\r\nContent-Length: 8\r\nConnection: close\r\n\r\nhijacked")
+		rw.Flush()
+	})
+
+	server := httptest.NewServer(httplog.Middleware(handler))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if !assert.NoError(t, err, "get") {
+		return
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	assert.NoError(t, err, "read")
+	assert.Equal(t, "hijacked", string(body), "body")
+
+	unsupported := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _, err := w.(http.Hijacker).Hijack()
+		assert.Error(t, err, "unsupported")
+	})
+	httplog.Middleware(unsupported).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+}
@@ -0,0 +1,65 @@
+package httplog
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+// ClientEventName is the name of the event logged for each outgoing request
+var ClientEventName = "http_client_request"
+
+// Transport wraps next (http.DefaultTransport when nil) so that the request ID of the request context is sent in
+// the RequestIDHeader of outgoing requests and an event is logged for each outgoing request
+//
+// This correlates the logs across service hops when the called service uses Middleware. The logged URL leaves out the
+// user info, the query and the fragment, which often contain credentials or tokens.
+func Transport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &transport{next: next}
+}
+
+type transport struct {
+	next http.RoundTripper
+}
+
+func (t *transport) RoundTrip(r *http.Request) (*http.Response, error) {
+
+	requestID := log.RequestIDFromContext(r.Context())
+	if requestID != "" && r.Header.Get(RequestIDHeader) == "" {
+		r = r.Clone(r.Context())
+		r.Header.Set(RequestIDHeader, requestID)
+	}
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(r)
+
+	url := *r.URL
+	url.User = nil
+	url.RawQuery = ""
+	url.ForceQuery = false
+	url.Fragment = ""
+
+	properties := map[string]interface{}{
+		FieldMethod:   r.Method,
+		"url":         url.String(),
+		"duration_ms": float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if requestID != "" {
+		properties[FieldRequestID] = requestID
+	}
+	if err != nil {
+		properties["error"] = err.Error()
+	} else {
+		properties[FieldStatus] = resp.StatusCode
+		properties[FieldErrorClass] = errorClass(resp.StatusCode)
+	}
+
+	log.Event(ClientEventName, properties)
+
+	return resp, err
+
+}
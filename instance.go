@@ -0,0 +1,288 @@
+package log
+
+import (
+	"io"
+	"os"
+	"time"
+)
+
+// Logger is a logger with its own configuration, so that libraries can log without changing the package settings
+//
+// The package level functions log using a default Logger which uses the package settings (such as DebugMode or
+// Stdout). All other settings (such as Format, LevelStreams or ScrubRules) are shared by all loggers.
+type Logger struct {
+	Level          Level
+	DebugMode      bool
+	TraceMode      bool
+	PrintTimestamp bool
+	TimeFormat     string
+	TimeZone       *time.Location
+	Stdout         io.Writer
+	Stderr         io.Writer
+	CallerSkip     int
+
+	global bool
+	fields map[string]interface{}
+}
+
+// Option configures a Logger created with New
+type Option func(*Logger)
+
+// std is the default logger used by the package level functions, it uses the package settings
+var std = &Logger{global: true}
+
+// defaultTimeZone is the initial value of TimeZone, used by the loggers created with New
+var defaultTimeZone *time.Location
+
+// New returns a logger which writes to os.Stdout and os.Stderr without timestamps, configured by opts
+func New(opts ...Option) *Logger {
+
+	l := &Logger{
+		TimeFormat: DefaultTimeFormat,
+		TimeZone:   defaultTimeZone,
+		Stdout:     os.Stdout,
+		Stderr:     os.Stderr,
+	}
+
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	return l
+
+}
+
+// WithDebugMode sets if the logger prints debug messages
+func WithDebugMode(enabled bool) Option {
+	return func(l *Logger) {
+		l.DebugMode = enabled
+	}
+}
+
+// WithTraceMode sets if the logger prints trace messages
+func WithTraceMode(enabled bool) Option {
+	return func(l *Logger) {
+		l.TraceMode = enabled
+	}
+}
+
+// WithPrintTimestamp sets if the logger includes a timestamp in its messages
+func WithPrintTimestamp(enabled bool) Option {
+	return func(l *Logger) {
+		l.PrintTimestamp = enabled
+	}
+}
+
+// WithTimeFormat sets the format used for the timestamps of the logger
+func WithTimeFormat(format string) Option {
+	return func(l *Logger) {
+		l.TimeFormat = format
+	}
+}
+
+// WithTimeZone sets the timezone in which the timestamps of the logger are formatted
+func WithTimeZone(location *time.Location) Option {
+	return func(l *Logger) {
+		l.TimeZone = location
+	}
+}
+
+// WithCallerSkip sets the number of extra stack frames skipped when looking up the caller, for loggers used by
+// wrapper functions
+func WithCallerSkip(skip int) Option {
+	return func(l *Logger) {
+		l.CallerSkip = skip
+	}
+}
+
+// WithOutput sets the writers to which the logger writes its regular messages and its errors
+func WithOutput(stdout io.Writer, stderr io.Writer) Option {
+	return func(l *Logger) {
+		l.Stdout = stdout
+		l.Stderr = stderr
+	}
+}
+
+// WithField returns a copy of the logger which adds the field to all its entries
+func (l *Logger) WithField(key string, value interface{}) *Logger {
+	return l.WithFields(map[string]interface{}{key: value})
+}
+
+// WithFields returns a copy of the logger which adds the fields to all its entries
+//
+// The fields are added to the fields of the logger, fields passed to a single entry take precedence.
+func (l *Logger) WithFields(fields map[string]interface{}) *Logger {
+
+	if l == nil {
+		l = std
+	}
+
+	copied := *l
+	copied.fields = mergeFields(l.fields, fields)
+
+	return &copied
+
+}
+
+// Trace prints a trace message
+//
+// Only shown if TraceMode is set to true
+func (l *Logger) Trace(args ...interface{}) {
+	if l.traceMode() || isRecording() {
+		l.printEntry("TRACE", formatMessage(args...), nil)
+	}
+}
+
+// Tracef prints a formatted trace message
+//
+// Only shown if TraceMode is set to true
+func (l *Logger) Tracef(format string, args ...interface{}) {
+	if l.traceMode() || isRecording() {
+		l.printEntry("TRACE", formatMessagef(format, args...), nil)
+	}
+}
+
+// Debug prints a debug message
+//
+// Only shown if DebugMode is set to true
+func (l *Logger) Debug(args ...interface{}) {
+	if l.debugMode() || isRecording() {
+		l.printEntry("DEBUG", formatMessage(args...), nil)
+	}
+}
+
+// Debugf prints a formatted debug message
+//
+// Only shown if DebugMode is set to true
+func (l *Logger) Debugf(format string, args ...interface{}) {
+	if l.debugMode() || isRecording() {
+		l.printEntry("DEBUG", formatMessagef(format, args...), nil)
+	}
+}
+
+// Info prints an info message
+func (l *Logger) Info(args ...interface{}) {
+	l.printEntry("INFO", formatMessage(args...), nil)
+}
+
+// Infof prints a formatted info message
+func (l *Logger) Infof(format string, args ...interface{}) {
+	l.printEntry("INFO", formatMessagef(format, args...), nil)
+}
+
+// Notice prints a notice message
+func (l *Logger) Notice(args ...interface{}) {
+	l.printEntry("NOTE", formatMessage(args...), nil)
+}
+
+// Noticef prints a formatted notice message
+func (l *Logger) Noticef(format string, args ...interface{}) {
+	l.printEntry("NOTE", formatMessagef(format, args...), nil)
+}
+
+// Warn prints a warning message
+func (l *Logger) Warn(args ...interface{}) {
+	l.printEntry("WARN", formatMessage(args...), nil)
+}
+
+// Warnf prints a formatted warning message
+func (l *Logger) Warnf(format string, args ...interface{}) {
+	l.printEntry("WARN", formatMessagef(format, args...), nil)
+}
+
+// Error prints an error message to stderr
+func (l *Logger) Error(args ...interface{}) {
+	l.printEntry("ERROR", formatMessage(args...), nil)
+}
+
+// Errorf prints a formatted error message to stderr
+func (l *Logger) Errorf(format string, args ...interface{}) {
+	l.printEntry("ERROR", formatMessagef(format, args...), nil)
+}
+
+// Fatal logs a fatal error message to stderr and exits the program with exit code 1
+func (l *Logger) Fatal(args ...interface{}) {
+	l.printEntry("FATAL", formatMessage(args...), nil)
+	exit(l, 1)
+}
+
+// Fatalf logs a formatted fatal error message to stderr and exits the program with exit code 1
+func (l *Logger) Fatalf(format string, args ...interface{}) {
+	l.printEntry("FATAL", formatMessagef(format, args...), nil)
+	exit(l, 1)
+}
+
+// The accessors below return the package settings for the default logger (or a nil logger)
+
+func (l *Logger) isDefault() bool {
+	return l == nil || l.global
+}
+
+func (l *Logger) debugMode() bool {
+	if l.isDefault() {
+		return DebugMode
+	}
+	return l.DebugMode
+}
+
+func (l *Logger) traceMode() bool {
+	if l.isDefault() {
+		return TraceMode
+	}
+	return l.TraceMode
+}
+
+func (l *Logger) callerSkip() int {
+	if l.isDefault() {
+		return CallerSkip
+	}
+	return l.CallerSkip
+}
+
+func (l *Logger) printTimestamp() bool {
+	if l.isDefault() {
+		return PrintTimestamp
+	}
+	return l.PrintTimestamp
+}
+
+func (l *Logger) timeFormat() string {
+	if l.isDefault() || l.TimeFormat == "" {
+		return TimeFormat
+	}
+	return l.TimeFormat
+}
+
+func (l *Logger) timeZone() *time.Location {
+	if l.isDefault() || l.TimeZone == nil {
+		return TimeZone
+	}
+	return l.TimeZone
+}
+
+func (l *Logger) stdout() io.Writer {
+	if l.isDefault() || l.Stdout == nil {
+		return Stdout
+	}
+	return l.Stdout
+}
+
+func (l *Logger) stderr() io.Writer {
+	if l.isDefault() || l.Stderr == nil {
+		return Stderr
+	}
+	return l.Stderr
+}
+
+// levelEnabled reports if entries of the level are written, callers of printEntry only bypass this check while a
+// session is being recorded
+func (l *Logger) levelEnabled(level string) bool {
+	switch level {
+	case "TRACE":
+		return l.traceMode()
+	case "DEBUG":
+		return l.debugMode()
+	default:
+		return levelOf(level) >= l.level()
+	}
+}
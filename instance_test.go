@@ -0,0 +1,137 @@
+package log_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func Test_New_Defaults(t *testing.T) {
+
+	logger := log.New()
+
+	assert.False(t, logger.DebugMode, "debug-mode")
+	assert.False(t, logger.TraceMode, "trace-mode")
+	assert.False(t, logger.PrintTimestamp, "print-timestamp")
+	assert.Equal(t, log.DefaultTimeFormat, logger.TimeFormat, "time-format")
+	assert.NotNil(t, logger.TimeZone, "time-zone")
+	assert.NotNil(t, logger.Stdout, "stdout")
+	assert.NotNil(t, logger.Stderr, "stderr")
+
+}
+
+func Test_Logger(t *testing.T) {
+
+	type test struct {
+		name     string
+		opts     []log.Option
+		stdout   string
+		stderr   string
+		exitCode int
+	}
+
+	var tests = []test{
+		{
+			"default",
+			nil,
+			"info\nnotice\nwarn\n",
+			"error\nfatal\n",
+			1,
+		},
+		{
+			"debug-and-trace",
+			[]log.Option{log.WithDebugMode(true), log.WithTraceMode(true)},
+			"trace\ndebug\ninfo\nnotice\nwarn\n",
+			"error\nfatal\n",
+			1,
+		},
+		{
+			"timestamp",
+			[]log.Option{log.WithPrintTimestamp(true), log.WithTimeFormat(log.TestingTimeFormat), log.WithTimeZone(time.UTC)},
+			"test | INFO  | info\ntest | NOTE  | notice\ntest | WARN  | warn\n",
+			"test | ERROR | error\ntest | FATAL | fatal\n",
+			1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			resetLogConfig()
+			globalStdout, globalStderr := redirectOutput()
+			defer resetLogOutput()
+
+			oldOsExit := log.OsExit
+			defer func() {
+				log.OsExit = oldOsExit
+			}()
+
+			var got int
+			log.OsExit = func(code int) {
+				got = code
+			}
+
+			log.DebugMode = true
+
+			stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+			logger := log.New(append(tc.opts, log.WithOutput(stdout, stderr))...)
+			logger.Trace("trace")
+			logger.Debug("debug")
+			logger.Info("info")
+			logger.Notice("notice")
+			logger.Warn("warn")
+			logger.Error("error")
+			logger.Fatal("fatal")
+
+			assert.Equal(t, tc.stdout, stdout.String(), "stdout")
+			assert.Equal(t, tc.stderr, stderr.String(), "stderr")
+			assert.Equal(t, tc.exitCode, got, "exit-code")
+			assert.Equal(t, "", globalStdout.String(), "global-stdout")
+			assert.Equal(t, "", globalStderr.String(), "global-stderr")
+
+		})
+	}
+
+}
+
+func Test_Logger_Isolated(t *testing.T) {
+
+	resetLogConfig()
+	globalStdout, _ := redirectOutput()
+	defer resetLogOutput()
+
+	stdout := &bytes.Buffer{}
+	logger := log.New(log.WithDebugMode(true), log.WithOutput(stdout, stdout))
+
+	log.DebugMode = false
+	log.Debug("global debug")
+	logger.Debug("library debug")
+
+	log.Info("global info")
+
+	assert.Equal(t, "library debug\n", stdout.String(), "logger")
+	assert.Equal(t, "test | INFO  | global info\n", globalStdout.String(), "global")
+
+}
+
+func Test_Logger_Printf(t *testing.T) {
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	logger := log.New(log.WithTraceMode(true), log.WithDebugMode(true), log.WithOutput(stdout, stderr))
+
+	logger.Tracef("trace %d", 1)
+	logger.Debugf("debug %d", 2)
+	logger.Infof("info %d", 3)
+	logger.Noticef("notice %d", 4)
+	logger.Warnf("warn %d", 5)
+	logger.Errorf("error %d", 6)
+
+	assert.Equal(t, "trace 1\ndebug 2\ninfo 3\nnotice 4\nwarn 5\n", stdout.String(), "stdout")
+	assert.Equal(t, "error 6\n", stderr.String(), "stderr")
+
+}
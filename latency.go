@@ -0,0 +1,65 @@
+package log
+
+import (
+	"io"
+	"time"
+)
+
+// MeasureLatency indicates if the latency of the entries through the log pipeline is measured
+//
+// The latency of an entry is the time from the logging call until an output wrote it, for an AsyncWriter until its
+// background goroutine wrote it. It's reported per output in the Stats and passed to the hooks as
+// PipelineLatencyField, which isn't written to the outputs themselves.
+var MeasureLatency = false
+
+// PipelineLatencyField is the field holding the latency of the entry (as a time.Duration) which is passed to the
+// hooks when MeasureLatency is enabled
+//
+// As the hooks are fired before the AsyncWriters write the entry, it's the time until the entry was written to the
+// other outputs and queued by the AsyncWriters.
+const PipelineLatencyField = "pipeline_latency"
+
+// measuredWriter passes the start of the entry and the name of the output with the entries queued by an AsyncWriter,
+// so that their latency can be recorded once they are written
+type measuredWriter struct {
+	async *AsyncWriter
+	start time.Time
+	sink  string
+}
+
+func (w measuredWriter) Write(p []byte) (int, error) {
+	return w.writeLevel("INFO", p)
+}
+
+func (w measuredWriter) writeLevel(level string, p []byte) (int, error) {
+	return w.async.queueEntry(asyncEntry{level: level, p: append([]byte(nil), p...), start: w.start, sink: w.sink})
+}
+
+// writeMeasured writes the line and records its latency, the latency of the entries queued by an AsyncWriter is
+// recorded by the AsyncWriter
+func writeMeasured(e entry, w io.Writer, sinkName string, line []byte) (int, error) {
+
+	if e.start.IsZero() {
+		return writeWithRetries(sinkName, w, e.level, line)
+	}
+
+	if a, ok := w.(*AsyncWriter); ok {
+		return writeWithRetries(sinkName, measuredWriter{async: a, start: e.start, sink: sinkName}, e.level, line)
+	}
+
+	n, err := writeWithRetries(sinkName, w, e.level, line)
+	if err == nil {
+		health.recordLatency(sinkName, time.Since(e.start))
+	}
+
+	return n, err
+
+}
+
+// withLatency adds the PipelineLatencyField to the fields of the entry passed to the hooks
+func withLatency(e entry) entry {
+	if !e.start.IsZero() {
+		e.fields = mergeFields(e.fields, map[string]interface{}{PipelineLatencyField: time.Since(e.start)})
+	}
+	return e
+}
@@ -0,0 +1,111 @@
+package log_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+type slowWriter struct {
+	bytes.Buffer
+	delay time.Duration
+}
+
+func (w *slowWriter) Write(p []byte) (int, error) {
+	time.Sleep(w.delay)
+	return w.Buffer.Write(p)
+}
+
+func Test_MeasureLatency(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+
+	log.MeasureLatency = true
+	defer func() {
+		log.MeasureLatency = false
+	}()
+
+	var hookFields map[string]interface{}
+	defer log.AddHook(log.HookFunc(func(level log.Level, t time.Time, message string, fields map[string]interface{}) {
+		hookFields = fields
+	}))()
+
+	slow := &slowWriter{delay: 5 * time.Millisecond}
+	log.AddOutput(slow, log.OutputOptions{Name: "latency-sync"})
+	defer log.RemoveOutput(slow)
+
+	log.Info("first")
+	log.WithField("key", "value").Info("second")
+
+	stats, found := log.SinkStatsFor("latency-sync")
+	assert.True(t, found, "found")
+	assert.True(t, stats.LastLatency >= 5*time.Millisecond, "last")
+	assert.True(t, stats.AverageLatency >= 5*time.Millisecond, "average")
+	assert.True(t, stats.MaxLatency >= stats.AverageLatency, "max")
+
+	latency, ok := hookFields[log.PipelineLatencyField].(time.Duration)
+	assert.True(t, ok, "hook-field")
+	assert.True(t, latency >= 5*time.Millisecond, "hook-latency")
+	assert.Equal(t, "value", hookFields["key"], "hook-other-fields")
+
+	assert.NotContains(t, stdout.String(), log.PipelineLatencyField, "hidden")
+	assert.NotContains(t, slow.String(), log.PipelineLatencyField, "hidden-output")
+
+}
+
+func Test_MeasureLatency_Async(t *testing.T) {
+
+	resetLogConfig()
+	redirectOutput()
+	defer resetLogOutput()
+
+	log.MeasureLatency = true
+	defer func() {
+		log.MeasureLatency = false
+	}()
+
+	async := log.NewAsyncWriter(&slowWriter{delay: 5 * time.Millisecond}, log.AsyncOptions{})
+	defer async.Close()
+
+	log.AddOutput(async, log.OutputOptions{Name: "latency-async"})
+	defer log.RemoveOutput(async)
+
+	log.Info("first")
+	log.Info("second")
+
+	assert.NoError(t, async.Flush(), "flush")
+
+	stats, _ := log.SinkStatsFor("latency-async")
+	assert.True(t, stats.LastLatency >= 10*time.Millisecond, "last-includes-queue")
+	assert.True(t, stats.MaxLatency >= stats.LastLatency, "max")
+
+}
+
+func Test_MeasureLatency_Disabled(t *testing.T) {
+
+	resetLogConfig()
+	redirectOutput()
+	defer resetLogOutput()
+
+	var hookFields map[string]interface{}
+	defer log.AddHook(log.HookFunc(func(level log.Level, t time.Time, message string, fields map[string]interface{}) {
+		hookFields = fields
+	}))()
+
+	buf := &bytes.Buffer{}
+	log.AddOutput(buf, log.OutputOptions{Name: "latency-disabled"})
+	defer log.RemoveOutput(buf)
+
+	log.WithField("key", "value").Info("message")
+
+	stats, _ := log.SinkStatsFor("latency-disabled")
+	assert.Equal(t, time.Duration(0), stats.MaxLatency, "max")
+	assert.NotContains(t, hookFields, log.PipelineLatencyField, "hook-field")
+
+}
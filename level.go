@@ -0,0 +1,143 @@
+package log
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Level is the severity of a log entry
+type Level int
+
+const (
+	// TraceLevel is used for trace messages
+	TraceLevel Level = iota
+
+	// DebugLevel is used for debug messages
+	DebugLevel
+
+	// InfoLevel is used for info messages
+	InfoLevel
+
+	// NoticeLevel is used for notices
+	NoticeLevel
+
+	// WarnLevel is used for warnings
+	WarnLevel
+
+	// ErrorLevel is used for errors
+	ErrorLevel
+
+	// FatalLevel is used for fatal errors
+	FatalLevel
+)
+
+// levelLabels are the labels of the levels as they are shown in the entries
+var levelLabels = map[Level]string{
+	TraceLevel:  "TRACE",
+	DebugLevel:  "DEBUG",
+	InfoLevel:   "INFO",
+	NoticeLevel: "NOTE",
+	WarnLevel:   "WARN",
+	ErrorLevel:  "ERROR",
+	FatalLevel:  "FATAL",
+}
+
+// minLevel is the minimum level of the entries which are written, see SetLevel
+var minLevel = InfoLevel
+
+// String returns the label of the level as shown in the entries (e.g. "INFO")
+func (l Level) String() string {
+	if label, ok := levelLabels[l]; ok {
+		return label
+	}
+	return fmt.Sprintf("Level(%d)", int(l))
+}
+
+// ParseLevel returns the level with the given name (e.g. "debug", "WARN" or "notice")
+func ParseLevel(name string) (Level, error) {
+	switch strings.TrimSpace(strings.ToUpper(name)) {
+	case "TRACE":
+		return TraceLevel, nil
+	case "DEBUG":
+		return DebugLevel, nil
+	case "INFO":
+		return InfoLevel, nil
+	case "NOTE", "NOTICE":
+		return NoticeLevel, nil
+	case "WARN", "WARNING":
+		return WarnLevel, nil
+	case "ERROR":
+		return ErrorLevel, nil
+	case "FATAL":
+		return FatalLevel, nil
+	default:
+		return InfoLevel, fmt.Errorf("unknown level: %q", name)
+	}
+}
+
+// SetLevel sets the minimum level of the entries which are written
+//
+// DebugMode and TraceMode are kept as compatibility settings: SetLevel updates them and enabling either of them
+// lowers the minimum level (see GetLevel).
+func SetLevel(level Level) {
+	minLevel = level
+	DebugMode = level <= DebugLevel
+	TraceMode = level <= TraceLevel
+}
+
+// GetLevel returns the minimum level of the entries which are written
+func GetLevel() Level {
+	return std.level()
+}
+
+// WithLevel sets the minimum level of the entries the logger writes
+func WithLevel(level Level) Option {
+	return func(l *Logger) {
+		l.Level = level
+		l.DebugMode = level <= DebugLevel
+		l.TraceMode = level <= TraceLevel
+	}
+}
+
+func (l *Logger) level() Level {
+
+	level := InfoLevel
+	if l.isDefault() {
+		level = minLevel
+	} else if l.Level > InfoLevel {
+		level = l.Level
+	}
+
+	if level < InfoLevel {
+		level = InfoLevel
+	}
+	if l.debugMode() {
+		level = DebugLevel
+	}
+	if l.traceMode() {
+		level = TraceLevel
+	}
+
+	return level
+
+}
+
+// levelOf returns the level for the label of an entry, unknown labels are treated as info
+func levelOf(label string) Level {
+	level, _ := ParseLevel(label)
+	return level
+}
+
+// levelWriter is implemented by writers which need the level of each entry (such as SyslogWriter)
+type levelWriter interface {
+	io.Writer
+	writeLevel(level string, p []byte) (int, error)
+}
+
+func writeLevel(w io.Writer, level string, p []byte) (int, error) {
+	if lw, ok := w.(levelWriter); ok {
+		return lw.writeLevel(level, p)
+	}
+	return w.Write(p)
+}
@@ -0,0 +1,128 @@
+package log_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func TestParseLevel(t *testing.T) {
+
+	type test struct {
+		name     string
+		expected log.Level
+		hasError bool
+	}
+
+	var tests = []test{
+		{"trace", log.TraceLevel, false},
+		{"DEBUG", log.DebugLevel, false},
+		{" info ", log.InfoLevel, false},
+		{"notice", log.NoticeLevel, false},
+		{"NOTE", log.NoticeLevel, false},
+		{"warning", log.WarnLevel, false},
+		{"error", log.ErrorLevel, false},
+		{"fatal", log.FatalLevel, false},
+		{"verbose", log.InfoLevel, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := log.ParseLevel(tc.name)
+			assert.Equal(t, tc.expected, actual, "level")
+			assert.Equal(t, tc.hasError, err != nil, "error")
+		})
+	}
+
+}
+
+func TestLevel_String(t *testing.T) {
+	assert.Equal(t, "TRACE", log.TraceLevel.String())
+	assert.Equal(t, "NOTE", log.NoticeLevel.String())
+	assert.Equal(t, "FATAL", log.FatalLevel.String())
+	assert.Equal(t, "Level(42)", log.Level(42).String())
+}
+
+func TestSetLevel(t *testing.T) {
+
+	type test struct {
+		level         log.Level
+		expectedLevel log.Level
+		debugMode     bool
+		traceMode     bool
+		expected      string
+	}
+
+	var tests = []test{
+		{log.TraceLevel, log.TraceLevel, true, true, "trace\ndebug\ninfo\nwarn\nerror\n"},
+		{log.DebugLevel, log.DebugLevel, true, false, "debug\ninfo\nwarn\nerror\n"},
+		{log.InfoLevel, log.InfoLevel, false, false, "info\nwarn\nerror\n"},
+		{log.WarnLevel, log.WarnLevel, false, false, "warn\nerror\n"},
+		{log.FatalLevel, log.FatalLevel, false, false, ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.level.String(), func(t *testing.T) {
+
+			resetLogConfig()
+			log.PrintTimestamp = false
+			stdout, stderr := redirectOutput()
+			defer resetLogOutput()
+			defer log.SetLevel(log.InfoLevel)
+
+			log.SetLevel(tc.level)
+
+			assert.Equal(t, tc.expectedLevel, log.GetLevel(), "level")
+			assert.Equal(t, tc.debugMode, log.DebugMode, "debug-mode")
+			assert.Equal(t, tc.traceMode, log.TraceMode, "trace-mode")
+
+			log.Trace("trace")
+			log.Debug("debug")
+			log.Info("info")
+			log.Warn("warn")
+			log.Error("error")
+
+			assert.Equal(t, tc.expected, stdout.String()+stderr.String(), "output")
+
+		})
+	}
+
+}
+
+func TestGetLevel_DebugMode(t *testing.T) {
+
+	resetLogConfig()
+	defer resetLogConfig()
+
+	assert.Equal(t, log.InfoLevel, log.GetLevel(), "default")
+
+	log.DebugMode = true
+	assert.Equal(t, log.DebugLevel, log.GetLevel(), "debug-mode")
+
+	log.TraceMode = true
+	assert.Equal(t, log.TraceLevel, log.GetLevel(), "trace-mode")
+
+}
+
+func TestWithLevel(t *testing.T) {
+
+	resetLogConfig()
+	log.PrintTimestamp = false
+	stdout, stderr := redirectOutput()
+	defer resetLogOutput()
+
+	logger := log.New(log.WithLevel(log.ErrorLevel), log.WithOutput(stdout, stderr))
+
+	logger.Info("info")
+	logger.Warn("warn")
+	logger.Error("error")
+
+	assert.Equal(t, "", stdout.String(), "stdout")
+	assert.Equal(t, "error\n", stderr.String(), "stderr")
+
+	log.Warn("warn")
+	assert.Equal(t, "warn\n", stdout.String(), "default-logger")
+
+}
@@ -0,0 +1,82 @@
+//go:build android && cgo
+// +build android,cgo
+
+package log
+
+// #cgo LDFLAGS: -llog
+// #include <stdlib.h>
+// #include <android/log.h>
+import "C"
+
+import (
+	"bytes"
+	"unsafe"
+)
+
+// logcatMaxLength is the length after which the messages are split, logcat truncates longer messages
+const logcatMaxLength = 4000
+
+// logcatPriorities maps the levels to the logcat priorities
+var logcatPriorities = map[string]C.int{
+	"TRACE": C.ANDROID_LOG_VERBOSE,
+	"DEBUG": C.ANDROID_LOG_DEBUG,
+	"INFO":  C.ANDROID_LOG_INFO,
+	"NOTE":  C.ANDROID_LOG_INFO,
+	"WARN":  C.ANDROID_LOG_WARN,
+	"ERROR": C.ANDROID_LOG_ERROR,
+	"FATAL": C.ANDROID_LOG_FATAL,
+}
+
+// LogcatWriter is a writer which sends the log entries to the Android log (android only)
+//
+// The levels are mapped to the logcat priorities. Logcat adds the timestamps, so the entries don't include them,
+// regardless of PrintTimestamp.
+type LogcatWriter struct {
+	tag *C.char
+}
+
+// ToLogcat uses a LogcatWriter with the tag as Stdout and Stderr
+func ToLogcat(tag string) *LogcatWriter {
+	w := NewLogcatWriter(tag)
+	Stdout = w
+	Stderr = w
+	return w
+}
+
+// NewLogcatWriter returns a writer which sends the log entries to the Android log with the tag
+func NewLogcatWriter(tag string) *LogcatWriter {
+	return &LogcatWriter{tag: C.CString(tag)}
+}
+
+// Write sends p with the info priority
+func (w *LogcatWriter) Write(p []byte) (int, error) {
+	return w.writeLevel("INFO", p)
+}
+
+func (w *LogcatWriter) printTimestamp() bool {
+	return false
+}
+
+func (w *LogcatWriter) writeLevel(level string, p []byte) (int, error) {
+
+	priority, ok := logcatPriorities[level]
+	if !ok {
+		priority = C.ANDROID_LOG_INFO
+	}
+
+	msg := bytes.TrimRight(p, "\n")
+	for len(msg) > 0 {
+		chunk := msg
+		if len(chunk) > logcatMaxLength {
+			chunk = chunk[:logcatMaxLength]
+		}
+		msg = msg[len(chunk):]
+
+		text := C.CString(string(chunk))
+		C.__android_log_write(priority, w.tag, text)
+		C.free(unsafe.Pointer(text))
+	}
+
+	return len(p), nil
+
+}
@@ -0,0 +1,86 @@
+package log
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// logfmtLevels are the values of the level key of the logfmt formatted entries
+var logfmtLevels = map[string]string{
+	"NOTE": "notice",
+}
+
+// logfmtReserved are the keys written by the logfmt formatter itself, fields with the same key are left out
+var logfmtReserved = map[string]bool{
+	"level":     true,
+	"ts":        true,
+	"msg":       true,
+	"caller":    true,
+	"goroutine": true,
+}
+
+func formatLogfmt(e entry, timestamp bool) []byte {
+
+	level, ok := logfmtLevels[e.level]
+	if !ok {
+		level = strings.ToLower(e.level)
+	}
+
+	var b strings.Builder
+	b.WriteString("level=" + level)
+
+	if timestamp {
+		b.WriteString(" ts=" + e.time.UTC().Format(time.RFC3339Nano))
+	}
+
+	b.WriteString(" msg=" + logfmtValue(e.message))
+
+	if e.caller != "" {
+		b.WriteString(" caller=" + logfmtValue(e.caller))
+	}
+	if e.goroutine != 0 {
+		b.WriteString(" goroutine=" + strconv.FormatUint(e.goroutine, 10))
+	}
+
+	keys := make([]string, 0, len(e.fields))
+	for key := range e.fields {
+		if !logfmtReserved[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		b.WriteString(" " + logfmtKey(key) + "=" + logfmtValue(formatValue(e.fields[key])))
+	}
+
+	b.WriteByte('\n')
+	return []byte(b.String())
+
+}
+
+// logfmtKey replaces the characters which aren't allowed in logfmt keys by underscores
+func logfmtKey(key string) string {
+	if key == "" {
+		return "_"
+	}
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r == '=' || r == '"' || r == 0x7f {
+			return '_'
+		}
+		return r
+	}, key)
+}
+
+// logfmtValue quotes the value when it's empty or contains spaces, quotes, equal signs or control characters
+func logfmtValue(value string) string {
+	needsQuotes := value == "" || strings.IndexFunc(value, func(r rune) bool {
+		return r <= ' ' || r == '=' || r == '"' || r == 0x7f
+	}) >= 0
+	if needsQuotes {
+		return strconv.Quote(value)
+	}
+	return value
+}
@@ -0,0 +1,76 @@
+package log_test
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func Test_FormatLogfmt(t *testing.T) {
+
+	type test struct {
+		name     string
+		log      func()
+		expected string
+	}
+
+	var tests = []test{
+		{"info", func() { log.Info("message") }, "level=info msg=message\n"},
+		{"notice", func() { log.Notice("message") }, "level=notice msg=message\n"},
+		{"warn", func() { log.Warn("two words") }, "level=warn msg=\"two words\"\n"},
+		{"fields", func() { log.WithField("user", "jane doe").WithField("count", 2).Info("message") }, "level=info msg=message count=2 user=\"jane doe\"\n"},
+		{"quotes", func() { log.WithField("q", `a"b`).Info("a=b") }, "level=info msg=\"a=b\" q=\"a\\\"b\"\n"},
+		{"empty", func() { log.WithField("empty", "").Info("") }, "level=info msg=\"\" empty=\"\"\n"},
+		{"invalid-key", func() { log.WithField("a key", 1).Info("message") }, "level=info msg=message a_key=1\n"},
+		{"reserved-key", func() { log.WithField("level", "debug").Info("message") }, "level=info msg=message\n"},
+		{"duration", func() { log.WithField("took", 1500*time.Millisecond).Info("message") }, "level=info msg=message took=1.5s\n"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			resetLogConfig()
+			stdout, _ := redirectOutput()
+			defer resetLogOutput()
+
+			log.PrintTimestamp = false
+			log.Format = log.FormatLogfmt
+			defer func() {
+				log.Format = log.FormatText
+			}()
+
+			tc.log()
+
+			assert.Equal(t, tc.expected, stdout.String())
+
+		})
+	}
+
+}
+
+func Test_FormatLogfmt_Timestamp(t *testing.T) {
+
+	resetLogConfig()
+	_, stderr := redirectOutput()
+	defer resetLogOutput()
+
+	log.Format = log.FormatLogfmt
+	defer func() {
+		log.Format = log.FormatText
+	}()
+
+	log.Error("failed")
+
+	matches := regexp.MustCompile(`^level=error ts=(\S+) msg=failed\n$`).FindStringSubmatch(stderr.String())
+	if assert.Len(t, matches, 2, "format") {
+		_, err := time.Parse(time.RFC3339Nano, matches[1])
+		assert.NoError(t, err, "timestamp")
+		assert.True(t, strings.HasSuffix(matches[1], "Z"), "utc")
+	}
+
+}
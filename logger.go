@@ -3,21 +3,20 @@ package log
 import (
 	"io"
 	"os"
-	"strings"
 	"time"
-
-	"github.com/go-errors/errors"
-	"github.com/sanity-io/litter"
-
-	"github.com/pieterclaerhout/go-formatter"
 )
 
 // PrintTimestamp indicates if the log messages should include a timestamp or not
 var PrintTimestamp = false
 
 // DebugMode indicates if debug information should be printed or not
+//
+// It is kept for compatibility, SetLevel is the preferred way to set the minimum level
 var DebugMode = false
 
+// TraceMode indicates if trace information should be printed or not
+var TraceMode = false
+
 // DebugSQLMode indicates if the SQL statements should be logged as debug messages
 var DebugSQLMode = false
 
@@ -39,24 +38,43 @@ var TestingTimeFormat = "test"
 // TimeFormat is the format to use for the timestamps
 var TimeFormat = DefaultTimeFormat
 
-// OsExit is the function to exit the app when a fatal error happens
+// OsExit is the function to exit the app when a fatal error happens, see FatalPanic to run the deferred functions
+// first
 var OsExit = os.Exit
 
+// Trace prints a trace message
+//
+// Only shown if TraceMode is set to true
+func Trace(args ...interface{}) {
+	std.Trace(args...)
+}
+
+// Tracef prints a formatted trace message
+//
+// Only shown if TraceMode is set to true
+func Tracef(format string, args ...interface{}) {
+	std.Tracef(format, args...)
+}
+
 // Debug prints a debug message
 //
 // Only shown if DebugMode is set to true
 func Debug(args ...interface{}) {
-	if DebugMode {
-		message := formatMessage(args...)
-		printMessage("DEBUG", message)
-	}
+	std.Debug(args...)
+}
+
+// Debugf prints a formatted debug message
+//
+// Only shown if DebugMode is set to true
+func Debugf(format string, args ...interface{}) {
+	std.Debugf(format, args...)
 }
 
 // DebugSeparator prints a debug separator
 //
 // Only shown if DebugMode is set to true
 func DebugSeparator(args ...interface{}) {
-	if DebugMode {
+	if DebugMode || isRecording() {
 		message := formatMessage(args...)
 		message = formatSeparator(message, "=", 80)
 		printMessage("DEBUG", message)
@@ -68,7 +86,7 @@ func DebugSeparator(args ...interface{}) {
 // Only shown if DebugMode and DebugSQLMode are set to true
 func DebugSQL(sql string) {
 	if DebugSQLMode {
-		message, err := formatter.SQL(sql)
+		message, err := formatSQL(sql)
 		if err != nil {
 			Error(err)
 		} else {
@@ -77,9 +95,24 @@ func DebugSQL(sql string) {
 	}
 }
 
+// DebugSQLQuery prints the SQL statement as a debug message with its arguments (as SQL literals in the sql.args field)
+// and the duration of its execution
+//
+// Unlike DebugSQL, the statement isn't formatted, so that it and its arguments never leave the host and logging it
+// doesn't wait on the network. Only shown if DebugMode and DebugSQLMode are set to true
+func DebugSQLQuery(sql string, args []interface{}, duration time.Duration) {
+	if DebugSQLMode && (DebugMode || isRecording()) {
+		fields := map[string]interface{}{"duration": duration}
+		if len(args) > 0 {
+			fields[SQLArgsField] = formatSQLArgs(args)
+		}
+		printEntry("DEBUG", sql, fields)
+	}
+}
+
 // DebugDump dumps the argument as a debug message with an optional prefix
 func DebugDump(arg interface{}, prefix string) {
-	message := litter.Sdump(arg)
+	message := dumpFor("DEBUG", arg)
 	if prefix != "" {
 		Debug(prefix, message)
 	} else {
@@ -89,8 +122,12 @@ func DebugDump(arg interface{}, prefix string) {
 
 // Info prints an info message
 func Info(args ...interface{}) {
-	message := formatMessage(args...)
-	printMessage("INFO ", message)
+	std.Info(args...)
+}
+
+// Infof prints a formatted info message
+func Infof(format string, args ...interface{}) {
+	std.Infof(format, args...)
 }
 
 // InfoSeparator prints an info separator
@@ -102,7 +139,7 @@ func InfoSeparator(args ...interface{}) {
 
 // InfoDump dumps the argument as an info message with an optional prefix
 func InfoDump(arg interface{}, prefix string) {
-	message := litter.Sdump(arg)
+	message := dumpFor("INFO", arg)
 	if prefix != "" {
 		Info(prefix, message)
 	} else {
@@ -110,15 +147,31 @@ func InfoDump(arg interface{}, prefix string) {
 	}
 }
 
+// Notice prints a notice message
+//
+// Notices are used for significant events which are not warnings, such as configuration changes
+func Notice(args ...interface{}) {
+	std.Notice(args...)
+}
+
+// Noticef prints a formatted notice message
+func Noticef(format string, args ...interface{}) {
+	std.Noticef(format, args...)
+}
+
 // Warn prints an warning message
 func Warn(args ...interface{}) {
-	message := formatMessage(args...)
-	printMessage("WARN ", message)
+	std.Warn(args...)
+}
+
+// Warnf prints a formatted warning message
+func Warnf(format string, args ...interface{}) {
+	std.Warnf(format, args...)
 }
 
 // WarnDump dumps the argument as a warning message with an optional prefix
 func WarnDump(arg interface{}, prefix string) {
-	message := litter.Sdump(arg)
+	message := dumpFor("WARN", arg)
 	if prefix != "" {
 		Warn(prefix, message)
 	} else {
@@ -128,13 +181,17 @@ func WarnDump(arg interface{}, prefix string) {
 
 // Error prints an error message to stderr
 func Error(args ...interface{}) {
-	message := formatMessage(args...)
-	printMessage("ERROR", message)
+	std.Error(args...)
+}
+
+// Errorf prints a formatted error message to stderr
+func Errorf(format string, args ...interface{}) {
+	std.Errorf(format, args...)
 }
 
 // ErrorDump dumps the argument as an err message with an optional prefix to stderr
 func ErrorDump(arg interface{}, prefix string) {
-	message := litter.Sdump(arg)
+	message := dumpFor("ERROR", arg)
 	if prefix != "" {
 		Error(prefix, message)
 	} else {
@@ -143,24 +200,29 @@ func ErrorDump(arg interface{}, prefix string) {
 }
 
 // StackTrace prints an error message with the stacktrace of err to stderr
+//
+// The errors wrapped by err are shown with the stacks embedded in them (e.g. by github.com/pkg/errors), the stack of
+// the caller is used when none of them has one.
+//
+// See ColorStackTraces and StackTraceSourceLines for making the output easier to read
 func StackTrace(err error) {
-	message := formatMessage(FormattedStackTrace(err))
+	message := formatMessage(stackTraceMessage(err))
 	printMessage("ERROR", message)
 }
 
-// FormattedStackTrace returns a formatted stacktrace for err
+// FormattedStackTrace returns a formatted stacktrace for err, including the errors it wraps like StackTrace
 func FormattedStackTrace(err error) string {
-	if cause := causeOfError(err); cause != nil {
-		err = cause
-	}
-	return strings.TrimSpace(errors.Wrap(err, 2).ErrorStack())
+	return formatStackTrace(err, false, 0, 1)
 }
 
 // Fatal logs a fatal error message to stdout and exits the program with exit code 1
 func Fatal(args ...interface{}) {
-	message := formatMessage(args...)
-	printMessage("FATAL", message)
-	OsExit(1)
+	std.Fatal(args...)
+}
+
+// Fatalf logs a formatted fatal error message to stdout and exits the program with exit code 1
+func Fatalf(format string, args ...interface{}) {
+	std.Fatalf(format, args...)
 }
 
 // CheckError checks if the error is not nil and if that's the case, it will print a fatal message and exits the
@@ -169,10 +231,35 @@ func Fatal(args ...interface{}) {
 // If DebugMode is enabled a stack trace will also be printed to stderr
 func CheckError(err error) {
 	if err != nil {
-		printMessage("FATAL", err.Error())
-		if DebugMode {
-			StackTrace(err)
-		}
-		OsExit(1)
+		printError("FATAL", err, err.Error())
+		exit(std, 1)
+	}
+}
+
+// CheckErrorMsg checks if the error is not nil and if that's the case, it will print a fatal message with the
+// formatted msg describing what failed before the error (e.g. "reading config: file not found") and exits the program
+// with exit code 1.
+//
+// If DebugMode is enabled a stack trace will also be printed to stderr
+func CheckErrorMsg(err error, msg string, args ...interface{}) {
+	if err != nil {
+		printError("FATAL", err, contextMessage(msg, args)+": "+err.Error())
+		exit(std, 1)
+	}
+}
+
+// LogError checks if the error is not nil and if that's the case, it will print an error message and returns true,
+// so that an error can be logged and handled with a single check:
+//
+//	if log.LogError(err) {
+//		return
+//	}
+//
+// If DebugMode is enabled a stack trace will also be printed to stderr
+func LogError(err error) bool {
+	if err == nil {
+		return false
 	}
+	printError("ERROR", err, err.Error())
+	return true
 }
@@ -2,6 +2,8 @@ package log
 
 import (
 	"fmt"
+	"io"
+	"runtime"
 	"strings"
 	"sync"
 	"time"
@@ -9,16 +11,53 @@ import (
 
 var logMutex = &sync.Mutex{}
 
+// taps receive a copy of each formatted entry and are guarded by logMutex
+var taps []io.Writer
+
 func init() {
 	TimeZone, _ = time.LoadLocation("Europe/Brussels")
+	defaultTimeZone = TimeZone
 }
 
 func formatMessage(args ...interface{}) string {
+	copied := false
+	for i, arg := range args {
+		if d, ok := arg.(time.Duration); ok {
+			if !copied {
+				args = append([]interface{}{}, args...)
+				copied = true
+			}
+			args[i] = formatDuration(d)
+		}
+	}
 	msg := fmt.Sprintln(args...)
 	msg = strings.TrimRight(msg, " \n\r")
 	return msg
 }
 
+func formatMessagef(format string, args ...interface{}) string {
+	msg := fmt.Sprintf(format, args...)
+	msg = strings.TrimRight(msg, " \n\r")
+	return msg
+}
+
+// contextMessage formats the message describing what failed, without treating it as a format when there are no
+// arguments so that it can contain percent signs
+func contextMessage(msg string, args []interface{}) string {
+	if len(args) == 0 {
+		return strings.TrimRight(msg, " \n\r")
+	}
+	return formatMessagef(msg, args...)
+}
+
+// printError prints the message of err at the level, followed by its stack trace when DebugMode is enabled
+func printError(level string, err error, message string) {
+	printMessage(level, message)
+	if DebugMode {
+		StackTrace(err)
+	}
+}
+
 func formatSeparator(message string, separator string, length int) string {
 	if message == "" {
 		return strings.Repeat(separator, length)
@@ -32,42 +71,232 @@ func formatSeparator(message string, separator string, length int) string {
 	return prefix + "[ " + message + " ]" + suffix
 }
 
+type entry struct {
+	time      time.Time
+	start     time.Time
+	level     string
+	message   string
+	logger    *Logger
+	fields    map[string]interface{}
+	caller    string
+	frame     runtime.Frame
+	goroutine uint64
+	previous  time.Time
+	color     string
+}
+
 func printMessage(level string, message string) {
+	printEntry(level, message, nil)
+}
+
+func printEntry(level string, message string, fields map[string]interface{}) {
+	std.printEntry(level, message, fields)
+}
+
+func (l *Logger) printEntry(level string, message string, fields map[string]interface{}) {
+	l.logEntry(level, message, fields, true)
+}
+
+// logEntry writes the entry and fires the hooks (unless runHooks is false)
+func (l *Logger) logEntry(level string, message string, fields map[string]interface{}, runHooks bool) {
+	l.logEntryAt(level, message, fields, runHooks, time.Time{}, 0)
+}
+
+// logEntryAt is logEntry for an entry which happened at t (now when zero) and was logged at the program counter pc
+// (the caller is looked up when 0), such as a slog record
+func (l *Logger) logEntryAt(level string, message string, fields map[string]interface{}, runHooks bool, t time.Time, pc uintptr) {
+
+	level = strings.TrimSpace(strings.ToUpper(level))
+
+	if levelOf(level) > DebugLevel && !l.levelEnabled(level) && !isRecording() {
+		return
+	}
+
+	if s := currentSampler(); s != nil && !s.Sample(levelOf(level), message) {
+		return
+	}
+
+	var start time.Time
+	if MeasureLatency {
+		start = time.Now()
+	}
+
+	if l != nil && len(l.fields) > 0 {
+		fields = mergeFields(l.fields, fields)
+	}
+
+	message, fields = redactEntry(level, message, fields)
+
+	if EntryIDs {
+		fields = withEntryID(fields)
+	}
+
+	if t.IsZero() {
+		t = time.Now()
+	}
 
 	logMutex.Lock()
 
-	level = strings.ToUpper(level)
+	e := entry{
+		time:    t,
+		start:   start,
+		level:   level,
+		message: message,
+		logger:  l,
+		fields:  fields,
+	}
 
-	if PrintTimestamp {
-		formattedTime := time.Now().In(TimeZone).Format(TimeFormat)
-		message = formattedTime + " | " + level + " | " + message
+	if ReportCaller {
+		var ok bool
+		if pc != 0 {
+			e.frame, ok = findCallerPC(pc)
+		} else {
+			e.frame, ok = findCaller(l.callerSkip())
+		}
+		if ok {
+			e.caller = formatCallerFrame(e.frame, CallerFormat)
+		}
 	}
 
-	w := Stdout
-	if level == "ERROR" || level == "FATAL" {
-		w = Stderr
+	if PrintGoroutineID {
+		e.goroutine = GoroutineID()
 	}
 
-	fmt.Fprint(w, message+"\n")
+	if isRecording() {
+		recordEntry(e)
+		if !l.levelEnabled(e.level) {
+			logMutex.Unlock()
+			return
+		}
+	}
+
+	e, written := writeEntry(e)
+
+	var hs []*registeredHook
+	if runHooks && written {
+		hs = hooks
+	}
 
 	logMutex.Unlock()
 
+	if len(hs) > 0 {
+		fireHooks(hs, withLatency(e))
+	}
+
 }
 
-func causeOfError(err error) error {
+// redactEntry applies the scrub rules to the message and the fields of an entry and encrypts its EncryptedFields
+func redactEntry(level string, message string, fields map[string]interface{}) (string, map[string]interface{}) {
+
+	if len(ScrubRules) > 0 {
+		message = scrubMessage(level, message)
+		fields = scrubFields(level, fields)
+	}
 
-	type causer interface {
-		Cause() error
+	if len(EncryptedFields) > 0 && EncryptionKey != nil {
+		fields = encryptFields(fields)
 	}
 
-	for err != nil {
-		cause, ok := err.(causer)
-		if !ok {
-			break
+	return message, fields
+
+}
+
+// writeEntry sends the entry to the outputs and must be called while holding logMutex
+//
+// It returns the entry as written (after applying the transform rules) and false when the entry was dropped.
+func writeEntry(e entry) (entry, bool) {
+
+	route := ""
+	if len(TransformRules) > 0 {
+		var ok bool
+		if e, route, ok = applyTransformRules(e); !ok {
+			return e, false
 		}
-		err = cause.Cause()
+	}
+
+	e.previous, lastEntryTime = lastEntryTime, e.time
+
+	if e.level == "ERROR" || e.level == "FATAL" {
+		health.recordError(e.time)
+	}
+
+	w, sinkName := writerForRoute(e.logger, route, e.level)
+	if route == "" && len(FieldRoutes) > 0 {
+		if fw, name, ok := writerForFields(e); ok {
+			w, sinkName = fw, name
+		}
+	}
+
+	// The entry only goes to the dead letter when none of the sinks got it, replaying it would duplicate it otherwise
+	delivered := writeOutput(e, w, sinkName, Format, CallerDefault) == nil
+
+	for _, o := range outputs {
+		if o.opts.MinLevel > levelOf(e.level) {
+			continue
+		}
+		if writeOutput(e, o.w, o.name, o.opts.Format, o.opts.CallerFormat) == nil {
+			delivered = true
+		}
+	}
+
+	if !delivered {
+		writeDeadLetter(e)
+	}
+
+	if len(taps) > 0 {
+		line := formatEntry(e, e.logger.printTimestamp())
+		for _, tap := range taps {
+			tap.Write(line)
+		}
+	}
+
+	if hook := currentStatsDHook(); hook != nil {
+		hook.countEntry(e.level)
+	}
+
+	return e, true
+
+}
+
+// writeOutput formats the entry for the output and writes it, it must be called while holding logMutex
+func writeOutput(e entry, w io.Writer, sinkName string, format OutputFormat, callerFormat CallerStyle) error {
+
+	colored := e
+	colored.color = colorFor(e.level, w)
+
+	if callerFormat != CallerDefault && e.caller != "" && e.frame.PC != 0 {
+		colored.caller = formatCallerFrame(e.frame, callerFormat)
+	}
+
+	if SequenceNumbers {
+		colored.fields = mergeFields(e.fields, map[string]interface{}{SequenceField: nextSequenceNumber(sinkName, w)})
+	}
+
+	line := formatEntryAs(colored, format, timestampFor(w, e.logger.printTimestamp()))
+	line = prefixPriority(highlightLine(line, e.level, w), e.level, w)
+
+	if SigningKey != nil {
+		line = signLine(sinkName, w, line, format)
+	}
+
+	n, err := writeMeasured(e, w, sinkName, line)
+	health.recordWrite(sinkName, e.time, n, err)
+
+	if q, ok := w.(queuedWriter); ok {
+		health.recordQueueDepth(sinkName, q.queueDepth())
 	}
 
 	return err
 
 }
+
+func mergeFields(base map[string]interface{}, fields map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(base)+len(fields))
+	for key, value := range base {
+		merged[key] = value
+	}
+	for key, value := range fields {
+		merged[key] = value
+	}
+	return merged
+}
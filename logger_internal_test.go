@@ -116,18 +116,21 @@ func (e *customError) Cause() error {
 	return customErrorCause
 }
 
-func Test_causeOfError(t *testing.T) {
+func Test_unwrapError(t *testing.T) {
 
 	err := newCustomError("custom error")
-	cause := causeOfError(err)
+	cause := unwrapError(err)
 
 	assert.EqualValues(t, customErrorCause, cause)
+	assert.Nil(t, unwrapError(cause), "root")
 
 }
 
 func resetLogConfig() {
+	SetLevel(InfoLevel)
 	PrintTimestamp = false
 	DebugMode = false
+	TraceMode = false
 	DebugSQLMode = false
 	TimeZone, _ = time.LoadLocation("Europe/Brussels")
 	TimeFormat = TestingTimeFormat
@@ -146,3 +149,13 @@ func resetLogOutput() {
 	Stderr = os.Stderr
 	TimeFormat = DefaultTimeFormat
 }
+
+func Test_mergeFields(t *testing.T) {
+
+	base := map[string]interface{}{"a": 1, "b": 2}
+	merged := mergeFields(base, map[string]interface{}{"b": 3, "c": 4})
+
+	assert.Equal(t, map[string]interface{}{"a": 1, "b": 3, "c": 4}, merged, "merged")
+	assert.Equal(t, map[string]interface{}{"a": 1, "b": 2}, base, "base-unchanged")
+
+}
@@ -125,6 +125,46 @@ func Test_DebugSQL_Disabled(t *testing.T) {
 
 }
 
+func Test_DebugSQLQuery(t *testing.T) {
+
+	type test struct {
+		name           string
+		debugMode      bool
+		debugSQLMode   bool
+		sql            string
+		args           []interface{}
+		expectedStdout string
+		expectedStderr string
+	}
+
+	var tests = []test{
+		{"enabled", true, true, "select * from t where id = ? and name = ?", []interface{}{1, "jane"}, "test | DEBUG | select * from t where id = ? and name = ? duration=1.5s sql.args=\"1, 'jane'\"\n", ""},
+		{"enabled-no-args", true, true, "select 1", nil, "test | DEBUG | select 1 duration=1.5s\n", ""},
+		{"enabled-invalid", true, true, "throw-error", nil, "test | DEBUG | throw-error duration=1.5s\n", ""},
+		{"debug-sql-disabled", true, false, "select 1", nil, "", ""},
+		{"debug-disabled", false, true, "select 1", nil, "", ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			resetLogConfig()
+			stdout, stderr := redirectOutput()
+			defer resetLogOutput()
+
+			log.DebugMode = tc.debugMode
+			log.DebugSQLMode = tc.debugSQLMode
+
+			log.DebugSQLQuery(tc.sql, tc.args, 1500*time.Millisecond)
+
+			assert.Equal(t, tc.expectedStdout, stdout.String(), "stdout")
+			assert.Equal(t, tc.expectedStderr, stderr.String(), "stderr")
+
+		})
+	}
+
+}
+
 func Test_DebugSeparator_Disabled(t *testing.T) {
 
 	resetLogConfig()
@@ -423,6 +463,82 @@ func Test_Fatal(t *testing.T) {
 
 }
 
+func Test_Printf(t *testing.T) {
+
+	type test struct {
+		name           string
+		print          func(format string, args ...interface{})
+		expectedStdout string
+		expectedStderr string
+	}
+
+	var tests = []test{
+		{"trace", log.Tracef, "test | TRACE | user 42 took 1.5s\n", ""},
+		{"debug", log.Debugf, "test | DEBUG | user 42 took 1.5s\n", ""},
+		{"info", log.Infof, "test | INFO  | user 42 took 1.5s\n", ""},
+		{"notice", log.Noticef, "test | NOTE  | user 42 took 1.5s\n", ""},
+		{"warn", log.Warnf, "test | WARN  | user 42 took 1.5s\n", ""},
+		{"error", log.Errorf, "", "test | ERROR | user 42 took 1.5s\n"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			resetLogConfig()
+			stdout, stderr := redirectOutput()
+			defer resetLogOutput()
+
+			log.DebugMode = true
+			log.TraceMode = true
+
+			tc.print("user %d took %v\n", 42, 1500*time.Millisecond)
+
+			assert.Equal(t, tc.expectedStdout, stdout.String(), "stdout")
+			assert.Equal(t, tc.expectedStderr, stderr.String(), "stderr")
+
+		})
+	}
+
+}
+
+func Test_Printf_Disabled(t *testing.T) {
+
+	resetLogConfig()
+	stdout, stderr := redirectOutput()
+	defer resetLogOutput()
+
+	log.Tracef("trace %d", 1)
+	log.Debugf("debug %d", 1)
+
+	assert.Equal(t, "", stdout.String(), "stdout")
+	assert.Equal(t, "", stderr.String(), "stderr")
+
+}
+
+func Test_Fatalf(t *testing.T) {
+
+	resetLogConfig()
+	stdout, stderr := redirectOutput()
+	defer resetLogOutput()
+
+	oldOsExit := log.OsExit
+	defer func() {
+		log.OsExit = oldOsExit
+	}()
+
+	var got int
+	log.OsExit = func(code int) {
+		got = code
+	}
+
+	log.Fatalf("fatal error: %s", "boom")
+
+	assert.Equal(t, "", stdout.String(), "stdout")
+	assert.Equal(t, "test | FATAL | fatal error: boom\n", stderr.String(), "stderr")
+	assert.Equal(t, 1, got, "exit-code")
+
+}
+
 func Test_CheckError(t *testing.T) {
 
 	type test struct {
@@ -482,9 +598,105 @@ func Test_CheckError(t *testing.T) {
 
 }
 
+func Test_CheckErrorMsg(t *testing.T) {
+
+	type test struct {
+		name             string
+		err              error
+		msg              string
+		args             []interface{}
+		expectedStderr   string
+		expectedExitCode int
+	}
+
+	var tests = []test{
+		{"nil", nil, "reading config", nil, "", 0},
+		{"message", errors.New("file not found"), "reading config", nil, "test | FATAL | reading config: file not found\n", 1},
+		{"args", errors.New("file not found"), "reading %s", []interface{}{"config.yaml"}, "test | FATAL | reading config.yaml: file not found\n", 1},
+		{"percent", errors.New("disk full"), "writing at 100%", nil, "test | FATAL | writing at 100%: disk full\n", 1},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			resetLogConfig()
+			stdout, stderr := redirectOutput()
+			defer resetLogOutput()
+
+			oldOsExit := log.OsExit
+			defer func() {
+				log.OsExit = oldOsExit
+			}()
+
+			got := 0
+			log.OsExit = func(code int) {
+				got = code
+			}
+
+			log.CheckErrorMsg(tc.err, tc.msg, tc.args...)
+
+			assert.Equal(t, "", stdout.String(), "stdout")
+			assert.Equal(t, tc.expectedStderr, stderr.String(), "stderr")
+			assert.Equal(t, tc.expectedExitCode, got, "exit-code")
+
+		})
+	}
+
+}
+
+func Test_LogError(t *testing.T) {
+
+	type test struct {
+		name           string
+		err            error
+		debug          bool
+		expected       bool
+		expectedStderr string
+	}
+
+	var tests = []test{
+		{"nil", nil, false, false, ""},
+		{"nil-debug", nil, true, false, ""},
+		{"err", errors.New("test"), false, true, "test | ERROR | test\n"},
+		{"err-debug", errors.New("test"), true, true, "test | ERROR | test\ntest | ERROR | *errors.fundamental test\n"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			resetLogConfig()
+			stdout, stderr := redirectOutput()
+			defer resetLogOutput()
+
+			oldOsExit := log.OsExit
+			defer func() {
+				log.OsExit = oldOsExit
+			}()
+
+			exited := false
+			log.OsExit = func(code int) {
+				exited = true
+			}
+
+			log.DebugMode = tc.debug
+
+			actual := log.LogError(tc.err)
+
+			assert.Equal(t, tc.expected, actual, "result")
+			assert.Equal(t, "", stdout.String(), "stdout")
+			assert.True(t, strings.HasPrefix(stderr.String(), tc.expectedStderr), "stderr")
+			assert.False(t, exited, "exited")
+
+		})
+	}
+
+}
+
 func resetLogConfig() {
+	log.SetLevel(log.InfoLevel)
 	log.PrintTimestamp = true
 	log.DebugMode = false
+	log.TraceMode = false
 	log.DebugSQLMode = false
 	log.TimeZone, _ = time.LoadLocation("Europe/Brussels")
 	log.TimeFormat = log.TestingTimeFormat
@@ -0,0 +1,300 @@
+// Package logreader queries the entries of the log files written by github.com/pieterclaerhout/go-log
+package logreader
+
+import (
+	"bufio"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+// backupTimeFormat is the format of the timestamp in the names of the files rotated by a RotatingFileSink
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// searchThreshold is the size of the part of a file below which it is scanned instead of bisected
+const searchThreshold = 64 * 1024
+
+// maxLineSize is the size of the longest line which can be read
+const maxLineSize = 1024 * 1024
+
+// Entry is an entry read from a log file
+type Entry struct {
+	Time    time.Time
+	Level   log.Level
+	Message string
+
+	// File is the path of the file the entry was read from
+	File string
+
+	// Line is the entry as it was written, including the lines following it which aren't entries themselves (such as
+	// the lines of a stack trace)
+	Line string
+}
+
+// LevelFilter reports whether the entries of a level are returned by Query
+type LevelFilter func(level log.Level) bool
+
+// MinLevel returns a LevelFilter which selects the entries of the level or above
+func MinLevel(min log.Level) LevelFilter {
+	return func(level log.Level) bool {
+		return level >= min
+	}
+}
+
+// Levels returns a LevelFilter which selects the entries of the given levels
+func Levels(levels ...log.Level) LevelFilter {
+	return func(level log.Level) bool {
+		for _, l := range levels {
+			if l == level {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Query returns the entries from the log files in dir between from and to (both included) whose level matches the
+// filter, ordered by time
+//
+// A zero from or to leaves that side of the window open and a nil filter selects all levels. The rotated files whose
+// names show they fall outside of the window are skipped and the plain text files are bisected to find the first
+// entry of the window, so querying a recent window only reads a small part of the files.
+//
+// The text entries are parsed using the current TimeFormat and TimeZone, entries without a timestamp are skipped.
+// Encrypted files are skipped as well.
+func Query(dir string, from time.Time, to time.Time, filter LevelFilter) ([]Entry, error) {
+
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []Entry{}
+	for _, f := range filesInWindow(infos, from, to) {
+		found, err := queryFile(filepath.Join(dir, f.name), from, to, filter)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, found...)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Time.Before(entries[j].Time)
+	})
+
+	return entries, nil
+
+}
+
+type logFile struct {
+	name  string
+	group string
+
+	// rotated is the time the file was rotated at, zero for the file which is still written to
+	rotated time.Time
+}
+
+// filesInWindow returns the files which can contain entries between from and to
+//
+// The entries of a rotated file were written between the rotation of the previous file of the same log and its own
+// rotation.
+func filesInWindow(infos []os.FileInfo, from time.Time, to time.Time) []logFile {
+
+	groups := map[string][]logFile{}
+	for _, info := range infos {
+		if info.IsDir() || strings.HasPrefix(info.Name(), ".") || strings.HasSuffix(info.Name(), ".enc") {
+			continue
+		}
+		f := parseFileName(info.Name())
+		groups[f.group] = append(groups[f.group], f)
+	}
+
+	files := []logFile{}
+	for _, group := range groups {
+
+		sort.Slice(group, func(i, j int) bool {
+			if group[i].rotated.IsZero() != group[j].rotated.IsZero() {
+				return group[j].rotated.IsZero()
+			}
+			return group[i].rotated.Before(group[j].rotated)
+		})
+
+		var previous time.Time
+		for _, f := range group {
+			afterFrom := from.IsZero() || f.rotated.IsZero() || !f.rotated.Before(from)
+			beforeTo := to.IsZero() || previous.IsZero() || !previous.After(to)
+			if afterFrom && beforeTo {
+				files = append(files, f)
+			}
+			previous = f.rotated
+		}
+
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].name < files[j].name
+	})
+
+	return files
+
+}
+
+// parseFileName returns the log the file belongs to and when it was rotated, based on the name of the file
+func parseFileName(name string) logFile {
+
+	base := strings.TrimSuffix(name, ".gz")
+	ext := filepath.Ext(base)
+	stem := strings.TrimSuffix(base, ext)
+
+	if len(stem) > len(backupTimeFormat) && stem[len(stem)-len(backupTimeFormat)-1] == '-' {
+		t, err := time.Parse(backupTimeFormat, stem[len(stem)-len(backupTimeFormat):])
+		if err == nil {
+			return logFile{name: name, group: stem[:len(stem)-len(backupTimeFormat)-1] + ext, rotated: t}
+		}
+	}
+
+	return logFile{name: name, group: base}
+
+}
+
+func queryFile(path string, from time.Time, to time.Time, filter LevelFilter) ([]Entry, error) {
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		r = gz
+	} else if !from.IsZero() {
+		info, err := f.Stat()
+		if err != nil {
+			return nil, err
+		}
+		offset, err := search(f, info.Size(), from)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := f.Seek(offset, io.SeekStart); err != nil {
+			return nil, err
+		}
+		br := bufio.NewReader(f)
+		if offset > 0 {
+			// The offset is in the middle of an entry which was written before from
+			if _, err := br.ReadString('\n'); err != nil && err != io.EOF {
+				return nil, err
+			}
+		}
+		r = br
+	}
+
+	return scan(r, path, from, to, filter)
+
+}
+
+// search bisects the file to find an offset before the first entry written at or after from
+func search(f *os.File, size int64, from time.Time) (int64, error) {
+
+	lo, hi := int64(0), size
+
+	for hi-lo > searchThreshold {
+
+		mid := lo + (hi-lo)/2
+
+		t, ok, err := firstTimeAfter(f, mid, hi)
+		if err != nil {
+			return 0, err
+		}
+
+		if !ok || !t.Before(from) {
+			hi = mid
+		} else {
+			lo = mid
+		}
+
+	}
+
+	return lo, nil
+
+}
+
+// firstTimeAfter returns the time of the first entry which starts after offset and before limit
+func firstTimeAfter(f *os.File, offset int64, limit int64) (time.Time, bool, error) {
+
+	r := bufio.NewReader(io.NewSectionReader(f, offset, limit-offset))
+
+	// The first line is skipped as it's likely only the end of a line
+	if _, err := r.ReadString('\n'); err != nil {
+		if err == io.EOF {
+			return time.Time{}, false, nil
+		}
+		return time.Time{}, false, err
+	}
+
+	for {
+		line, err := r.ReadString('\n')
+		if e, ok := parseLine(strings.TrimRight(line, "\r\n")); ok {
+			return e.Time, true, nil
+		}
+		if err == io.EOF {
+			return time.Time{}, false, nil
+		}
+		if err != nil {
+			return time.Time{}, false, err
+		}
+	}
+
+}
+
+// scan reads the entries between from and to, lines which aren't entries are added to the previous entry
+func scan(r io.Reader, path string, from time.Time, to time.Time, filter LevelFilter) ([]Entry, error) {
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	entries := []Entry{}
+	matching := false
+
+	for scanner.Scan() {
+
+		line := strings.TrimRight(scanner.Text(), "\r")
+
+		e, ok := parseLine(line)
+		if !ok {
+			if matching {
+				last := &entries[len(entries)-1]
+				last.Line += "\n" + line
+			}
+			continue
+		}
+
+		if !to.IsZero() && e.Time.After(to) {
+			break
+		}
+
+		matching = !e.Time.Before(from) && (filter == nil || filter(e.Level))
+		if matching {
+			e.File = path
+			entries = append(entries, e)
+		}
+
+	}
+
+	return entries, scanner.Err()
+
+}
@@ -0,0 +1,80 @@
+package logreader
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func Test_parseFileName(t *testing.T) {
+
+	var tests = []struct {
+		name            string
+		expectedGroup   string
+		expectedRotated time.Time
+	}{
+		{"app.log", "app.log", time.Time{}},
+		{"app-2020-03-01T10-00-00.000.log", "app.log", time.Date(2020, 3, 1, 10, 0, 0, 0, time.UTC)},
+		{"app-2020-03-01T10-00-00.000.log.gz", "app.log", time.Date(2020, 3, 1, 10, 0, 0, 0, time.UTC)},
+		{"my-app-2020-03-01T10-00-00.500.log", "my-app.log", time.Date(2020, 3, 1, 10, 0, 0, 500000000, time.UTC)},
+		{"app-invalid.log", "app-invalid.log", time.Time{}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			f := parseFileName(tc.name)
+			assert.Equal(t, tc.expectedGroup, f.group, "group")
+			assert.True(t, tc.expectedRotated.Equal(f.rotated), "rotated")
+		})
+	}
+
+}
+
+func Test_search(t *testing.T) {
+
+	dir, _ := ioutil.TempDir("", "go-log")
+	defer os.RemoveAll(dir)
+
+	start := time.Date(2020, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	var b strings.Builder
+	offsets := []int64{}
+	for i := 0; i < 20000; i++ {
+		offsets = append(offsets, int64(b.Len()))
+		ts := start.Add(time.Duration(i) * time.Second).In(log.TimeZone).Format(log.TimeFormat)
+		fmt.Fprintf(&b, "%s | INFO  | entry %d\n", ts, i)
+	}
+
+	path := filepath.Join(dir, "app.log")
+	ioutil.WriteFile(path, []byte(b.String()), 0644)
+
+	f, _ := os.Open(path)
+	defer f.Close()
+
+	var tests = []struct {
+		name  string
+		entry int
+	}{
+		{"first", 0},
+		{"middle", 10000},
+		{"last", 19999},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			offset, err := search(f, int64(b.Len()), start.Add(time.Duration(tc.entry)*time.Second))
+			assert.NoError(t, err)
+			assert.True(t, offset <= offsets[tc.entry], "before-entry")
+			assert.True(t, offsets[tc.entry]-offset <= searchThreshold+int64(len("entry 19999")+40), "close-to-entry")
+		})
+	}
+
+}
@@ -0,0 +1,155 @@
+package logreader_test
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+	"github.com/pieterclaerhout/go-log/logreader"
+)
+
+var start = time.Date(2020, 3, 1, 10, 0, 0, 0, time.UTC)
+
+func textLine(t time.Time, level string, message string) string {
+	return fmt.Sprintf("%s | %-5s | %s\n", t.In(log.TimeZone).Format(log.TimeFormat), level, message)
+}
+
+// writeLogs writes an hour of entries (one per minute, every tenth an error) to each of the files, the first one is
+// compressed
+func writeLogs(t *testing.T, dir string) {
+
+	files := []string{
+		"app-" + start.Add(time.Hour).Format("2006-01-02T15-04-05.000") + ".log.gz",
+		"app-" + start.Add(2*time.Hour).Format("2006-01-02T15-04-05.000") + ".log",
+		"app.log",
+	}
+
+	for i, name := range files {
+
+		var b strings.Builder
+		for m := 0; m < 60; m++ {
+			ts := start.Add(time.Duration(i)*time.Hour + time.Duration(m)*time.Minute)
+			if m%10 == 0 {
+				b.WriteString(textLine(ts, "ERROR", fmt.Sprintf("error %d:%02d", 10+i, m)))
+				b.WriteString("stack trace\n")
+			} else {
+				b.WriteString(textLine(ts, "INFO", fmt.Sprintf("info %d:%02d", 10+i, m)))
+			}
+		}
+
+		f, err := os.Create(filepath.Join(dir, name))
+		assert.NoError(t, err, "create")
+		if strings.HasSuffix(name, ".gz") {
+			gz := gzip.NewWriter(f)
+			gz.Write([]byte(b.String()))
+			gz.Close()
+		} else {
+			f.WriteString(b.String())
+		}
+		f.Close()
+
+	}
+
+}
+
+func messages(entries []logreader.Entry) []string {
+	result := []string{}
+	for _, e := range entries {
+		result = append(result, e.Message)
+	}
+	return result
+}
+
+func Test_Query(t *testing.T) {
+
+	dir, _ := ioutil.TempDir("", "go-log")
+	defer os.RemoveAll(dir)
+
+	writeLogs(t, dir)
+	ioutil.WriteFile(filepath.Join(dir, "app-2020-03-01T09-00-00.000.log.enc"), []byte("encrypted"), 0644)
+
+	var tests = []struct {
+		name     string
+		from     time.Time
+		to       time.Time
+		filter   logreader.LevelFilter
+		expected []string
+	}{
+		{"errors-all", time.Time{}, time.Time{}, logreader.MinLevel(log.ErrorLevel), []string{
+			"error 10:00", "error 10:10", "error 10:20", "error 10:30", "error 10:40", "error 10:50",
+			"error 11:00", "error 11:10", "error 11:20", "error 11:30", "error 11:40", "error 11:50",
+			"error 12:00", "error 12:10", "error 12:20", "error 12:30", "error 12:40", "error 12:50",
+		}},
+		{"across-files", start.Add(55 * time.Minute), start.Add(62 * time.Minute), nil, []string{
+			"info 10:55", "info 10:56", "info 10:57", "info 10:58", "info 10:59",
+			"error 11:00", "info 11:01", "info 11:02",
+		}},
+		{"compressed", start.Add(5 * time.Minute), start.Add(7 * time.Minute), nil, []string{
+			"info 10:05", "info 10:06", "info 10:07",
+		}},
+		{"active", start.Add(2*time.Hour + 58*time.Minute), time.Time{}, nil, []string{
+			"info 12:58", "info 12:59",
+		}},
+		{"levels", start.Add(2 * time.Hour), start.Add(2*time.Hour + 2*time.Minute), logreader.Levels(log.InfoLevel), []string{
+			"info 12:01", "info 12:02",
+		}},
+		{"empty", start.Add(5 * time.Hour), time.Time{}, nil, []string{}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			entries, err := logreader.Query(dir, tc.from, tc.to, tc.filter)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, messages(entries))
+		})
+	}
+
+}
+
+func Test_Query_Entry(t *testing.T) {
+
+	dir, _ := ioutil.TempDir("", "go-log")
+	defer os.RemoveAll(dir)
+
+	writeLogs(t, dir)
+
+	entries, err := logreader.Query(dir, start.Add(2*time.Hour), start.Add(2*time.Hour), nil)
+	assert.NoError(t, err, "query")
+
+	if assert.Len(t, entries, 1, "entries") {
+		assert.True(t, start.Add(2*time.Hour).Equal(entries[0].Time), "time")
+		assert.Equal(t, log.ErrorLevel, entries[0].Level, "level")
+		assert.Equal(t, filepath.Join(dir, "app.log"), entries[0].File, "file")
+		assert.Equal(t, strings.TrimSuffix(textLine(start.Add(2*time.Hour), "ERROR", "error 12:00"), "\n")+"\nstack trace", entries[0].Line, "line")
+	}
+
+}
+
+func Test_Query_Formats(t *testing.T) {
+
+	dir, _ := ioutil.TempDir("", "go-log")
+	defer os.RemoveAll(dir)
+
+	lines := `{"message":"datadog","status":"warn","timestamp":"2020-03-01T10:00:00Z"}` + "\n" +
+		`level=error ts=2020-03-01T10:01:00Z msg="log fmt" user=jane` + "\n" +
+		"<4>" + textLine(start.Add(2*time.Minute), "WARN", "journald")
+	ioutil.WriteFile(filepath.Join(dir, "app.log"), []byte(lines), 0644)
+
+	entries, err := logreader.Query(dir, start, time.Time{}, logreader.MinLevel(log.WarnLevel))
+	assert.NoError(t, err, "query")
+	assert.Equal(t, []string{"datadog", "log fmt", "journald"}, messages(entries), "messages")
+
+}
+
+func Test_Query_MissingDir(t *testing.T) {
+	_, err := logreader.Query(filepath.Join(os.TempDir(), "go-log-missing"), time.Time{}, time.Time{}, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,170 @@
+package logreader
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+// datadogLevels maps the Datadog statuses back to the levels
+var datadogLevels = map[string]log.Level{
+	"debug":    log.DebugLevel,
+	"info":     log.InfoLevel,
+	"notice":   log.NoticeLevel,
+	"warn":     log.WarnLevel,
+	"error":    log.ErrorLevel,
+	"critical": log.FatalLevel,
+}
+
+// parseLine parses an entry formatted as text, logfmt or Datadog JSON
+func parseLine(line string) (Entry, bool) {
+
+	line = stripPriority(line)
+
+	switch {
+	case strings.HasPrefix(line, "{"):
+		return parseDatadog(line)
+	case strings.HasPrefix(line, "level="):
+		return parseLogfmt(line)
+	default:
+		return parseText(line)
+	}
+
+}
+
+// stripPriority removes the syslog priority prefix written for journald (e.g. "<6>")
+func stripPriority(line string) string {
+	if len(line) >= 3 && line[0] == '<' && line[1] >= '0' && line[1] <= '7' && line[2] == '>' {
+		return line[3:]
+	}
+	return line
+}
+
+func parseText(line string) (Entry, bool) {
+
+	parts := strings.SplitN(line, " | ", 3)
+	if len(parts) < 3 {
+		return Entry{}, false
+	}
+
+	zone := log.TimeZone
+	if zone == nil {
+		zone = time.Local
+	}
+
+	t, err := time.ParseInLocation(log.TimeFormat, parts[0], zone)
+	if err != nil {
+		return Entry{}, false
+	}
+
+	level, err := log.ParseLevel(parts[1])
+	if err != nil {
+		return Entry{}, false
+	}
+
+	return Entry{Time: t, Level: level, Message: parts[2], Line: line}, true
+
+}
+
+func parseDatadog(line string) (Entry, bool) {
+
+	var attributes struct {
+		Timestamp string `json:"timestamp"`
+		Status    string `json:"status"`
+		Message   string `json:"message"`
+	}
+	if err := json.Unmarshal([]byte(line), &attributes); err != nil {
+		return Entry{}, false
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, attributes.Timestamp)
+	if err != nil {
+		return Entry{}, false
+	}
+
+	level, ok := datadogLevels[attributes.Status]
+	if !ok {
+		return Entry{}, false
+	}
+
+	return Entry{Time: t, Level: level, Message: attributes.Message, Line: line}, true
+
+}
+
+func parseLogfmt(line string) (Entry, bool) {
+
+	values := parseLogfmtPairs(line)
+
+	t, err := time.Parse(time.RFC3339Nano, values["ts"])
+	if err != nil {
+		return Entry{}, false
+	}
+
+	level, err := log.ParseLevel(values["level"])
+	if err != nil {
+		return Entry{}, false
+	}
+
+	return Entry{Time: t, Level: level, Message: values["msg"], Line: line}, true
+
+}
+
+// parseLogfmtPairs returns the key/value pairs of a logfmt line, a key which occurs more than once keeps its first
+// value
+func parseLogfmtPairs(line string) map[string]string {
+
+	values := map[string]string{}
+
+	for line != "" {
+
+		line = strings.TrimLeft(line, " ")
+
+		eq := strings.IndexByte(line, '=')
+		if eq <= 0 {
+			break
+		}
+		key := line[:eq]
+		line = line[eq+1:]
+
+		var value string
+		if strings.HasPrefix(line, `"`) {
+			end := closingQuote(line)
+			if end < 0 {
+				break
+			}
+			unquoted, err := strconv.Unquote(line[:end+1])
+			if err != nil {
+				break
+			}
+			value, line = unquoted, line[end+1:]
+		} else if space := strings.IndexByte(line, ' '); space >= 0 {
+			value, line = line[:space], line[space:]
+		} else {
+			value, line = line, ""
+		}
+
+		if _, ok := values[key]; !ok {
+			values[key] = value
+		}
+
+	}
+
+	return values
+
+}
+
+// closingQuote returns the index of the quote closing the quoted string at the start of s
+func closingQuote(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
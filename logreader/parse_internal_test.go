@@ -0,0 +1,67 @@
+package logreader
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func Test_parseLine(t *testing.T) {
+
+	ts := time.Date(2020, 3, 1, 10, 0, 0, 0, time.UTC)
+	text := ts.In(log.TimeZone).Format(log.TimeFormat)
+
+	var tests = []struct {
+		name            string
+		line            string
+		expectedOK      bool
+		expectedLevel   log.Level
+		expectedMessage string
+	}{
+		{"text", text + " | INFO  | message | with pipes", true, log.InfoLevel, "message | with pipes"},
+		{"text-priority", "<3>" + text + " | ERROR | failed", true, log.ErrorLevel, "failed"},
+		{"text-no-timestamp", "message", false, log.InfoLevel, ""},
+		{"text-invalid-level", text + " | OOPS  | message", false, log.InfoLevel, ""},
+		{"datadog", `{"message":"m","status":"critical","timestamp":"2020-03-01T10:00:00Z"}`, true, log.FatalLevel, "m"},
+		{"datadog-no-timestamp", `{"message":"m","status":"info"}`, false, log.InfoLevel, ""},
+		{"datadog-invalid", `{"message":`, false, log.InfoLevel, ""},
+		{"logfmt", `level=notice ts=2020-03-01T10:00:00Z msg="a \"quoted\" msg" k=v`, true, log.NoticeLevel, `a "quoted" msg`},
+		{"logfmt-no-timestamp", `level=info msg=m`, false, log.InfoLevel, ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			e, ok := parseLine(tc.line)
+			assert.Equal(t, tc.expectedOK, ok, "ok")
+			if tc.expectedOK {
+				assert.True(t, ts.Equal(e.Time), "time")
+				assert.Equal(t, tc.expectedLevel, e.Level, "level")
+				assert.Equal(t, tc.expectedMessage, e.Message, "message")
+			}
+		})
+	}
+
+}
+
+func Test_parseLogfmtPairs(t *testing.T) {
+
+	var tests = []struct {
+		line     string
+		expected map[string]string
+	}{
+		{`a=1 b="two words" c=`, map[string]string{"a": "1", "b": "two words", "c": ""}},
+		{`a=1 a=2`, map[string]string{"a": "1"}},
+		{`a="unterminated`, map[string]string{}},
+		{`novalue`, map[string]string{}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.line, func(t *testing.T) {
+			assert.Equal(t, tc.expected, parseLogfmtPairs(tc.line))
+		})
+	}
+
+}
@@ -0,0 +1,112 @@
+package logtest
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// ErrChaos is the error returned by the writes which a ChaosSink fails
+var ErrChaos = errors.New("chaos: injected write failure")
+
+// ChaosOptions define how often a ChaosSink injects each kind of failure
+//
+// The rates are probabilities between 0 and 1 which are checked for each write. A write is either failed, partially
+// written or written completely, the delay is injected independently before it.
+type ChaosOptions struct {
+	// Seed makes the sequence of failures reproducible, the same seed injects the same failures for the same writes
+	Seed int64
+
+	// FailureRate is the probability that a write fails with ErrChaos without writing anything
+	FailureRate float64
+
+	// PartialRate is the probability that only a part of the data is written, the write then fails with
+	// io.ErrShortWrite
+	PartialRate float64
+
+	// DelayRate is the probability that a write is delayed by up to MaxDelay
+	DelayRate float64
+
+	// MaxDelay is the longest delay which is injected (defaults to 10ms)
+	MaxDelay time.Duration
+}
+
+// ChaosStats are the number of writes and of injected failures of a ChaosSink
+type ChaosStats struct {
+	Writes   int
+	Failures int
+	Partial  int
+	Delayed  int
+}
+
+// ChaosSink is a writer which randomly fails, delays or partially performs the writes to the wrapped writer, so that
+// the retries, fallbacks and dropped entries of the outputs can be exercised in tests
+//
+//	sink := logtest.NewChaosSink(&buf, logtest.ChaosOptions{Seed: 1, FailureRate: 0.2})
+//	log.Stdout = sink
+type ChaosSink struct {
+	w     io.Writer
+	opts  ChaosOptions
+	mutex sync.Mutex
+	rand  *rand.Rand
+	stats ChaosStats
+}
+
+// NewChaosSink creates a ChaosSink which writes to w (discards the data when nil)
+func NewChaosSink(w io.Writer, opts ChaosOptions) *ChaosSink {
+
+	if w == nil {
+		w = ioutil.Discard
+	}
+	if opts.MaxDelay <= 0 {
+		opts.MaxDelay = 10 * time.Millisecond
+	}
+
+	return &ChaosSink{w: w, opts: opts, rand: rand.New(rand.NewSource(opts.Seed))}
+
+}
+
+// Write writes p to the wrapped writer unless a failure is injected
+func (s *ChaosSink) Write(p []byte) (int, error) {
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// The same number of values is drawn for each write, so that the failures only depend on the seed and on the
+	// number of writes and not on the rates
+	delayRoll, delayFraction := s.rand.Float64(), s.rand.Float64()
+	outcomeRoll, partialFraction := s.rand.Float64(), s.rand.Float64()
+
+	s.stats.Writes++
+
+	if delayRoll < s.opts.DelayRate {
+		s.stats.Delayed++
+		time.Sleep(time.Duration(delayFraction * float64(s.opts.MaxDelay)))
+	}
+
+	switch {
+	case outcomeRoll < s.opts.FailureRate:
+		s.stats.Failures++
+		return 0, ErrChaos
+	case outcomeRoll < s.opts.FailureRate+s.opts.PartialRate && len(p) > 1:
+		s.stats.Partial++
+		n, err := s.w.Write(p[:1+int(partialFraction*float64(len(p)-1))])
+		if err == nil {
+			err = io.ErrShortWrite
+		}
+		return n, err
+	default:
+		return s.w.Write(p)
+	}
+
+}
+
+// Stats returns the number of writes and of injected failures so far
+func (s *ChaosSink) Stats() ChaosStats {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.stats
+}
@@ -0,0 +1,96 @@
+package logtest_test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+	"github.com/pieterclaerhout/go-log/logtest"
+)
+
+func Test_ChaosSink(t *testing.T) {
+
+	type test struct {
+		name     string
+		opts     logtest.ChaosOptions
+		expected logtest.ChaosStats
+		err      error
+		written  string
+	}
+
+	var tests = []test{
+		{"none", logtest.ChaosOptions{}, logtest.ChaosStats{Writes: 1}, nil, "hello\n"},
+		{"failure", logtest.ChaosOptions{FailureRate: 1}, logtest.ChaosStats{Writes: 1, Failures: 1}, logtest.ErrChaos, ""},
+		{"partial", logtest.ChaosOptions{PartialRate: 1}, logtest.ChaosStats{Writes: 1, Partial: 1}, io.ErrShortWrite, ""},
+		{"delay", logtest.ChaosOptions{DelayRate: 1, MaxDelay: time.Millisecond}, logtest.ChaosStats{Writes: 1, Delayed: 1}, nil, "hello\n"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			buf := &bytes.Buffer{}
+			sink := logtest.NewChaosSink(buf, tc.opts)
+
+			n, err := sink.Write([]byte("hello\n"))
+			assert.Equal(t, tc.err, err, "err")
+			assert.Equal(t, buf.Len(), n, "n")
+			assert.Equal(t, tc.expected, sink.Stats(), "stats")
+
+			if tc.name == "partial" {
+				assert.True(t, n > 0 && n < len("hello\n"), "partial-length")
+				assert.True(t, strings.HasPrefix("hello\n", buf.String()), "partial-prefix")
+			} else {
+				assert.Equal(t, tc.written, buf.String(), "written")
+			}
+
+		})
+	}
+
+}
+
+func Test_ChaosSink_Seed(t *testing.T) {
+
+	run := func(seed int64) (string, logtest.ChaosStats) {
+		buf := &bytes.Buffer{}
+		sink := logtest.NewChaosSink(buf, logtest.ChaosOptions{Seed: seed, FailureRate: 0.3, PartialRate: 0.3})
+		for i := 0; i < 100; i++ {
+			sink.Write([]byte("entry\n"))
+		}
+		return buf.String(), sink.Stats()
+	}
+
+	first, firstStats := run(42)
+	second, secondStats := run(42)
+	other, otherStats := run(7)
+
+	assert.Equal(t, first, second, "same-seed-output")
+	assert.Equal(t, firstStats, secondStats, "same-seed-stats")
+	assert.True(t, first != other || firstStats != otherStats, "other-seed")
+
+	assert.Equal(t, 100, firstStats.Writes, "writes")
+	assert.True(t, firstStats.Failures > 0 && firstStats.Partial > 0, "injected")
+
+}
+
+func Test_ChaosSink_DeadLetter(t *testing.T) {
+
+	deadLetter := &bytes.Buffer{}
+
+	log.Stdout = logtest.NewChaosSink(nil, logtest.ChaosOptions{FailureRate: 1})
+	log.DeadLetter = deadLetter
+	defer func() {
+		log.Stdout = os.Stdout
+		log.DeadLetter = nil
+	}()
+
+	log.Info("lost")
+
+	assert.Contains(t, deadLetter.String(), `"message":"lost"`, "dead-letter")
+
+}
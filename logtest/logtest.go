@@ -0,0 +1,309 @@
+// Package logtest contains helpers for testing code which uses github.com/pieterclaerhout/go-log
+package logtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+// ValidateJSON validates each line of b against the JSON Schema of the structured output it was written as
+//
+// The output is detected from the keys of each line: events, metrics, scrub reports, Datadog entries (which have a
+// "status") and recorded entries (which have a "level"). Empty lines are skipped.
+func ValidateJSON(b []byte) error {
+
+	for i, line := range bytes.Split(b, []byte("\n")) {
+
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+
+		value, err := decode(line)
+		if err != nil {
+			return fmt.Errorf("line %d: %v", i+1, err)
+		}
+
+		schema, err := schemaFor(value)
+		if err != nil {
+			return fmt.Errorf("line %d: %v", i+1, err)
+		}
+
+		if err := validateValue(value, schema); err != nil {
+			return fmt.Errorf("line %d: %v", i+1, err)
+		}
+
+	}
+
+	return nil
+
+}
+
+// ValidateJSONSchema validates each line of b against the JSON Schema (such as log.EventSchema)
+//
+// Only the keywords used by the schemas of this package are supported: type, required, properties,
+// additionalProperties, items, enum, const, minimum and the date-time format.
+func ValidateJSONSchema(b []byte, schema string) error {
+
+	parsed, err := parseSchema(schema)
+	if err != nil {
+		return err
+	}
+
+	for i, line := range bytes.Split(b, []byte("\n")) {
+
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+
+		value, err := decode(line)
+		if err != nil {
+			return fmt.Errorf("line %d: %v", i+1, err)
+		}
+
+		if err := validate("", value, parsed); err != nil {
+			return fmt.Errorf("line %d: %v", i+1, err)
+		}
+
+	}
+
+	return nil
+
+}
+
+func schemaFor(value interface{}) (string, error) {
+
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return "", fmt.Errorf("expected a JSON object")
+	}
+
+	has := func(key string) bool {
+		_, ok := object[key]
+		return ok
+	}
+
+	switch {
+	case has("event"):
+		return log.EventSchema, nil
+	case has("metric"):
+		return log.MetricSchema, nil
+	case has("rule") && has("matches"):
+		return log.ScrubReportSchema, nil
+	case has("status"):
+		return log.DatadogSchema, nil
+	case has("level"):
+		return log.RecordedEntrySchema, nil
+	default:
+		return "", fmt.Errorf("unknown structured output")
+	}
+
+}
+
+func validateValue(value interface{}, schema string) error {
+	parsed, err := parseSchema(schema)
+	if err != nil {
+		return err
+	}
+	return validate("", value, parsed)
+}
+
+func parseSchema(schema string) (map[string]interface{}, error) {
+	value, err := decode([]byte(schema))
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema: %v", err)
+	}
+	parsed, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid schema: expected a JSON object")
+	}
+	return parsed, nil
+}
+
+func decode(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func validate(path string, value interface{}, schema map[string]interface{}) error {
+
+	if types, ok := schema["type"]; ok && !matchesType(value, types) {
+		return fmt.Errorf("%s: expected type %v, got %s", pathName(path), types, typeName(value))
+	}
+
+	if expected, ok := schema["const"]; ok && !equal(value, expected) {
+		return fmt.Errorf("%s: expected %v, got %v", pathName(path), expected, value)
+	}
+
+	if values, ok := schema["enum"].([]interface{}); ok {
+		found := false
+		for _, expected := range values {
+			if equal(value, expected) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%s: %v is not one of %v", pathName(path), value, values)
+		}
+	}
+
+	if minimum, ok := schema["minimum"].(json.Number); ok {
+		if number, ok := value.(json.Number); ok {
+			min, _ := minimum.Float64()
+			actual, _ := number.Float64()
+			if actual < min {
+				return fmt.Errorf("%s: %v is less than %v", pathName(path), number, minimum)
+			}
+		}
+	}
+
+	if schema["format"] == "date-time" {
+		if s, ok := value.(string); ok {
+			if _, err := time.Parse(time.RFC3339Nano, s); err != nil {
+				return fmt.Errorf("%s: %q is not a date-time", pathName(path), s)
+			}
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return validateObject(path, v, schema)
+	case []interface{}:
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				if err := validate(fmt.Sprintf("%s[%d]", path, i), item, items); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+
+}
+
+func validateObject(path string, object map[string]interface{}, schema map[string]interface{}) error {
+
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, key := range required {
+			if _, ok := object[fmt.Sprint(key)]; !ok {
+				return fmt.Errorf("%s: missing required key %q", pathName(path), key)
+			}
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		child := strings.TrimPrefix(path+"."+key, ".")
+		if property, ok := properties[key].(map[string]interface{}); ok {
+			if err := validate(child, object[key], property); err != nil {
+				return err
+			}
+			continue
+		}
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				return fmt.Errorf("%s: unexpected key %q", pathName(path), key)
+			}
+		case map[string]interface{}:
+			if err := validate(child, object[key], additional); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+
+}
+
+func matchesType(value interface{}, types interface{}) bool {
+	switch t := types.(type) {
+	case string:
+		return isType(value, t)
+	case []interface{}:
+		for _, name := range t {
+			if isType(value, fmt.Sprint(name)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isType(value interface{}, name string) bool {
+	switch name {
+	case "integer":
+		number, ok := value.(json.Number)
+		if !ok {
+			return false
+		}
+		_, err := number.Int64()
+		return err == nil
+	case "number":
+		_, ok := value.(json.Number)
+		return ok
+	default:
+		return typeName(value) == name
+	}
+}
+
+func typeName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number:
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func equal(a interface{}, b interface{}) bool {
+	an, aok := a.(json.Number)
+	bn, bok := b.(json.Number)
+	if aok && bok {
+		af, _ := an.Float64()
+		bf, _ := bn.Float64()
+		return af == bf
+	}
+	if aok || bok {
+		return false
+	}
+	return fmt.Sprintf("%#v", a) == fmt.Sprintf("%#v", b)
+}
+
+func pathName(path string) string {
+	if path == "" {
+		return "root"
+	}
+	return path
+}
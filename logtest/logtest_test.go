@@ -0,0 +1,158 @@
+package logtest_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+	"github.com/pieterclaerhout/go-log/logtest"
+)
+
+func capture(write func()) []byte {
+
+	stdout := bytes.NewBufferString("")
+	log.Stdout = stdout
+	log.Stderr = stdout
+	defer func() {
+		log.Stdout = os.Stdout
+		log.Stderr = os.Stderr
+		log.Format = log.FormatText
+		log.ScrubRules = []log.ScrubRule{}
+		log.ScrubDryRun = false
+		log.ScrubReport = nil
+	}()
+
+	write()
+
+	return stdout.Bytes()
+
+}
+
+func Test_ValidateJSON(t *testing.T) {
+
+	type test struct {
+		name  string
+		write func()
+	}
+
+	var tests = []test{
+		{"datadog", func() {
+			log.Format = log.FormatDatadog
+			log.Info("info")
+			log.Error("error")
+		}},
+		{"event", func() {
+			log.Event("signup", map[string]interface{}{"plan": "pro"})
+		}},
+		{"metric", func() {
+			log.Metric("queue.depth", 3, map[string]string{"queue": "emails"})
+			log.MetricCount("emails.sent", 1, nil)
+		}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			output := capture(tc.write)
+			assert.NotEmpty(t, output, "output")
+			assert.NoError(t, logtest.ValidateJSON(output), "validate")
+		})
+	}
+
+}
+
+func Test_ValidateJSON_ScrubReport(t *testing.T) {
+
+	report := bytes.NewBufferString("")
+
+	capture(func() {
+		log.ScrubRules = []log.ScrubRule{{Name: "digits", Pattern: regexp.MustCompile(`\d+`)}}
+		log.ScrubDryRun = true
+		log.ScrubReport = report
+		log.Info("order 123")
+	})
+
+	assert.NotEmpty(t, report.Bytes(), "report")
+	assert.NoError(t, logtest.ValidateJSON(report.Bytes()), "validate")
+
+}
+
+func Test_ValidateJSON_Recording(t *testing.T) {
+
+	dir, _ := ioutil.TempDir("", "go-log")
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "recording.jsonl.gz")
+
+	capture(func() {
+		assert.NoError(t, log.StartRecording(path), "start")
+		log.WithField("key", "value").Info("recorded")
+		log.Warn("warning")
+		assert.NoError(t, log.StopRecording(), "stop")
+	})
+
+	f, err := os.Open(path)
+	assert.NoError(t, err, "open")
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	assert.NoError(t, err, "gzip")
+
+	output, err := ioutil.ReadAll(gz)
+	assert.NoError(t, err, "read")
+	assert.NotEmpty(t, output, "output")
+	assert.NoError(t, logtest.ValidateJSON(output), "validate")
+
+}
+
+func Test_ValidateJSON_Invalid(t *testing.T) {
+
+	type test struct {
+		name     string
+		input    string
+		expected string
+	}
+
+	var tests = []test{
+		{"not-json", `{"event":`, "line 1: unexpected EOF"},
+		{"not-object", `[1, 2]`, "line 1: expected a JSON object"},
+		{"unknown", `{"hello":"world"}`, "line 1: unknown structured output"},
+		{"missing-key", `{"time":"2019-01-01T12:00:00Z","event":"signup","schema_version":1}`, `line 1: root: missing required key "properties"`},
+		{"unexpected-key", `{"time":"2019-01-01T12:00:00Z","event":"signup","properties":{},"schema_version":1,"extra":true}`, `line 1: root: unexpected key "extra"`},
+		{"wrong-type", `{"time":"2019-01-01T12:00:00Z","metric":"m","value":"1","type":"gauge","tags":[],"schema_version":1}`, "line 1: value: expected type number, got string"},
+		{"wrong-enum", `{"time":"2019-01-01T12:00:00Z","metric":"m","value":1,"type":"histogram","tags":[],"schema_version":1}`, "line 1: type: histogram is not one of [gauge count]"},
+		{"wrong-item", `{"time":"2019-01-01T12:00:00Z","metric":"m","value":1,"type":"gauge","tags":[1],"schema_version":1}`, "line 1: tags[0]: expected type string, got number"},
+		{"wrong-version", `{"status":"info","message":"m","schema_version":2}`, "line 1: schema_version: expected 1, got 2"},
+		{"wrong-time", `{"time":"yesterday","level":"INFO","message":"m","schema_version":1}`, `line 1: time: "yesterday" is not a date-time`},
+		{"wrong-minimum", `{"time":"2019-01-01T12:00:00Z","rule":"r","level":"INFO","caller":"","matches":0,"schema_version":1}`, "line 1: matches: 0 is less than 1"},
+		{"second-line", `{"status":"info","message":"m","schema_version":1}` + "\n\n" + `{"status":"info","schema_version":1}`, `line 3: root: missing required key "message"`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := logtest.ValidateJSON([]byte(tc.input))
+			if assert.Error(t, err, "error") {
+				assert.Equal(t, tc.expected, err.Error(), "message")
+			}
+		})
+	}
+
+}
+
+func Test_ValidateJSONSchema(t *testing.T) {
+
+	output := capture(func() {
+		log.Event("signup", nil)
+	})
+
+	assert.NoError(t, logtest.ValidateJSONSchema(output, log.EventSchema), "event")
+	assert.Error(t, logtest.ValidateJSONSchema(output, log.MetricSchema), "metric")
+	assert.Error(t, logtest.ValidateJSONSchema(output, "not a schema"), "invalid-schema")
+
+}
@@ -0,0 +1,152 @@
+package logtest
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+// SoakOptions define the load which Soak drives through the async pipeline
+type SoakOptions struct {
+	// Goroutines is the number of goroutines logging concurrently (defaults to 8)
+	Goroutines int
+
+	// Messages is the number of entries logged by each goroutine (defaults to 1000)
+	Messages int
+
+	// MessageSize is the number of bytes of the message of each entry (defaults to 128)
+	MessageSize int
+
+	// Async are the options of the AsyncWriter under test, such as its BufferSize and Overflow policy
+	Async log.AsyncOptions
+
+	// Sink receives the entries written by the AsyncWriter (defaults to discarding them), a ChaosSink with delays
+	// simulates a slow output
+	Sink io.Writer
+}
+
+// SoakResult is the outcome of a Soak run
+type SoakResult struct {
+	// Entries is the number of entries which were logged
+	Entries int
+
+	// Dropped is the number of entries dropped because the buffer was full
+	Dropped uint64
+
+	// Duration is the time it took to log the entries and to write all of them to the Sink
+	Duration time.Duration
+
+	// Throughput is the number of entries per second over the Duration
+	Throughput float64
+
+	// The percentiles of the latency of the logging calls, the time the callers are blocked by the pipeline
+	P50 time.Duration
+	P90 time.Duration
+	P99 time.Duration
+	Max time.Duration
+}
+
+// String returns a summary of the result
+func (r SoakResult) String() string {
+	return fmt.Sprintf(
+		"%d entries in %s (%.0f/s), %d dropped, latency p50=%s p90=%s p99=%s max=%s",
+		r.Entries, r.Duration, r.Throughput, r.Dropped, r.P50, r.P90, r.P99, r.Max,
+	)
+}
+
+// Soak logs entries from concurrent goroutines through an AsyncWriter created with opts.Async and reports the
+// throughput, the latencies and the number of dropped entries, which helps to size the buffer for a workload
+//
+// Stdout and Stderr are replaced by the AsyncWriter while running, so nothing else should log at the same time. The
+// error is the first error returned by the Sink.
+func Soak(opts SoakOptions) (SoakResult, error) {
+
+	if opts.Goroutines <= 0 {
+		opts.Goroutines = 8
+	}
+	if opts.Messages <= 0 {
+		opts.Messages = 1000
+	}
+	if opts.MessageSize <= 0 {
+		opts.MessageSize = 128
+	}
+	if opts.Sink == nil {
+		opts.Sink = ioutil.Discard
+	}
+
+	message := strings.Repeat("x", opts.MessageSize)
+
+	async := log.NewAsyncWriter(opts.Sink, opts.Async)
+
+	stdout, stderr := log.Stdout, log.Stderr
+	log.Stdout, log.Stderr = async, async
+	defer func() {
+		log.Stdout, log.Stderr = stdout, stderr
+	}()
+
+	latencies := make([][]time.Duration, opts.Goroutines)
+
+	start := time.Now()
+
+	var wg sync.WaitGroup
+	for i := 0; i < opts.Goroutines; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			measured := make([]time.Duration, opts.Messages)
+			for j := range measured {
+				callStart := time.Now()
+				log.Info(message)
+				measured[j] = time.Since(callStart)
+			}
+			latencies[i] = measured
+		}(i)
+	}
+	wg.Wait()
+
+	err := async.Close()
+	duration := time.Since(start)
+
+	all := make([]time.Duration, 0, opts.Goroutines*opts.Messages)
+	for _, measured := range latencies {
+		all = append(all, measured...)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		return all[i] < all[j]
+	})
+
+	result := SoakResult{
+		Entries:  len(all),
+		Dropped:  async.Dropped(),
+		Duration: duration,
+		P50:      percentile(all, 0.50),
+		P90:      percentile(all, 0.90),
+		P99:      percentile(all, 0.99),
+		Max:      percentile(all, 1),
+	}
+	if duration > 0 {
+		result.Throughput = float64(result.Entries) / duration.Seconds()
+	}
+
+	return result, err
+
+}
+
+// percentile returns the nearest-rank percentile of the sorted durations
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
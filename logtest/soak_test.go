@@ -0,0 +1,52 @@
+package logtest_test
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+	"github.com/pieterclaerhout/go-log/logtest"
+)
+
+func Test_Soak(t *testing.T) {
+
+	sink := &bytes.Buffer{}
+
+	result, err := logtest.Soak(logtest.SoakOptions{Goroutines: 4, Messages: 50, MessageSize: 10, Sink: sink})
+	assert.NoError(t, err, "err")
+
+	assert.Equal(t, os.Stdout, log.Stdout, "stdout-restored")
+	assert.Equal(t, os.Stderr, log.Stderr, "stderr-restored")
+
+	assert.Equal(t, 200, result.Entries, "entries")
+	assert.Equal(t, uint64(0), result.Dropped, "dropped")
+	assert.Equal(t, 200, strings.Count(sink.String(), strings.Repeat("x", 10)+"\n"), "written")
+	assert.True(t, result.Throughput > 0, "throughput")
+	assert.True(t, result.P50 <= result.P90 && result.P90 <= result.P99 && result.P99 <= result.Max, "percentiles")
+	assert.Contains(t, result.String(), "200 entries in ", "string")
+
+}
+
+func Test_Soak_Dropped(t *testing.T) {
+
+	sink := &bytes.Buffer{}
+	slow := logtest.NewChaosSink(sink, logtest.ChaosOptions{DelayRate: 1, MaxDelay: time.Millisecond})
+
+	result, err := logtest.Soak(logtest.SoakOptions{
+		Goroutines: 2,
+		Messages:   50,
+		Async:      log.AsyncOptions{BufferSize: 1, Overflow: log.OverflowDrop},
+		Sink:       slow,
+	})
+	assert.NoError(t, err, "err")
+
+	assert.Equal(t, 100, result.Entries, "entries")
+	assert.True(t, result.Dropped > 0, "dropped")
+	assert.Equal(t, 100-int(result.Dropped), strings.Count(sink.String(), "\n"), "written")
+
+}
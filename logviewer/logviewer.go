@@ -0,0 +1,454 @@
+// Package logviewer is a small terminal viewer for the entries logged with github.com/pieterclaerhout/go-log
+//
+// It shows the most recent entries and reads commands line by line, so that it works in any terminal without
+// switching it to raw mode:
+//
+//	p           pause or resume the updates
+//	l <level>   only show the entries of the level or above ("l" shows all levels)
+//	/<text>     only show the entries containing the text ("/" clears the search)
+//	q           quit
+package logviewer
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+// tailSize is the number of bytes at the end of the files which are shown when starting to tail them
+const tailSize = 64 * 1024
+
+const (
+	clearScreen = "\x1b[H\x1b[2J"
+	reverse     = "\x1b[7m"
+	reset       = "\x1b[0m"
+)
+
+// Options are the options of a Viewer
+type Options struct {
+	// In is where the commands are read from (defaults to os.Stdin)
+	In io.Reader
+
+	// Out is where the viewer is drawn (defaults to os.Stdout)
+	Out io.Writer
+
+	// Height is the number of entry lines shown (defaults to 20)
+	Height int
+
+	// MaxLines is the number of lines kept (defaults to 10000)
+	MaxLines int
+
+	// Backlog is shown before the entries logged after starting the viewer (e.g. log.RecentLogs)
+	Backlog *log.MemorySink
+
+	// Files are tailed next to the entries logged by the process
+	Files []string
+
+	// PollInterval is the interval at which the Files are checked for new lines (defaults to 250ms)
+	PollInterval time.Duration
+}
+
+type line struct {
+	level    log.Level
+	hasLevel bool
+	text     string
+}
+
+// Viewer shows the entries written to it, New adds it as an output of the logger
+type Viewer struct {
+	mutex    sync.Mutex
+	opts     Options
+	output   io.Writer
+	lines    []line
+	minLevel log.Level
+	search   string
+	paused   bool
+	pending  int
+	status   string
+	partial  string
+	changed  chan struct{}
+}
+
+// New returns a Viewer which receives every entry logged from now on, call Run to show it
+//
+// When the viewer is drawn on the terminal, Stdout and Stderr should be redirected (e.g. to a file) so that the
+// entries aren't written over it.
+func New(opts Options) *Viewer {
+
+	if opts.In == nil {
+		opts.In = os.Stdin
+	}
+	if opts.Out == nil {
+		opts.Out = os.Stdout
+	}
+	if opts.Height <= 0 {
+		opts.Height = 20
+	}
+	if opts.MaxLines <= 0 {
+		opts.MaxLines = 10000
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = 250 * time.Millisecond
+	}
+
+	v := &Viewer{opts: opts, minLevel: log.TraceLevel, changed: make(chan struct{}, 1)}
+
+	if opts.Backlog != nil {
+		for _, entry := range opts.Backlog.Entries() {
+			v.Write([]byte(entry))
+		}
+	}
+
+	v.output = log.WithTimestamp(v, true)
+	log.AddOutput(v.output, log.OutputOptions{Name: "viewer", Format: log.FormatText})
+
+	return v
+
+}
+
+// Write adds the lines of p to the viewer, a line which isn't complete is kept until the rest of it is written
+func (v *Viewer) Write(p []byte) (int, error) {
+
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	v.addData(&v.partial, p)
+
+	return len(p), nil
+
+}
+
+// addData adds the complete lines of the data following the partial line and signals the change
+func (v *Viewer) addData(partial *string, p []byte) {
+
+	data := *partial + string(p)
+	complete := strings.LastIndexByte(data, '\n') + 1
+	*partial = data[complete:]
+
+	if complete > 0 {
+		for _, text := range strings.Split(data[:complete-1], "\n") {
+			v.addLine(strings.TrimSuffix(text, "\r"))
+		}
+		select {
+		case v.changed <- struct{}{}:
+		default:
+		}
+	}
+
+}
+
+// addLine adds a line, lines without a level (such as stack traces) get the level of the previous line
+func (v *Viewer) addLine(text string) {
+
+	l := line{text: text}
+	l.level, l.hasLevel = levelOfLine(text)
+	if !l.hasLevel && len(v.lines) > 0 {
+		l.level = v.lines[len(v.lines)-1].level
+	}
+
+	v.lines = append(v.lines, l)
+	if len(v.lines) > v.opts.MaxLines {
+		v.lines = v.lines[len(v.lines)-v.opts.MaxLines:]
+	}
+
+	if v.paused {
+		v.pending++
+	}
+
+}
+
+// Run shows the viewer until the "q" command is given or the input is closed, it then stops receiving the entries
+func (v *Viewer) Run() error {
+
+	defer log.RemoveOutput(v.output)
+
+	done := make(chan struct{})
+	defer close(done)
+
+	for _, path := range v.opts.Files {
+		go v.tail(path, done)
+	}
+
+	commands := make(chan string)
+	errs := make(chan error, 1)
+	go func() {
+		scanner := bufio.NewScanner(v.opts.In)
+		for scanner.Scan() {
+			select {
+			case commands <- scanner.Text():
+			case <-done:
+				return
+			}
+		}
+		errs <- scanner.Err()
+	}()
+
+	v.render()
+
+	for {
+		select {
+		case command := <-commands:
+			if !v.execute(strings.TrimSpace(command)) {
+				return nil
+			}
+			v.render()
+		case err := <-errs:
+			return err
+		case <-v.changed:
+			if !v.isPaused() {
+				v.render()
+			}
+		}
+	}
+
+}
+
+// execute runs a command and returns false when the viewer should quit
+func (v *Viewer) execute(command string) bool {
+
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	v.status = ""
+
+	switch {
+	case command == "q":
+		return false
+	case command == "p":
+		v.paused = !v.paused
+		v.pending = 0
+	case command == "l":
+		v.minLevel = log.TraceLevel
+	case strings.HasPrefix(command, "l "):
+		level, err := log.ParseLevel(strings.TrimSpace(command[2:]))
+		if err != nil {
+			v.status = err.Error()
+		} else {
+			v.minLevel = level
+		}
+	case strings.HasPrefix(command, "/"):
+		v.search = command[1:]
+	case command != "":
+		v.status = fmt.Sprintf("unknown command: %q", command)
+	}
+
+	return true
+
+}
+
+func (v *Viewer) isPaused() bool {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	return v.paused
+}
+
+// render draws the header, the most recent lines matching the filters and the help
+func (v *Viewer) render() {
+
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	var b strings.Builder
+	b.WriteString(clearScreen)
+
+	state := "LIVE"
+	if v.paused {
+		state = fmt.Sprintf("PAUSED (%d new)", v.pending)
+	}
+	fmt.Fprintf(&b, "%s go-log | %s | level >= %s | search: %q %s\n", reverse, state, v.minLevel, v.search, reset)
+
+	// The lines added while paused are only shown after resuming
+	last := len(v.lines) - 1
+	if v.paused {
+		last -= v.pending
+	}
+
+	visible := []string{}
+	for i := last; i >= 0 && len(visible) < v.opts.Height; i-- {
+		l := v.lines[i]
+		if l.level < v.minLevel {
+			continue
+		}
+		if v.search != "" && !strings.Contains(strings.ToLower(l.text), strings.ToLower(v.search)) {
+			continue
+		}
+		visible = append(visible, highlight(l.text, v.search))
+	}
+	for i := len(visible) - 1; i >= 0; i-- {
+		b.WriteString(visible[i] + "\n")
+	}
+	for i := len(visible); i < v.opts.Height; i++ {
+		b.WriteString("\n")
+	}
+
+	if v.status != "" {
+		b.WriteString(v.status + "\n")
+	}
+	b.WriteString("p: pause/resume | l <level>: filter | /<text>: search | q: quit\n> ")
+
+	io.WriteString(v.opts.Out, b.String())
+
+}
+
+// tail adds the lines appended to the file until done is closed, starting with the end of the file and starting
+// over when it is truncated
+func (v *Viewer) tail(path string, done chan struct{}) {
+
+	offset := int64(-1)
+	partial := ""
+
+	ticker := time.NewTicker(v.opts.PollInterval)
+	defer ticker.Stop()
+
+	for {
+
+		if data, size, ok := readFrom(path, offset); ok {
+			if offset < 0 && size > tailSize {
+				// The first line is skipped as it's likely only the end of a line
+				if i := strings.IndexByte(string(data), '\n'); i >= 0 {
+					data = data[i+1:]
+				}
+			}
+			if offset > size {
+				partial = ""
+			}
+			v.mutex.Lock()
+			v.addData(&partial, data)
+			v.mutex.Unlock()
+			offset = size
+		}
+
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+
+	}
+
+}
+
+// readFrom returns the data of the file after offset (the last tailSize bytes when offset is negative) and its size,
+// reading it from the start when it was truncated
+func readFrom(path string, offset int64) ([]byte, int64, bool) {
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, 0, false
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, 0, false
+	}
+
+	size := info.Size()
+	switch {
+	case offset < 0:
+		offset = size - tailSize
+		if offset < 0 {
+			offset = 0
+		}
+	case offset > size:
+		offset = 0
+	case offset == size:
+		return nil, size, true
+	}
+
+	data, err := ioutil.ReadAll(io.NewSectionReader(f, offset, size-offset))
+	if err != nil {
+		return nil, 0, false
+	}
+
+	return data, size, true
+
+}
+
+// highlight shows the occurrences of the search in reverse video
+func highlight(text string, search string) string {
+
+	if search == "" {
+		return text
+	}
+
+	lower := strings.ToLower(text)
+	needle := strings.ToLower(search)
+
+	var b strings.Builder
+	for {
+		i := strings.Index(lower, needle)
+		if i < 0 || len(lower) != len(text) {
+			b.WriteString(text)
+			return b.String()
+		}
+		b.WriteString(text[:i] + reverse + text[i:i+len(needle)] + reset)
+		text, lower = text[i+len(needle):], lower[i+len(needle):]
+	}
+
+}
+
+// levelOfLine returns the level of an entry formatted as text, logfmt or Datadog JSON
+func levelOfLine(text string) (log.Level, bool) {
+
+	text = stripANSI(text)
+
+	if strings.HasPrefix(text, "{") {
+		var attributes struct {
+			Status string `json:"status"`
+		}
+		if json.Unmarshal([]byte(text), &attributes) != nil || attributes.Status == "" {
+			return log.InfoLevel, false
+		}
+		if attributes.Status == "critical" {
+			return log.FatalLevel, true
+		}
+		level, err := log.ParseLevel(attributes.Status)
+		return level, err == nil
+	}
+
+	if strings.HasPrefix(text, "level=") {
+		value := strings.TrimPrefix(text, "level=")
+		if i := strings.IndexByte(value, ' '); i >= 0 {
+			value = value[:i]
+		}
+		level, err := log.ParseLevel(value)
+		return level, err == nil
+	}
+
+	parts := strings.SplitN(text, " | ", 3)
+	if len(parts) < 3 {
+		return log.InfoLevel, false
+	}
+	level, err := log.ParseLevel(parts[1])
+	return level, err == nil
+
+}
+
+// stripANSI removes the escape sequences used to color the entries
+func stripANSI(text string) string {
+	if !strings.Contains(text, "\x1b[") {
+		return text
+	}
+	var b strings.Builder
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\x1b' && i+1 < len(text) && text[i+1] == '[' {
+			j := i + 2
+			for j < len(text) && (text[j] < '@' || text[j] > '~') {
+				j++
+			}
+			i = j
+			continue
+		}
+		b.WriteByte(text[i])
+	}
+	return b.String()
+}
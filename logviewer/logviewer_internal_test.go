@@ -0,0 +1,118 @@
+package logviewer
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+// lastFrame renders the viewer and returns the lines of the entries which are shown
+func lastFrame(v *Viewer) []string {
+	out := &bytes.Buffer{}
+	v.opts.Out = out
+	v.render()
+	lines := strings.Split(strings.TrimPrefix(out.String(), clearScreen), "\n")
+	shown := []string{}
+	for _, l := range lines[1 : 1+v.opts.Height] {
+		if l != "" {
+			shown = append(shown, l)
+		}
+	}
+	return shown
+}
+
+func newTestViewer() *Viewer {
+	v := &Viewer{opts: Options{Height: 3, MaxLines: 100}, changed: make(chan struct{}, 1)}
+	v.Write([]byte("t | INFO  | started\nt | ERROR | failed\nstack trace\nt | DEBUG | details\n"))
+	return v
+}
+
+func Test_Viewer_Commands(t *testing.T) {
+
+	var tests = []struct {
+		name           string
+		commands       []string
+		expected       []string
+		expectedStatus string
+	}{
+		{"all", nil, []string{"t | ERROR | failed", "stack trace", "t | DEBUG | details"}, ""},
+		{"level", []string{"l error"}, []string{"t | ERROR | failed", "stack trace"}, ""},
+		{"level-reset", []string{"l error", "l"}, []string{"t | ERROR | failed", "stack trace", "t | DEBUG | details"}, ""},
+		{"level-invalid", []string{"l loud"}, []string{"t | ERROR | failed", "stack trace", "t | DEBUG | details"}, `unknown level: "loud"`},
+		{"search", []string{"/START"}, []string{"t | INFO  | " + reverse + "start" + reset + "ed"}, ""},
+		{"search-clear", []string{"/start", "/"}, []string{"t | ERROR | failed", "stack trace", "t | DEBUG | details"}, ""},
+		{"unknown", []string{"x"}, []string{"t | ERROR | failed", "stack trace", "t | DEBUG | details"}, `unknown command: "x"`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			v := newTestViewer()
+			for _, command := range tc.commands {
+				assert.True(t, v.execute(command), "continue")
+			}
+			assert.Equal(t, tc.expected, lastFrame(v), "shown")
+			assert.Equal(t, tc.expectedStatus, v.status, "status")
+		})
+	}
+
+	assert.False(t, newTestViewer().execute("q"), "quit")
+
+}
+
+func Test_Viewer_Pause(t *testing.T) {
+
+	v := newTestViewer()
+	v.execute("p")
+
+	v.Write([]byte("t | INFO  | while paused\n"))
+	assert.Equal(t, []string{"t | ERROR | failed", "stack trace", "t | DEBUG | details"}, lastFrame(v), "paused")
+	assert.Equal(t, 1, v.pending, "pending")
+
+	v.execute("p")
+	assert.Equal(t, []string{"stack trace", "t | DEBUG | details", "t | INFO  | while paused"}, lastFrame(v), "resumed")
+
+}
+
+func Test_Viewer_Write(t *testing.T) {
+
+	v := &Viewer{opts: Options{Height: 3, MaxLines: 2}, changed: make(chan struct{}, 1)}
+
+	v.Write([]byte("t | INFO  | one\nt | INFO  | tw"))
+	assert.Len(t, v.lines, 1, "partial")
+
+	v.Write([]byte("o\r\nt | INFO  | three\n"))
+	assert.Equal(t, []string{"t | INFO  | two", "t | INFO  | three"}, lastFrame(v), "max-lines")
+
+}
+
+func Test_levelOfLine(t *testing.T) {
+
+	var tests = []struct {
+		line          string
+		expectedLevel log.Level
+		expectedOK    bool
+	}{
+		{"2020-03-01 10:00:00.000 | WARN  | message", log.WarnLevel, true},
+		{"t | \x1b[31mERROR\x1b[0m | message", log.ErrorLevel, true},
+		{"level=debug ts=2020-03-01T10:00:00Z msg=m", log.DebugLevel, true},
+		{`{"status":"critical","message":"m"}`, log.FatalLevel, true},
+		{`{"status":"notice","message":"m"}`, log.NoticeLevel, true},
+		{`{"message":"m"}`, log.InfoLevel, false},
+		{"stack trace", log.InfoLevel, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.line, func(t *testing.T) {
+			level, ok := levelOfLine(tc.line)
+			assert.Equal(t, tc.expectedOK, ok, "ok")
+			if tc.expectedOK {
+				assert.Equal(t, tc.expectedLevel, level, "level")
+			}
+		})
+	}
+
+}
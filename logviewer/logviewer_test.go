@@ -0,0 +1,118 @@
+package logviewer_test
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+	"github.com/pieterclaerhout/go-log/logviewer"
+)
+
+type syncBuffer struct {
+	mutex sync.Mutex
+	buf   bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buf.String()
+}
+
+func redirectOutput() func() {
+	stdout, stderr := log.Stdout, log.Stderr
+	log.Stdout, log.Stderr = ioutil.Discard, ioutil.Discard
+	return func() {
+		log.Stdout, log.Stderr = stdout, stderr
+	}
+}
+
+func lastFrame(out string) string {
+	frames := strings.Split(out, "\x1b[H\x1b[2J")
+	return frames[len(frames)-1]
+}
+
+func Test_Viewer(t *testing.T) {
+
+	defer redirectOutput()()
+
+	backlog := log.NewMemorySink(1024)
+	backlog.Write([]byte("t | WARN  | from backlog\n"))
+
+	in, commands := io.Pipe()
+	out := &syncBuffer{}
+
+	v := logviewer.New(logviewer.Options{In: in, Out: out, Backlog: backlog})
+
+	log.Info("info entry")
+	log.Error("error entry")
+
+	result := make(chan error, 1)
+	go func() {
+		result <- v.Run()
+	}()
+
+	io.WriteString(commands, "l warn\n")
+	commands.Close()
+
+	assert.NoError(t, <-result, "run")
+
+	frame := lastFrame(out.String())
+	assert.Contains(t, frame, "level >= WARN", "header")
+	assert.Contains(t, frame, "| WARN  | from backlog", "backlog")
+	assert.Contains(t, frame, "| ERROR | error entry", "error")
+	assert.NotContains(t, frame, "info entry", "filtered")
+
+	log.Error("after run")
+	assert.NotContains(t, out.String(), "after run", "removed")
+
+}
+
+func Test_Viewer_Files(t *testing.T) {
+
+	defer redirectOutput()()
+
+	dir, _ := ioutil.TempDir("", "go-log")
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "app.log")
+	ioutil.WriteFile(path, []byte("t | INFO  | existing\n"), 0644)
+
+	in, commands := io.Pipe()
+	out := &syncBuffer{}
+
+	v := logviewer.New(logviewer.Options{In: in, Out: out, Files: []string{path}, PollInterval: 5 * time.Millisecond})
+
+	result := make(chan error, 1)
+	go func() {
+		result <- v.Run()
+	}()
+
+	f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	f.WriteString("t | ERROR | appended\n")
+	f.Close()
+
+	assert.Eventually(t, func() bool {
+		return strings.Contains(lastFrame(out.String()), "| ERROR | appended")
+	}, time.Second, 5*time.Millisecond, "appended")
+	assert.Contains(t, lastFrame(out.String()), "| INFO  | existing", "existing")
+
+	io.WriteString(commands, "q\n")
+	assert.NoError(t, <-result, "run")
+
+}
@@ -0,0 +1,98 @@
+package log
+
+import (
+	"bytes"
+	"sync"
+)
+
+// defaultMemoryBudget is the budget of a MemorySink created without a positive budget
+const defaultMemoryBudget = 1024 * 1024
+
+// MemorySink is a writer which keeps the most recent log entries in memory within a fixed byte budget
+//
+// It can be used as Stdout, Stderr or Events (or combined with them using io.MultiWriter) to embed the recent
+// logs in support bundles or error reports. Each call to Write is kept as a single entry, the oldest entries are
+// dropped when the budget is exceeded.
+type MemorySink struct {
+	mutex   sync.Mutex
+	budget  int
+	size    int
+	entries [][]byte
+	dropped uint64
+}
+
+// NewMemorySink returns a MemorySink which keeps at most budget bytes (defaults to 1 MB when budget is 0 or negative)
+func NewMemorySink(budget int) *MemorySink {
+	if budget <= 0 {
+		budget = defaultMemoryBudget
+	}
+	return &MemorySink{budget: budget}
+}
+
+// Write stores p as an entry
+//
+// An entry which is larger than the budget is truncated to the last budget bytes.
+func (s *MemorySink) Write(p []byte) (int, error) {
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	entry := p
+	if len(entry) > s.budget {
+		entry = entry[len(entry)-s.budget:]
+	}
+	entry = append([]byte(nil), entry...)
+
+	s.entries = append(s.entries, entry)
+	s.size += len(entry)
+
+	for s.size > s.budget && len(s.entries) > 0 {
+		s.size -= len(s.entries[0])
+		s.entries[0] = nil
+		s.entries = s.entries[1:]
+		s.dropped++
+	}
+
+	return len(p), nil
+
+}
+
+// Entries returns a copy of the entries which are currently kept, oldest first
+func (s *MemorySink) Entries() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	entries := make([]string, 0, len(s.entries))
+	for _, entry := range s.entries {
+		entries = append(entries, string(entry))
+	}
+	return entries
+}
+
+// Bytes returns the entries which are currently kept as a single buffer, oldest first
+func (s *MemorySink) Bytes() []byte {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return bytes.Join(s.entries, nil)
+}
+
+// Size returns the number of bytes which are currently kept
+func (s *MemorySink) Size() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.size
+}
+
+// Dropped returns the number of entries which were dropped to stay within the budget
+func (s *MemorySink) Dropped() uint64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.dropped
+}
+
+// Reset removes all entries
+func (s *MemorySink) Reset() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.entries = nil
+	s.size = 0
+}
@@ -0,0 +1,75 @@
+package log_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func Test_MemorySink(t *testing.T) {
+
+	resetLogConfig()
+	redirectOutput()
+	defer resetLogOutput()
+
+	sink := log.NewMemorySink(50)
+	log.Stdout = sink
+
+	log.Info("first")
+	log.Info("second")
+
+	assert.Equal(t, []string{"test | INFO  | first\n", "test | INFO  | second\n"}, sink.Entries(), "entries")
+	assert.Equal(t, "test | INFO  | first\ntest | INFO  | second\n", string(sink.Bytes()), "bytes")
+	assert.Equal(t, 43, sink.Size(), "size")
+
+	log.Info("third")
+
+	assert.Equal(t, []string{"test | INFO  | second\n", "test | INFO  | third\n"}, sink.Entries(), "evicted")
+	assert.Equal(t, uint64(1), sink.Dropped(), "dropped")
+
+	sink.Reset()
+
+	assert.Empty(t, sink.Entries(), "reset")
+	assert.Equal(t, 0, sink.Size(), "reset-size")
+
+}
+
+func Test_MemorySink_LargeEntry(t *testing.T) {
+
+	sink := log.NewMemorySink(5)
+
+	n, err := sink.Write([]byte("0123456789"))
+
+	assert.NoError(t, err, "error")
+	assert.Equal(t, 10, n, "written")
+	assert.Equal(t, []string{"56789"}, sink.Entries(), "entries")
+
+}
+
+func Test_MemorySink_InvalidBudget(t *testing.T) {
+
+	var tests = []struct {
+		name   string
+		budget int
+	}{
+		{"zero", 0},
+		{"negative", -1},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			sink := log.NewMemorySink(tc.budget)
+
+			assert.NotPanics(t, func() {
+				sink.Write([]byte("entry\n"))
+			}, "write")
+			assert.Equal(t, []string{"entry\n"}, sink.Entries(), "entries")
+			assert.Equal(t, uint64(0), sink.Dropped(), "dropped")
+
+		})
+	}
+
+}
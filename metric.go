@@ -0,0 +1,86 @@
+package log
+
+import (
+	"io"
+	"sort"
+	"time"
+)
+
+// Metrics is the writer to where the metrics should be written (defaults to Events when nil)
+var Metrics io.Writer
+
+// MetricType is the type of a metric
+type MetricType string
+
+const (
+	// MetricGauge is a metric which records the current value of something
+	MetricGauge MetricType = "gauge"
+
+	// MetricCounter is a metric which records an increment of a count
+	MetricCounter MetricType = "count"
+)
+
+type metricEntry struct {
+	Time          string     `json:"time"`
+	Metric        string     `json:"metric"`
+	Value         float64    `json:"value"`
+	Type          MetricType `json:"type"`
+	Tags          []string   `json:"tags"`
+	SchemaVersion int        `json:"schema_version"`
+}
+
+// Metric writes a gauge metric as a single JSON line
+//
+// The tags are written as sorted "key:value" pairs, as used by DogStatsD-style log-to-metric ingestion. When the
+// metric can't be encoded or written, the error is logged and returned.
+func Metric(name string, value float64, tags map[string]string) error {
+	return writeMetric(name, value, MetricGauge, tags)
+}
+
+// MetricCount writes a counter increment as a single JSON line
+func MetricCount(name string, value float64, tags map[string]string) error {
+	return writeMetric(name, value, MetricCounter, tags)
+}
+
+func writeMetric(name string, value float64, metricType MetricType, tags map[string]string) error {
+
+	if hook := currentStatsDHook(); hook != nil {
+		hook.send(name, value, metricType, formatTags(tags))
+		return nil
+	}
+
+	w := Metrics
+	if w == nil {
+		w = Events
+	}
+
+	now := time.Now()
+
+	err := writeJSONLine("metrics", w, now, metricEntry{
+		Time:          now.UTC().Format(time.RFC3339Nano),
+		Metric:        name,
+		Value:         value,
+		Type:          metricType,
+		Tags:          formatTags(tags),
+		SchemaVersion: SchemaVersion,
+	})
+	if err != nil {
+		Error("Failed to write metric", name+":", err)
+	}
+
+	return err
+
+}
+
+func formatTags(tags map[string]string) []string {
+	formatted := []string{}
+	for key, value := range tags {
+		if value == "" {
+			formatted = append(formatted, key)
+		} else {
+			formatted = append(formatted, key+":"+value)
+		}
+	}
+	sort.Strings(formatted)
+	return formatted
+}
@@ -0,0 +1,84 @@
+package log_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func Test_Metric(t *testing.T) {
+
+	type test struct {
+		name         string
+		metric       func()
+		expectedType string
+	}
+
+	var tests = []test{
+		{"gauge", func() { log.Metric("queue_depth", 42, map[string]string{"queue": "jobs", "env": "prod"}) }, "gauge"},
+		{"count", func() { log.MetricCount("queue_depth", 42, map[string]string{"queue": "jobs", "env": "prod"}) }, "count"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			resetLogConfig()
+			stdout, _ := redirectOutput()
+			defer resetLogOutput()
+
+			tc.metric()
+
+			var actual map[string]interface{}
+			err := json.Unmarshal(stdout.Bytes(), &actual)
+
+			assert.NoError(t, err, "json")
+			assert.Equal(t, "queue_depth", actual["metric"], "metric")
+			assert.Equal(t, 42.0, actual["value"], "value")
+			assert.Equal(t, tc.expectedType, actual["type"], "type")
+			assert.Equal(t, []interface{}{"env:prod", "queue:jobs"}, actual["tags"], "tags")
+
+		})
+	}
+
+}
+
+func Test_Metric_Writer(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+
+	metrics := bytes.NewBufferString("")
+	log.Metrics = metrics
+	defer func() {
+		log.Metrics = nil
+	}()
+
+	log.Metric("workers", 3, nil)
+
+	assert.Equal(t, "", stdout.String(), "stdout")
+	assert.Contains(t, metrics.String(), `"metric":"workers","value":3,"type":"gauge","tags":[]`, "metrics")
+
+}
+
+func Test_Metric_WriteFailed(t *testing.T) {
+
+	resetLogConfig()
+	_, stderr := redirectOutput()
+	defer resetLogOutput()
+
+	log.Metrics = failingWriter{}
+	defer func() {
+		log.Metrics = nil
+	}()
+
+	err := log.MetricCount("requests", 1, nil)
+
+	assert.EqualError(t, err, "write failed", "error")
+	assert.Contains(t, stderr.String(), "test | ERROR | Failed to write metric requests: write failed", "stderr")
+
+}
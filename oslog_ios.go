@@ -0,0 +1,82 @@
+//go:build ios && cgo
+// +build ios,cgo
+
+package log
+
+// #include <stdlib.h>
+// #include <os/log.h>
+//
+// static void go_log_os_log(os_log_t log, os_log_type_t type, const char *msg) {
+// 	os_log_with_type(log, type, "%{public}s", msg);
+// }
+import "C"
+
+import (
+	"bytes"
+	"unsafe"
+)
+
+// osLogTypes maps the levels to the os_log types
+var osLogTypes = map[string]C.os_log_type_t{
+	"TRACE": C.OS_LOG_TYPE_DEBUG,
+	"DEBUG": C.OS_LOG_TYPE_DEBUG,
+	"INFO":  C.OS_LOG_TYPE_INFO,
+	"NOTE":  C.OS_LOG_TYPE_DEFAULT,
+	"WARN":  C.OS_LOG_TYPE_DEFAULT,
+	"ERROR": C.OS_LOG_TYPE_ERROR,
+	"FATAL": C.OS_LOG_TYPE_FAULT,
+}
+
+// OSLogWriter is a writer which sends the log entries to the unified logging system (ios only)
+//
+// The levels are mapped to the os_log types, warnings and notices use the default type. The messages are logged as
+// public. The unified logging system adds the timestamps, so the entries don't include them, regardless of
+// PrintTimestamp.
+type OSLogWriter struct {
+	log C.os_log_t
+}
+
+// ToOSLog uses an OSLogWriter for the subsystem (e.g. "com.example.app") and category as Stdout and Stderr
+func ToOSLog(subsystem string, category string) *OSLogWriter {
+	w := NewOSLogWriter(subsystem, category)
+	Stdout = w
+	Stderr = w
+	return w
+}
+
+// NewOSLogWriter returns a writer which sends the log entries to the unified logging system
+func NewOSLogWriter(subsystem string, category string) *OSLogWriter {
+
+	cSubsystem := C.CString(subsystem)
+	defer C.free(unsafe.Pointer(cSubsystem))
+
+	cCategory := C.CString(category)
+	defer C.free(unsafe.Pointer(cCategory))
+
+	return &OSLogWriter{log: C.os_log_create(cSubsystem, cCategory)}
+
+}
+
+// Write sends p with the info type
+func (w *OSLogWriter) Write(p []byte) (int, error) {
+	return w.writeLevel("INFO", p)
+}
+
+func (w *OSLogWriter) printTimestamp() bool {
+	return false
+}
+
+func (w *OSLogWriter) writeLevel(level string, p []byte) (int, error) {
+
+	logType, ok := osLogTypes[level]
+	if !ok {
+		logType = C.OS_LOG_TYPE_DEFAULT
+	}
+
+	msg := C.CString(string(bytes.TrimRight(p, "\n")))
+	C.go_log_os_log(w.log, logType, msg)
+	C.free(unsafe.Pointer(msg))
+
+	return len(p), nil
+
+}
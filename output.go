@@ -0,0 +1,95 @@
+package log
+
+import (
+	"io"
+	"strconv"
+)
+
+// OutputOptions are the options of an output added with AddOutput
+type OutputOptions struct {
+	// Name is the name of the output in the Health status (defaults to "output-<n>")
+	Name string
+
+	// Format is the format of the entries written to the output, regardless of Format
+	Format OutputFormat
+
+	// MinLevel is the minimum level of the entries written to the output (the default writes all entries)
+	MinLevel Level
+
+	// CallerFormat defines how the caller is formatted in the entries written to the output when ReportCaller is
+	// enabled (defaults to CallerFormat)
+	CallerFormat CallerStyle
+}
+
+type output struct {
+	w    io.Writer
+	name string
+	opts OutputOptions
+}
+
+// outputs are the outputs which receive every entry next to Stdout and Stderr, guarded by logMutex
+var outputs []output
+
+// outputCount is used to name the outputs, guarded by logMutex
+var outputCount int
+
+// AddOutput writes every entry to w as well, formatted according to opts
+//
+// Unlike an io.MultiWriter, every output gets its own format, colors (see ForceColors), timestamps (see
+// WithTimestamp), sequence numbers and signature chain, and a failing output doesn't stop the others.
+func AddOutput(w io.Writer, opts OutputOptions) {
+
+	logMutex.Lock()
+	defer logMutex.Unlock()
+
+	addOutput(w, opts)
+
+}
+
+// addOutput adds the output and must be called while holding logMutex
+func addOutput(w io.Writer, opts OutputOptions) {
+
+	outputCount++
+
+	name := opts.Name
+	if name == "" {
+		name = "output-" + strconv.Itoa(outputCount)
+	}
+
+	outputs = append(outputs, output{w: w, name: name, opts: opts})
+
+}
+
+// RemoveOutput stops writing the entries to w, which was added with AddOutput, and removes it from the Health status
+func RemoveOutput(w io.Writer) {
+
+	logMutex.Lock()
+	defer logMutex.Unlock()
+
+	removeOutput(w)
+
+}
+
+// removeOutput removes the output and must be called while holding logMutex
+func removeOutput(w io.Writer) {
+	for i, o := range outputs {
+		if o.w == w {
+			outputs = append(outputs[:i:i], outputs[i+1:]...)
+			health.removeSink(o.name)
+			return
+		}
+	}
+}
+
+// RemoveOutputs removes all outputs added with AddOutput
+func RemoveOutputs() {
+
+	logMutex.Lock()
+	defer logMutex.Unlock()
+
+	for _, o := range outputs {
+		health.removeSink(o.name)
+	}
+	outputs = nil
+
+}
@@ -0,0 +1,87 @@
+package log_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func Test_AddOutput(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+	defer log.RemoveOutputs()
+
+	text := &bytes.Buffer{}
+	datadog := &bytes.Buffer{}
+	warnings := &bytes.Buffer{}
+
+	log.AddOutput(text, log.OutputOptions{})
+	log.AddOutput(datadog, log.OutputOptions{Format: log.FormatDatadog})
+	log.AddOutput(warnings, log.OutputOptions{MinLevel: log.WarnLevel})
+
+	log.Info("info")
+	log.Warn("warn")
+
+	assert.Equal(t, "test | INFO  | info\ntest | WARN  | warn\n", stdout.String(), "stdout")
+	assert.Equal(t, "test | INFO  | info\ntest | WARN  | warn\n", text.String(), "text")
+	assert.Contains(t, datadog.String(), `"message":"info"`, "datadog")
+	assert.Contains(t, datadog.String(), `"status":"warn"`, "datadog-warn")
+	assert.Equal(t, "test | WARN  | warn\n", warnings.String(), "min-level")
+
+	log.RemoveOutput(text)
+	log.Info("removed")
+
+	assert.NotContains(t, text.String(), "removed", "removed")
+	assert.Contains(t, warnings.String(), "warn", "kept")
+
+}
+
+func Test_AddOutput_Timestamp(t *testing.T) {
+
+	resetLogConfig()
+	redirectOutput()
+	defer resetLogOutput()
+	defer log.RemoveOutputs()
+
+	output := &bytes.Buffer{}
+	log.AddOutput(log.WithTimestamp(output, false), log.OutputOptions{})
+
+	log.Info("info")
+
+	assert.Equal(t, "info\n", output.String(), "no-timestamp")
+
+}
+
+func Test_AddOutput_Failing(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+	defer log.RemoveOutputs()
+
+	log.AddOutput(failingWriter{}, log.OutputOptions{Name: "failing"})
+
+	log.Info("info")
+
+	assert.Equal(t, "test | INFO  | info\n", stdout.String(), "stdout")
+
+	found := false
+	for _, sink := range log.Health().Sinks {
+		if sink.Name == "failing" {
+			found = true
+			assert.False(t, sink.Healthy, "unhealthy")
+		}
+	}
+	assert.True(t, found, "health")
+
+	log.RemoveOutputs()
+	for _, sink := range log.Health().Sinks {
+		assert.NotEqual(t, "failing", sink.Name, "removed-from-health")
+	}
+
+}
@@ -0,0 +1,29 @@
+//go:build !tinygo
+// +build !tinygo
+
+package log
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// runPager shows the text in the pager command on the terminal and waits until it's closed, it's a variable so that
+// it can be replaced during testing
+var runPager = func(command string, text string, terminal *os.File) error {
+
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return errors.New("no pager command")
+	}
+
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = strings.NewReader(text)
+	cmd.Stdout = terminal
+	cmd.Stderr = terminal
+
+	return cmd.Run()
+
+}
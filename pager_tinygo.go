@@ -0,0 +1,14 @@
+//go:build tinygo
+// +build tinygo
+
+package log
+
+import (
+	"errors"
+	"os"
+)
+
+// runPager isn't available when building with TinyGo, the dumps are only folded
+var runPager = func(command string, text string, terminal *os.File) error {
+	return errors.New("pager is not supported")
+}
@@ -0,0 +1,79 @@
+package log
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strconv"
+)
+
+// PriorityPrefixMode defines when the lines are prefixed with their syslog priority (e.g. "<4>" for warnings)
+type PriorityPrefixMode int
+
+const (
+	// PriorityPrefixOff never prefixes the lines
+	PriorityPrefixOff PriorityPrefixMode = iota
+
+	// PriorityPrefixOn prefixes the lines written to all outputs
+	PriorityPrefixOn
+
+	// PriorityPrefixAuto prefixes the lines written to the standard output or error when they are connected to the
+	// systemd journal
+	PriorityPrefixAuto
+)
+
+// PriorityPrefix defines when the lines are prefixed with the priority of their level, so that journald assigns
+// them the correct severity when the output of a systemd service is captured (see sd-daemon(3))
+var PriorityPrefix = PriorityPrefixOff
+
+// syslogSeverities maps the levels to the syslog severities
+var syslogSeverities = map[string]int{
+	"TRACE": 7,
+	"DEBUG": 7,
+	"INFO":  6,
+	"NOTE":  5,
+	"WARN":  4,
+	"ERROR": 3,
+	"FATAL": 2,
+}
+
+// isJournalStream is a variable so that it can be replaced during testing
+var isJournalStream = func(w io.Writer) bool {
+	f, ok := unwrapWriter(w).(*os.File)
+	if !ok || (f != os.Stdout && f != os.Stderr) {
+		return false
+	}
+	return journalStream(f)
+}
+
+// prefixPriority prefixes each line of the entry with the priority of the level when enabled for w
+func prefixPriority(line []byte, level string, w io.Writer) []byte {
+
+	switch PriorityPrefix {
+	case PriorityPrefixOn:
+	case PriorityPrefixAuto:
+		if !isJournalStream(w) {
+			return line
+		}
+	default:
+		return line
+	}
+
+	severity, ok := syslogSeverities[level]
+	if !ok {
+		severity = syslogSeverities["INFO"]
+	}
+	prefix := []byte("<" + strconv.Itoa(severity) + ">")
+
+	body := bytes.TrimRight(line, "\n")
+
+	var b bytes.Buffer
+	for _, part := range bytes.Split(body, []byte("\n")) {
+		b.Write(prefix)
+		b.Write(part)
+		b.WriteByte('\n')
+	}
+
+	return b.Bytes()
+
+}
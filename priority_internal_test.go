@@ -0,0 +1,55 @@
+package log
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_prefixPriority(t *testing.T) {
+
+	oldIsJournalStream := isJournalStream
+	defer func() {
+		isJournalStream = oldIsJournalStream
+		PriorityPrefix = PriorityPrefixOff
+	}()
+
+	type test struct {
+		name     string
+		mode     PriorityPrefixMode
+		journal  bool
+		level    string
+		line     string
+		expected string
+	}
+
+	var tests = []test{
+		{"off", PriorityPrefixOff, true, "WARN", "message\n", "message\n"},
+		{"on", PriorityPrefixOn, false, "WARN", "message\n", "<4>message\n"},
+		{"auto-journal", PriorityPrefixAuto, true, "ERROR", "message\n", "<3>message\n"},
+		{"auto-not-journal", PriorityPrefixAuto, false, "ERROR", "message\n", "message\n"},
+		{"debug", PriorityPrefixOn, false, "DEBUG", "message\n", "<7>message\n"},
+		{"notice", PriorityPrefixOn, false, "NOTE", "message\n", "<5>message\n"},
+		{"fatal", PriorityPrefixOn, false, "FATAL", "message\n", "<2>message\n"},
+		{"unknown", PriorityPrefixOn, false, "OTHER", "message\n", "<6>message\n"},
+		{"multi-line", PriorityPrefixOn, false, "ERROR", "message\nstack\n", "<3>message\n<3>stack\n"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			isJournalStream = func(w io.Writer) bool {
+				return tc.journal
+			}
+			PriorityPrefix = tc.mode
+			actual := prefixPriority([]byte(tc.line), tc.level, &bytes.Buffer{})
+			assert.Equal(t, tc.expected, string(actual))
+		})
+	}
+
+}
+
+func Test_isJournalStream(t *testing.T) {
+	assert.False(t, isJournalStream(&bytes.Buffer{}), "buffer")
+}
@@ -0,0 +1,13 @@
+//go:build tinygo || (!darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd)
+// +build tinygo !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd
+
+package log
+
+import (
+	"os"
+)
+
+// journalStream reports if f is connected to the systemd journal, which only exists on unix platforms
+func journalStream(f *os.File) bool {
+	return false
+}
@@ -0,0 +1,30 @@
+package log_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func Test_PriorityPrefix(t *testing.T) {
+
+	resetLogConfig()
+	stdout, stderr := redirectOutput()
+	defer resetLogOutput()
+	defer func() {
+		log.PriorityPrefix = log.PriorityPrefixOff
+	}()
+
+	log.PrintTimestamp = false
+	log.PriorityPrefix = log.PriorityPrefixOn
+
+	log.Info("info")
+	log.Warn("warn")
+	log.Error("error")
+
+	assert.Equal(t, "<6>info\n<4>warn\n", stdout.String(), "stdout")
+	assert.Equal(t, "<3>error\n", stderr.String(), "stderr")
+
+}
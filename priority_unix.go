@@ -0,0 +1,29 @@
+//go:build (darwin || dragonfly || freebsd || linux || netbsd || openbsd) && !tinygo
+// +build darwin dragonfly freebsd linux netbsd openbsd
+// +build !tinygo
+
+package log
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// journalStream reports if f is the stream systemd connected to the journal, by comparing its device and inode
+// with the JOURNAL_STREAM environment variable
+func journalStream(f *os.File) bool {
+
+	stream := os.Getenv("JOURNAL_STREAM")
+	if stream == "" {
+		return false
+	}
+
+	var stat syscall.Stat_t
+	if err := syscall.Fstat(int(f.Fd()), &stat); err != nil {
+		return false
+	}
+
+	return stream == fmt.Sprintf("%d:%d", stat.Dev, stat.Ino)
+
+}
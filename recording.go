@@ -0,0 +1,124 @@
+package log
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+// ErrAlreadyRecording is returned when starting a recording while another one is active
+var ErrAlreadyRecording = errors.New("already recording")
+
+// RecordedEntry is a log entry as stored in a session recording
+type RecordedEntry struct {
+	Time          time.Time              `json:"time"`
+	Level         string                 `json:"level"`
+	Message       string                 `json:"message"`
+	Fields        map[string]interface{} `json:"fields,omitempty"`
+	Caller        string                 `json:"caller,omitempty"`
+	Goroutine     uint64                 `json:"goroutine,omitempty"`
+	SchemaVersion int                    `json:"schema_version"`
+}
+
+type recording struct {
+	file    *os.File
+	gzip    *gzip.Writer
+	encoder *json.Encoder
+}
+
+var recordingActive int32
+
+// activeRecording is guarded by logMutex
+var activeRecording *recording
+
+// StartRecording starts mirroring all log entries into a gzip compressed file at path
+//
+// All entries are recorded regardless of TraceMode and DebugMode, with their full metadata (caller and goroutine)
+// as one JSON object per line, so that a complete session can be attached to a bug report.
+func StartRecording(path string) error {
+
+	logMutex.Lock()
+	defer logMutex.Unlock()
+
+	if activeRecording != nil {
+		return ErrAlreadyRecording
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(file)
+	activeRecording = &recording{
+		file:    file,
+		gzip:    gz,
+		encoder: json.NewEncoder(gz),
+	}
+	atomic.StoreInt32(&recordingActive, 1)
+
+	return nil
+
+}
+
+// StopRecording stops the active recording and closes its file
+func StopRecording() error {
+
+	logMutex.Lock()
+	defer logMutex.Unlock()
+
+	if activeRecording == nil {
+		return nil
+	}
+
+	r := activeRecording
+	activeRecording = nil
+	atomic.StoreInt32(&recordingActive, 0)
+
+	err := r.gzip.Close()
+	if closeErr := r.file.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+
+}
+
+func isRecording() bool {
+	return atomic.LoadInt32(&recordingActive) == 1
+}
+
+// recordEntry must be called while holding logMutex
+func recordEntry(e entry) {
+
+	if activeRecording == nil {
+		return
+	}
+
+	caller := e.caller
+	if caller == "" {
+		if frame, ok := findCaller(e.logger.callerSkip()); ok {
+			caller = formatFrame(frame, CallerFull)
+		}
+	}
+
+	goroutine := e.goroutine
+	if goroutine == 0 {
+		goroutine = GoroutineID()
+	}
+
+	err := activeRecording.encoder.Encode(RecordedEntry{
+		Time:          e.time,
+		Level:         e.level,
+		Message:       e.message,
+		Fields:        e.fields,
+		Caller:        caller,
+		Goroutine:     goroutine,
+		SchemaVersion: SchemaVersion,
+	})
+	health.recordWrite("recording", e.time, 0, err)
+
+}
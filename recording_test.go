@@ -0,0 +1,81 @@
+package log_test
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func readRecording(t *testing.T, path string) []log.RecordedEntry {
+
+	file, err := os.Open(path)
+	assert.NoError(t, err, "open")
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	assert.NoError(t, err, "gzip")
+
+	entries := []log.RecordedEntry{}
+	scanner := bufio.NewScanner(gz)
+	for scanner.Scan() {
+		var entry log.RecordedEntry
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &entry), "json")
+		entries = append(entries, entry)
+	}
+
+	return entries
+
+}
+
+func Test_Recording(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+
+	dir, _ := ioutil.TempDir("", "go-log")
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "session.log.gz")
+
+	log.Info("before")
+
+	assert.NoError(t, log.StartRecording(path), "start")
+	assert.Equal(t, log.ErrAlreadyRecording, log.StartRecording(path), "start-twice")
+
+	log.Debug("hidden debug")
+	log.Trace("hidden trace")
+	log.InfoTemplate("user {user}", map[string]interface{}{"user": "john"})
+
+	assert.NoError(t, log.StopRecording(), "stop")
+	assert.NoError(t, log.StopRecording(), "stop-twice")
+
+	log.Info("after")
+
+	assert.Equal(t, "test | INFO  | before\ntest | INFO  | user john msg.template=\"user {user}\" user=john\ntest | INFO  | after\n", stdout.String(), "stdout")
+
+	entries := readRecording(t, path)
+
+	assert.Len(t, entries, 3, "entries")
+	assert.Equal(t, "DEBUG", entries[0].Level, "debug-level")
+	assert.Equal(t, "hidden debug", entries[0].Message, "debug-message")
+	assert.Equal(t, "TRACE", entries[1].Level, "trace-level")
+	assert.Equal(t, "john", entries[2].Fields["user"], "fields")
+	assert.Contains(t, entries[2].Caller, "recording_test.go:", "caller")
+	assert.Equal(t, log.GoroutineID(), entries[2].Goroutine, "goroutine")
+	assert.False(t, entries[2].Time.IsZero(), "time")
+
+}
+
+func Test_StartRecording_Invalid(t *testing.T) {
+	err := log.StartRecording(filepath.Join("missing", "dir", "session.log.gz"))
+	assert.Error(t, err)
+}
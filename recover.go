@@ -0,0 +1,67 @@
+package log
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Recover recovers from a panic and logs it as an error including the stack trace
+//
+// It should be used as:
+//
+//	defer log.Recover()
+//
+// Errors are logged with their full chain of wrapped errors, other values are dumped structurally. The entry has
+// the field "recovered=true" so that panics can be counted separately from regular errors.
+func Recover() {
+	if r := recover(); r != nil {
+		logPanic(r)
+	}
+}
+
+func logPanic(r interface{}) {
+	if exitOnFatal(r) {
+		return
+	}
+	message := "Recovered from panic: " + formatPanicValue(r) + "\n" + panicStack()
+	printEntry("ERROR", message, map[string]interface{}{"recovered": true})
+}
+
+func formatPanicValue(r interface{}) string {
+
+	switch value := r.(type) {
+	case string:
+		return value
+	case error:
+		return formatErrorChain(value)
+	default:
+		return dump(value)
+	}
+
+}
+
+func formatErrorChain(err error) string {
+
+	type causer interface {
+		Cause() error
+	}
+
+	lines := []string{fmt.Sprintf("%T %s", err, err.Error())}
+	for {
+		var next error
+		if c, ok := err.(causer); ok {
+			next = c.Cause()
+		} else {
+			next = errors.Unwrap(err)
+		}
+		if next == nil || next == err {
+			break
+		}
+		err = next
+		lines = append(lines, fmt.Sprintf("caused by: %T %s", err, err.Error()))
+	}
+
+	return strings.Join(lines, "\n")
+
+}
@@ -0,0 +1,74 @@
+package log_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+type panicValue struct {
+	Code   int
+	Reason string
+}
+
+func panicking(value interface{}) {
+	defer log.Recover()
+	panic(value)
+}
+
+func Test_Recover(t *testing.T) {
+
+	type test struct {
+		name           string
+		value          interface{}
+		expectedPrefix string
+	}
+
+	base := errors.New("base error")
+
+	var tests = []test{
+		{"string", "boom", "test | ERROR | Recovered from panic: boom\n"},
+		{"error", fmt.Errorf("wrapped: %w", base), "test | ERROR | Recovered from panic: *fmt.wrapError wrapped: base error\ncaused by: *errors.fundamental base error\n"},
+		{"struct", panicValue{Code: 42, Reason: "bad"}, "test | ERROR | Recovered from panic: log_test.panicValue{\n  Code: 42,\n  Reason: \"bad\",\n}\n"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			resetLogConfig()
+			stdout, stderr := redirectOutput()
+			defer resetLogOutput()
+
+			panicking(tc.value)
+
+			actual := stderr.String()
+
+			assert.Equal(t, "", stdout.String(), "stdout")
+			assert.True(t, strings.HasPrefix(actual, tc.expectedPrefix), "prefix")
+			assert.Contains(t, actual, "\tpanicking: panic(value)", "stack")
+			assert.True(t, strings.HasSuffix(actual, " recovered=true\n"), "field")
+
+		})
+	}
+
+}
+
+func Test_Recover_NoPanic(t *testing.T) {
+
+	resetLogConfig()
+	stdout, stderr := redirectOutput()
+	defer resetLogOutput()
+
+	func() {
+		defer log.Recover()
+	}()
+
+	assert.Equal(t, "", stdout.String(), "stdout")
+	assert.Equal(t, "", stderr.String(), "stderr")
+
+}
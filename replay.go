@@ -0,0 +1,154 @@
+package log
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var replayLevels = map[string]string{
+	"NOTICE":   "NOTE",
+	"WARNING":  "WARN",
+	"CRITICAL": "FATAL",
+}
+
+// Replay reads a recorded session or a JSON log from r and emits its entries through the current configuration
+//
+// The input is one JSON object per line and may be gzip compressed. Both the format written by StartRecording and the
+// Datadog format are understood, any unknown keys are replayed as fields. The original time of each entry is kept,
+// while the level filters, scrub rules, format, streams and taps currently configured are applied (hooks are not
+// fired for replayed entries). It returns the number of entries which were read.
+func Replay(r io.Reader) (int, error) {
+
+	reader := bufio.NewReader(r)
+	if magic, err := reader.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(reader)
+		if err != nil {
+			return 0, err
+		}
+		defer gz.Close()
+		reader = bufio.NewReader(gz)
+	}
+
+	count := 0
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	for line := 1; scanner.Scan(); line++ {
+
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+
+		e, err := parseReplayLine(scanner.Bytes())
+		if err != nil {
+			return count, fmt.Errorf("line %d: %v", line, err)
+		}
+
+		replayEntry(e)
+		count++
+
+	}
+
+	return count, scanner.Err()
+
+}
+
+// ReplayFile replays the recorded session or JSON log stored at path
+func ReplayFile(path string) (int, error) {
+
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	return Replay(file)
+
+}
+
+func parseReplayLine(data []byte) (entry, error) {
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var values map[string]interface{}
+	if err := decoder.Decode(&values); err != nil {
+		return entry{}, err
+	}
+
+	e := entry{
+		time:   time.Now(),
+		level:  "INFO",
+		fields: map[string]interface{}{},
+	}
+
+	for key, value := range values {
+		switch key {
+		case "time", "timestamp":
+			if t, err := time.Parse(time.RFC3339Nano, fmt.Sprint(value)); err == nil {
+				e.time = t
+			}
+		case "level", "status":
+			e.level = replayLevel(fmt.Sprint(value))
+		case "message", "msg":
+			e.message = fmt.Sprint(value)
+		case "caller", "logger.method_name":
+			e.caller = fmt.Sprint(value)
+		case "goroutine", "logger.thread_name":
+			e.goroutine, _ = strconv.ParseUint(fmt.Sprint(value), 10, 64)
+		case SchemaVersionField:
+		case "fields":
+			if fields, ok := value.(map[string]interface{}); ok {
+				for name, field := range fields {
+					e.fields[name] = field
+				}
+			}
+		default:
+			e.fields[key] = value
+		}
+	}
+
+	if len(e.fields) == 0 {
+		e.fields = nil
+	}
+
+	return e, nil
+
+}
+
+func replayLevel(level string) string {
+	level = strings.TrimSpace(strings.ToUpper(level))
+	if mapped, ok := replayLevels[level]; ok {
+		return mapped
+	}
+	return level
+}
+
+func replayEntry(e entry) {
+
+	e.message, e.fields = redactEntry(e.level, e.message, e.fields)
+
+	if !ReportCaller {
+		e.caller = ""
+	}
+
+	if !PrintGoroutineID {
+		e.goroutine = 0
+	}
+
+	logMutex.Lock()
+	defer logMutex.Unlock()
+
+	if e.logger.levelEnabled(e.level) {
+		writeEntry(e)
+	}
+
+}
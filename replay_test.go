@@ -0,0 +1,165 @@
+package log_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func Test_Replay(t *testing.T) {
+
+	type test struct {
+		name      string
+		input     string
+		debugMode bool
+		stdout    string
+		stderr    string
+	}
+
+	var tests = []test{
+		{
+			"recording",
+			`{"time":"2020-03-04T12:00:00Z","level":"INFO","message":"hello","fields":{"user":"john"},"caller":"main.go:1","goroutine":1}`,
+			false,
+			"2020-03-04 | INFO  | hello user=john\n",
+			"",
+		},
+		{
+			"datadog",
+			`{"timestamp":"2020-03-04T12:00:00Z","status":"critical","message":"boom","attempt":3}`,
+			false,
+			"",
+			"2020-03-04 | FATAL | boom attempt=3\n",
+		},
+		{
+			"debug-filtered",
+			`{"time":"2020-03-04T12:00:00Z","level":"DEBUG","message":"hidden"}`,
+			false,
+			"",
+			"",
+		},
+		{
+			"debug-enabled",
+			`{"time":"2020-03-04T12:00:00Z","level":"DEBUG","message":"shown"}`,
+			true,
+			"2020-03-04 | DEBUG | shown\n",
+			"",
+		},
+		{
+			"blank-lines",
+			"\n" + `{"time":"2020-03-04T12:00:00Z","status":"notice","msg":"first"}` + "\n\n" + `{"time":"2020-03-04T12:00:00Z","status":"warning","msg":"second"}` + "\n",
+			false,
+			"2020-03-04 | NOTE  | first\n2020-03-04 | WARN  | second\n",
+			"",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			resetLogConfig()
+			stdout, stderr := redirectOutput()
+			defer resetLogOutput()
+
+			log.TimeFormat = "2006-01-02"
+			log.DebugMode = tc.debugMode
+
+			_, err := log.Replay(strings.NewReader(tc.input))
+
+			assert.NoError(t, err, "error")
+			assert.Equal(t, tc.stdout, stdout.String(), "stdout")
+			assert.Equal(t, tc.stderr, stderr.String(), "stderr")
+
+		})
+	}
+
+}
+
+func Test_Replay_Gzip(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	gz.Write([]byte(`{"level":"INFO","message":"one"}` + "\n" + `{"level":"INFO","message":"two"}` + "\n"))
+	gz.Close()
+
+	count, err := log.Replay(&buf)
+
+	assert.NoError(t, err, "error")
+	assert.Equal(t, 2, count, "count")
+	assert.Equal(t, "test | INFO  | one\ntest | INFO  | two\n", stdout.String(), "stdout")
+
+}
+
+func Test_Replay_Invalid(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+
+	count, err := log.Replay(strings.NewReader(`{"message":"valid"}` + "\nnot json\n"))
+
+	assert.Error(t, err, "error")
+	assert.Contains(t, err.Error(), "line 2", "line")
+	assert.Equal(t, 1, count, "count")
+	assert.Equal(t, "test | INFO  | valid\n", stdout.String(), "stdout")
+
+}
+
+func Test_ReplayFile_Recording(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+
+	dir, _ := ioutil.TempDir("", "go-log")
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "session.log.gz")
+
+	assert.NoError(t, log.StartRecording(path), "start")
+	log.Debug("recorded")
+	assert.NoError(t, log.StopRecording(), "stop")
+
+	log.DebugMode = true
+	stdout.Reset()
+
+	count, err := log.ReplayFile(path)
+
+	assert.NoError(t, err, "error")
+	assert.Equal(t, 1, count, "count")
+	assert.Equal(t, "test | DEBUG | recorded\n", stdout.String(), "stdout")
+
+}
+
+func Test_ReplayFile_Missing(t *testing.T) {
+	_, err := log.ReplayFile(filepath.Join("missing", "session.log.gz"))
+	assert.Error(t, err)
+}
+
+func Test_Replay_Scrub(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+	defer resetScrubConfig()
+
+	log.ScrubRules = []log.ScrubRule{emailRule}
+
+	_, err := log.Replay(strings.NewReader(`{"level":"INFO","message":"mail john@example.com","fields":{"to":"jane@example.com"}}`))
+
+	assert.NoError(t, err, "error")
+	assert.Equal(t, "test | INFO  | mail [REDACTED] to=[REDACTED]\n", stdout.String(), "stdout")
+
+}
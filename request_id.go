@@ -0,0 +1,90 @@
+package log
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"sync"
+	"time"
+)
+
+// crockford is the Crockford base32 alphabet used by ULIDs
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+var requestIDMutex = &sync.Mutex{}
+var lastRequestIDTime uint64
+var lastRequestIDEntropy [10]byte
+
+type requestIDKey struct{}
+
+// ContextWithRequestID returns a copy of ctx carrying the request ID
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the request ID carried by ctx (or an empty string if there is none)
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// NewRequestID returns a new unique request ID in ULID format (26 characters)
+//
+// The IDs start with the current time in milliseconds so that they sort in the order in which they were created.
+// IDs created within the same millisecond are monotonically increasing.
+func NewRequestID() string {
+
+	ms := uint64(time.Now().UnixNano() / int64(time.Millisecond))
+
+	requestIDMutex.Lock()
+	if ms <= lastRequestIDTime {
+		ms = lastRequestIDTime
+		incrementEntropy(&lastRequestIDEntropy)
+	} else {
+		lastRequestIDTime = ms
+		if _, err := rand.Read(lastRequestIDEntropy[:]); err != nil {
+			binary.BigEndian.PutUint64(lastRequestIDEntropy[2:], uint64(time.Now().UnixNano()))
+		}
+	}
+	entropy := lastRequestIDEntropy
+	requestIDMutex.Unlock()
+
+	var id [16]byte
+	id[0] = byte(ms >> 40)
+	id[1] = byte(ms >> 32)
+	id[2] = byte(ms >> 24)
+	id[3] = byte(ms >> 16)
+	id[4] = byte(ms >> 8)
+	id[5] = byte(ms)
+	copy(id[6:], entropy[:])
+
+	return encodeULID(id)
+
+}
+
+func incrementEntropy(entropy *[10]byte) {
+	for i := len(entropy) - 1; i >= 0; i-- {
+		entropy[i]++
+		if entropy[i] != 0 {
+			return
+		}
+	}
+}
+
+func encodeULID(id [16]byte) string {
+
+	// 128 bits are encoded as 26 characters of 5 bits, the first character only holds 3 bits
+	var hi, lo uint64
+	hi = binary.BigEndian.Uint64(id[:8])
+	lo = binary.BigEndian.Uint64(id[8:])
+
+	out := make([]byte, 26)
+	for i := 25; i >= 0; i-- {
+		out[i] = crockford[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+
+	return string(out)
+
+}
@@ -0,0 +1,37 @@
+package log
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_encodeULID(t *testing.T) {
+
+	type test struct {
+		name     string
+		id       [16]byte
+		expected string
+	}
+
+	var tests = []test{
+		{"zero", [16]byte{}, "00000000000000000000000000"},
+		{"max", [16]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, "7ZZZZZZZZZZZZZZZZZZZZZZZZZ"},
+		{"one", [16]byte{15: 1}, "00000000000000000000000001"},
+		{"timestamp", [16]byte{0x01, 0x6b, 0x4e, 0x9a, 0x1b, 0x10}, "01DD79M6RG0000000000000000"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := encodeULID(tc.id)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+
+}
+
+func Test_incrementEntropy(t *testing.T) {
+	entropy := [10]byte{9: 0xff}
+	incrementEntropy(&entropy)
+	assert.Equal(t, [10]byte{8: 1}, entropy)
+}
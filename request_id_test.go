@@ -0,0 +1,34 @@
+package log_test
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func Test_NewRequestID(t *testing.T) {
+
+	ids := make([]string, 1000)
+	seen := map[string]bool{}
+
+	for i := range ids {
+		ids[i] = log.NewRequestID()
+		assert.Len(t, ids[i], 26, "length")
+		assert.Regexp(t, "^[0-9A-HJKMNP-TV-Z]{26}$", ids[i], "alphabet")
+		assert.False(t, seen[ids[i]], "unique")
+		seen[ids[i]] = true
+	}
+
+	assert.True(t, sort.StringsAreSorted(ids), "sorted")
+
+}
+
+func Test_RequestIDFromContext(t *testing.T) {
+	ctx := context.Background()
+	assert.Equal(t, "", log.RequestIDFromContext(ctx), "missing")
+	assert.Equal(t, "id", log.RequestIDFromContext(log.ContextWithRequestID(ctx, "id")), "present")
+}
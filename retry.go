@@ -0,0 +1,61 @@
+package log
+
+import (
+	"context"
+	"time"
+)
+
+// Retry calls fn until it succeeds, at most attempts times, and logs each attempt and the final outcome
+//
+// The delay between attempts starts at backoff and doubles after each failed attempt. Failed attempts are logged
+// as warnings, giving up as an error. When ctx is done while waiting, the context error is returned.
+func Retry(ctx context.Context, attempts int, backoff time.Duration, fn func() error) error {
+
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	delay := backoff
+
+	for attempt := 1; ; attempt++ {
+
+		err := fn()
+
+		fields := map[string]interface{}{
+			"attempt":  attempt,
+			"attempts": attempts,
+		}
+
+		if err == nil {
+			if attempt > 1 {
+				printEntry("INFO", "Retry succeeded", fields)
+			}
+			return nil
+		}
+
+		fields["error"] = err.Error()
+
+		if attempt >= attempts {
+			printEntry("ERROR", "Retry failed, giving up", fields)
+			return err
+		}
+
+		fields["delay"] = delay.String()
+		printEntry("WARN", "Retry attempt failed", fields)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			delete(fields, "delay")
+			fields["error"] = ctx.Err().Error()
+			printEntry("ERROR", "Retry canceled", fields)
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		delay *= 2
+
+	}
+
+}
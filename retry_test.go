@@ -0,0 +1,87 @@
+package log_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func Test_Retry_Succeeds(t *testing.T) {
+
+	resetLogConfig()
+	stdout, stderr := redirectOutput()
+	defer resetLogOutput()
+
+	calls := 0
+	err := log.Retry(context.Background(), 3, time.Millisecond, func() error {
+		calls++
+		if calls < 3 {
+			return errors.New("unavailable")
+		}
+		return nil
+	})
+
+	expected := "test | WARN  | Retry attempt failed attempt=1 attempts=3 delay=1ms error=unavailable\n" +
+		"test | WARN  | Retry attempt failed attempt=2 attempts=3 delay=2ms error=unavailable\n" +
+		"test | INFO  | Retry succeeded attempt=3 attempts=3\n"
+
+	assert.NoError(t, err, "error")
+	assert.Equal(t, 3, calls, "calls")
+	assert.Equal(t, expected, stdout.String(), "stdout")
+	assert.Equal(t, "", stderr.String(), "stderr")
+
+}
+
+func Test_Retry_FirstAttempt(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+
+	err := log.Retry(context.Background(), 3, time.Millisecond, func() error {
+		return nil
+	})
+
+	assert.NoError(t, err, "error")
+	assert.Equal(t, "", stdout.String(), "stdout")
+
+}
+
+func Test_Retry_GivesUp(t *testing.T) {
+
+	resetLogConfig()
+	stdout, stderr := redirectOutput()
+	defer resetLogOutput()
+
+	err := log.Retry(context.Background(), 2, time.Millisecond, func() error {
+		return errors.New("unavailable")
+	})
+
+	assert.EqualError(t, err, "unavailable", "error")
+	assert.Equal(t, "test | WARN  | Retry attempt failed attempt=1 attempts=2 delay=1ms error=unavailable\n", stdout.String(), "stdout")
+	assert.Equal(t, "test | ERROR | Retry failed, giving up attempt=2 attempts=2 error=unavailable\n", stderr.String(), "stderr")
+
+}
+
+func Test_Retry_Canceled(t *testing.T) {
+
+	resetLogConfig()
+	_, stderr := redirectOutput()
+	defer resetLogOutput()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := log.Retry(ctx, 5, time.Hour, func() error {
+		return errors.New("unavailable")
+	})
+
+	assert.Equal(t, context.Canceled, err, "error")
+	assert.Equal(t, "test | ERROR | Retry canceled attempt=1 attempts=5 error=\"context canceled\"\n", stderr.String(), "stderr")
+
+}
@@ -0,0 +1,446 @@
+package log
+
+import (
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupTimeFormat is the format of the timestamp in the names of the rotated files
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// RotationOptions are the options used to open a RotatingFileSink
+type RotationOptions struct {
+	// FileOptions are used to open the file, Mode only applies to the file which is opened first
+	FileOptions
+
+	// MaxSizeMB is the size in megabytes after which the file is rotated (0 disables size-based rotation)
+	MaxSizeMB int
+
+	// Interval is the time after which the file is rotated (0 disables time-based rotation)
+	Interval time.Duration
+
+	// MaxAge is the age after which rotated files are removed (0 keeps them regardless of their age)
+	MaxAge time.Duration
+
+	// MaxBackups is the number of rotated files which are kept (0 keeps all of them)
+	MaxBackups int
+
+	// Compress compresses the rotated files using gzip
+	Compress bool
+
+	// Archive uploads the rotated files (after compressing and encrypting them) to a store and removes the local
+	// copies (nil keeps them locally)
+	Archive *ArchiveOptions
+
+	// EncryptionKey encrypts the rotated files (after compressing them) with AES-GCM to name.enc, it must be 16, 24 or
+	// 32 bytes long (nil keeps them in plain text), see DecryptArchive
+	EncryptionKey []byte
+
+	// CopyTruncate rotates by copying the file and truncating it instead of renaming it, for files which other
+	// processes keep open (entries written by them while copying are lost)
+	//
+	// It's also used when renaming the file fails, such as on Windows when another process opened the file without
+	// sharing it for deleting.
+	CopyTruncate bool
+}
+
+// RotatingFileSink is a FileSink which rotates the file when it gets too large or too old
+//
+// The rotated files are renamed (or copied, see CopyTruncate) to name-<timestamp>.ext (in UTC) next to the file.
+// Compressing, encrypting, uploading and removing the rotated files happens in the background, Close waits for it to
+// finish.
+type RotatingFileSink struct {
+	mutex    sync.Mutex
+	path     string
+	opts     RotationOptions
+	sink     *FileSink
+	size     int64
+	openedAt time.Time
+	cleanup  sync.WaitGroup
+
+	// cleanupMutex makes sure only one rotated file is processed at a time
+	cleanupMutex sync.Mutex
+
+	// pendingUploads are the rotated files which failed to upload, guarded by cleanupMutex
+	pendingUploads []string
+
+	// unprocessed are the rotated files which are waiting for cleanupBackups, guarded by mutex
+	unprocessed map[string]bool
+}
+
+// ToFile opens a RotatingFileSink at path and uses it as Stdout and Stderr
+func ToFile(path string, opts RotationOptions) (*RotatingFileSink, error) {
+
+	sink, err := OpenRotatingFileSink(path, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	Stdout = sink
+	Stderr = sink
+
+	return sink, nil
+
+}
+
+// OpenRotatingFileSink opens the file at path for writing log entries and rotates it according to opts
+func OpenRotatingFileSink(path string, opts RotationOptions) (*RotatingFileSink, error) {
+	if opts.EncryptionKey != nil {
+		if _, err := archiveCipher(opts.EncryptionKey); err != nil {
+			return nil, err
+		}
+	}
+	s := &RotatingFileSink{path: path, opts: opts}
+	if err := s.open(opts.FileOptions); err != nil {
+		return nil, err
+	}
+	s.uploadLeftovers()
+	return s, nil
+}
+
+// Path returns the path of the file
+func (s *RotatingFileSink) Path() string {
+	return s.path
+}
+
+// Write writes p to the file, rotating it first when needed
+func (s *RotatingFileSink) Write(p []byte) (int, error) {
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.sink == nil {
+		return 0, ErrSinkClosed
+	}
+
+	if s.shouldRotate(len(p)) {
+		if err := s.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := s.sink.Write(p)
+	s.size += int64(n)
+	return n, err
+
+}
+
+// Rotate rotates the file, regardless of its size or age
+func (s *RotatingFileSink) Rotate() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.sink == nil {
+		return ErrSinkClosed
+	}
+	return s.rotate()
+}
+
+// Sync commits the contents of the file to stable storage
+func (s *RotatingFileSink) Sync() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.sink == nil {
+		return ErrSinkClosed
+	}
+	return s.sink.Sync()
+}
+
+// Close closes the file and waits for the rotated files to be compressed and removed
+func (s *RotatingFileSink) Close() error {
+	s.mutex.Lock()
+	var err error
+	if s.sink != nil {
+		err = s.sink.Close()
+		s.sink = nil
+	}
+	s.mutex.Unlock()
+	s.cleanup.Wait()
+	return err
+}
+
+func (s *RotatingFileSink) open(opts FileOptions) error {
+
+	sink, err := OpenFileSink(s.path, opts)
+	if err != nil {
+		return err
+	}
+
+	s.sink = sink
+	s.size = 0
+	s.openedAt = time.Now()
+
+	if info, err := os.Stat(s.path); err == nil {
+		s.size = info.Size()
+	}
+
+	return nil
+
+}
+
+func (s *RotatingFileSink) shouldRotate(n int) bool {
+	if s.opts.MaxSizeMB > 0 && s.size > 0 && s.size+int64(n) > int64(s.opts.MaxSizeMB)*1024*1024 {
+		return true
+	}
+	return s.opts.Interval > 0 && time.Since(s.openedAt) >= s.opts.Interval
+}
+
+func (s *RotatingFileSink) rotate() error {
+
+	if err := s.sink.Close(); err != nil {
+		return err
+	}
+	s.sink = nil
+
+	backup := s.backupPath(time.Now().UTC())
+	mode, err := s.moveToBackup(backup)
+
+	// The file is reopened even when moving it failed, so that the entries are still written
+	opts := s.opts.FileOptions
+	opts.Mode = mode
+	if err := s.open(opts); err != nil {
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	if s.unprocessed == nil {
+		s.unprocessed = map[string]bool{}
+	}
+	s.unprocessed[backup] = true
+
+	s.cleanup.Add(1)
+	go func() {
+		defer s.cleanup.Done()
+		s.cleanupBackups(backup)
+	}()
+
+	return nil
+
+}
+
+// moveToBackup moves the contents of the file to backup and returns the mode to reopen the file with
+func (s *RotatingFileSink) moveToBackup(backup string) (FileMode, error) {
+
+	if !s.opts.CopyTruncate {
+		err := os.Rename(s.path, backup)
+		if err == nil || os.IsNotExist(err) {
+			return FileAppend, nil
+		}
+	}
+
+	if err := copyFile(s.path, backup); err != nil {
+		if os.IsNotExist(err) {
+			return FileAppend, nil
+		}
+		return FileAppend, err
+	}
+
+	return FileTruncate, nil
+
+}
+
+// backupPath returns an unused path for the rotated file, based on the time of the rotation
+func (s *RotatingFileSink) backupPath(t time.Time) string {
+	ext := filepath.Ext(s.path)
+	prefix := strings.TrimSuffix(s.path, ext) + "-"
+	for {
+		path := prefix + t.Format(backupTimeFormat) + ext
+		if !fileExists(path) && !fileExists(path+".gz") && !fileExists(path+".enc") && !fileExists(path+".gz.enc") {
+			return path
+		}
+		t = t.Add(time.Millisecond)
+	}
+}
+
+func (s *RotatingFileSink) cleanupBackups(backup string) {
+
+	s.cleanupMutex.Lock()
+	defer s.cleanupMutex.Unlock()
+
+	rotated := backup
+	backup = s.prepareBackup(backup)
+
+	// Files which fail to upload are kept locally and retried after the next rotation
+	if s.opts.Archive != nil && s.opts.Archive.Store != nil {
+		s.uploadBackups(append(s.pendingUploads, backup))
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.unprocessed, rotated)
+
+	if s.opts.MaxBackups <= 0 && s.opts.MaxAge <= 0 {
+		return
+	}
+
+	// Files which still have to be uploaded, or to be processed by a later call, are kept until they are
+	pending := make(map[string]bool, len(s.pendingUploads))
+	for _, path := range s.pendingUploads {
+		pending[path] = true
+	}
+
+	backups := s.backups()
+	for i, b := range backups {
+		if pending[b.path] || s.unprocessed[strings.TrimSuffix(strings.TrimSuffix(b.path, ".enc"), ".gz")] {
+			continue
+		}
+		if (s.opts.MaxBackups > 0 && i >= s.opts.MaxBackups) || (s.opts.MaxAge > 0 && time.Since(b.time) > s.opts.MaxAge) {
+			os.Remove(b.path)
+		}
+	}
+
+}
+
+// prepareBackup compresses and encrypts the rotated file unless it already is and returns the path of the result
+func (s *RotatingFileSink) prepareBackup(backup string) string {
+
+	// A file which fails to compress is kept uncompressed, logging the error could write to this sink again
+	if s.opts.Compress && !strings.HasSuffix(backup, ".gz") && !strings.HasSuffix(backup, ".enc") {
+		compressFile(backup)
+		if fileExists(backup + ".gz") {
+			backup += ".gz"
+		}
+	}
+
+	// A file which fails to encrypt is kept as is for the same reason
+	if s.opts.EncryptionKey != nil && !strings.HasSuffix(backup, ".enc") {
+		encryptFile(backup, s.opts.EncryptionKey)
+		if fileExists(backup + ".enc") {
+			backup += ".enc"
+		}
+	}
+
+	return backup
+
+}
+
+type backupFile struct {
+	path string
+	time time.Time
+}
+
+// backups returns the rotated files, the most recent first
+func (s *RotatingFileSink) backups() []backupFile {
+
+	infos, err := ioutil.ReadDir(filepath.Dir(s.path))
+	if err != nil {
+		return nil
+	}
+
+	backups := []backupFile{}
+	for _, info := range infos {
+		t, ok := s.backupTime(info.Name())
+		if info.IsDir() || !ok {
+			continue
+		}
+		backups = append(backups, backupFile{path: filepath.Join(filepath.Dir(s.path), info.Name()), time: t})
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].time.After(backups[j].time)
+	})
+
+	return backups
+
+}
+
+// backupTime returns the time of the rotation of the rotated file with the given name, if it is one
+func (s *RotatingFileSink) backupTime(name string) (time.Time, bool) {
+
+	ext := filepath.Ext(s.path)
+	prefix := filepath.Base(strings.TrimSuffix(s.path, ext)) + "-"
+
+	name = strings.TrimSuffix(strings.TrimSuffix(name, ".enc"), ".gz")
+	if !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ext) {
+		return time.Time{}, false
+	}
+
+	t, err := time.Parse(backupTimeFormat, strings.TrimSuffix(strings.TrimPrefix(name, prefix), ext))
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return t, true
+
+}
+
+func compressFile(path string) error {
+
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+
+	dst, err := os.OpenFile(path+".gz", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(dst)
+	if _, err := io.Copy(gz, src); err != nil {
+		dst.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		dst.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(path + ".gz")
+		return err
+	}
+
+	src.Close()
+	return os.Remove(path)
+
+}
+
+func copyFile(src string, dst string) error {
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+
+	return out.Close()
+
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
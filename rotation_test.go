@@ -0,0 +1,300 @@
+package log_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func rotatedFiles(t *testing.T, dir string) []string {
+	infos, err := ioutil.ReadDir(dir)
+	assert.NoError(t, err, "read-dir")
+	names := []string{}
+	for _, info := range infos {
+		if info.Name() != "app.log" {
+			names = append(names, info.Name())
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func Test_RotatingFileSink_MaxSize(t *testing.T) {
+
+	dir, _ := ioutil.TempDir("", "go-log")
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "app.log")
+
+	sink, err := log.OpenRotatingFileSink(path, log.RotationOptions{MaxSizeMB: 1})
+	assert.NoError(t, err, "open")
+
+	chunk := strings.Repeat("x", 400*1024) + "\n"
+	for i := 0; i < 5; i++ {
+		_, err := sink.Write([]byte(chunk))
+		assert.NoError(t, err, "write")
+	}
+
+	assert.NoError(t, sink.Close(), "close")
+
+	backups := rotatedFiles(t, dir)
+	assert.Len(t, backups, 2, "backups")
+	for _, backup := range backups {
+		assert.Regexp(t, `^app-\d{4}-\d{2}-\d{2}T\d{2}-\d{2}-\d{2}\.\d{3}\.log$`, backup, "name")
+		info, _ := os.Stat(filepath.Join(dir, backup))
+		assert.Equal(t, int64(len(chunk)*2), info.Size(), "backup-size")
+	}
+
+	info, _ := os.Stat(path)
+	assert.Equal(t, int64(len(chunk)), info.Size(), "current-size")
+
+}
+
+func Test_RotatingFileSink_Interval(t *testing.T) {
+
+	dir, _ := ioutil.TempDir("", "go-log")
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "app.log")
+
+	sink, err := log.OpenRotatingFileSink(path, log.RotationOptions{Interval: 20 * time.Millisecond})
+	assert.NoError(t, err, "open")
+
+	sink.Write([]byte("first\n"))
+	time.Sleep(30 * time.Millisecond)
+	sink.Write([]byte("second\n"))
+
+	assert.NoError(t, sink.Close(), "close")
+
+	backups := rotatedFiles(t, dir)
+	assert.Len(t, backups, 1, "backups")
+
+	actual, _ := ioutil.ReadFile(filepath.Join(dir, backups[0]))
+	assert.Equal(t, "first\n", string(actual), "backup")
+
+	actual, _ = ioutil.ReadFile(path)
+	assert.Equal(t, "second\n", string(actual), "current")
+
+}
+
+func Test_RotatingFileSink_MaxBackups(t *testing.T) {
+
+	dir, _ := ioutil.TempDir("", "go-log")
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "app.log")
+
+	sink, err := log.OpenRotatingFileSink(path, log.RotationOptions{MaxBackups: 2})
+	assert.NoError(t, err, "open")
+
+	for i := 0; i < 4; i++ {
+		sink.Write([]byte("entry\n"))
+		assert.NoError(t, sink.Rotate(), "rotate")
+	}
+
+	assert.NoError(t, sink.Close(), "close")
+	assert.Len(t, rotatedFiles(t, dir), 2, "backups")
+
+}
+
+func Test_RotatingFileSink_MaxAge(t *testing.T) {
+
+	dir, _ := ioutil.TempDir("", "go-log")
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "app.log")
+	old := filepath.Join(dir, "app-2000-01-01T00-00-00.000.log")
+	ioutil.WriteFile(old, []byte("old\n"), 0644)
+
+	sink, err := log.OpenRotatingFileSink(path, log.RotationOptions{MaxAge: time.Hour})
+	assert.NoError(t, err, "open")
+
+	sink.Write([]byte("entry\n"))
+	assert.NoError(t, sink.Rotate(), "rotate")
+	assert.NoError(t, sink.Close(), "close")
+
+	backups := rotatedFiles(t, dir)
+	assert.Len(t, backups, 1, "backups")
+	assert.NotEqual(t, filepath.Base(old), backups[0], "old-removed")
+
+}
+
+func Test_RotatingFileSink_Compress(t *testing.T) {
+
+	dir, _ := ioutil.TempDir("", "go-log")
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "app.log")
+
+	sink, err := log.OpenRotatingFileSink(path, log.RotationOptions{Compress: true})
+	assert.NoError(t, err, "open")
+
+	sink.Write([]byte("entry\n"))
+	assert.NoError(t, sink.Rotate(), "rotate")
+	assert.NoError(t, sink.Close(), "close")
+
+	backups := rotatedFiles(t, dir)
+	if assert.Len(t, backups, 1, "backups") {
+		assert.True(t, strings.HasSuffix(backups[0], ".log.gz"), "gz")
+
+		f, err := os.Open(filepath.Join(dir, backups[0]))
+		assert.NoError(t, err, "open-backup")
+		defer f.Close()
+
+		gz, err := gzip.NewReader(f)
+		assert.NoError(t, err, "gzip")
+		actual, _ := ioutil.ReadAll(gz)
+		assert.Equal(t, "entry\n", string(actual), "contents")
+	}
+
+}
+
+func Test_RotatingFileSink_EncryptionKey(t *testing.T) {
+
+	key := bytes.Repeat([]byte{7}, 32)
+
+	var tests = []struct {
+		name     string
+		compress bool
+		suffix   string
+	}{
+		{"plain", false, ".log.enc"},
+		{"compressed", true, ".log.gz.enc"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			dir, _ := ioutil.TempDir("", "go-log")
+			defer os.RemoveAll(dir)
+
+			path := filepath.Join(dir, "app.log")
+
+			sink, err := log.OpenRotatingFileSink(path, log.RotationOptions{Compress: tc.compress, EncryptionKey: key, MaxBackups: 1})
+			assert.NoError(t, err, "open")
+
+			sink.Write([]byte("first\n"))
+			assert.NoError(t, sink.Rotate(), "rotate")
+			sink.Write([]byte("second\n"))
+			assert.NoError(t, sink.Rotate(), "rotate-again")
+			assert.NoError(t, sink.Close(), "close")
+
+			backups := rotatedFiles(t, dir)
+			if assert.Len(t, backups, 1, "backups") {
+				assert.True(t, strings.HasSuffix(backups[0], tc.suffix), "suffix")
+
+				f, err := os.Open(filepath.Join(dir, backups[0]))
+				assert.NoError(t, err, "open-backup")
+				defer f.Close()
+
+				var decrypted bytes.Buffer
+				assert.NoError(t, log.DecryptArchive(&decrypted, f, key), "decrypt")
+
+				var r io.Reader = &decrypted
+				if tc.compress {
+					r, err = gzip.NewReader(r)
+					assert.NoError(t, err, "gzip")
+				}
+				actual, _ := ioutil.ReadAll(r)
+				assert.Equal(t, "second\n", string(actual), "contents")
+			}
+
+		})
+	}
+
+}
+
+func Test_RotatingFileSink_InvalidEncryptionKey(t *testing.T) {
+
+	dir, _ := ioutil.TempDir("", "go-log")
+	defer os.RemoveAll(dir)
+
+	_, err := log.OpenRotatingFileSink(filepath.Join(dir, "app.log"), log.RotationOptions{EncryptionKey: []byte("short")})
+	assert.Error(t, err, "error")
+
+}
+
+func Test_RotatingFileSink_Closed(t *testing.T) {
+
+	dir, _ := ioutil.TempDir("", "go-log")
+	defer os.RemoveAll(dir)
+
+	sink, err := log.OpenRotatingFileSink(filepath.Join(dir, "app.log"), log.RotationOptions{})
+	assert.NoError(t, err, "open")
+	assert.Equal(t, filepath.Join(dir, "app.log"), sink.Path(), "path")
+
+	assert.NoError(t, sink.Close(), "close")
+	assert.NoError(t, sink.Close(), "close-twice")
+
+	_, err = sink.Write([]byte("message\n"))
+	assert.Equal(t, log.ErrSinkClosed, err, "write")
+	assert.Equal(t, log.ErrSinkClosed, sink.Rotate(), "rotate")
+
+}
+
+func Test_ToFile(t *testing.T) {
+
+	resetLogConfig()
+	defer resetLogOutput()
+
+	dir, _ := ioutil.TempDir("", "go-log")
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "logs", "app.log")
+
+	sink, err := log.ToFile(path, log.RotationOptions{FileOptions: log.FileOptions{CreateDirs: true}})
+	assert.NoError(t, err, "open")
+
+	log.Info("info")
+	log.Error("error")
+
+	assert.NoError(t, sink.Close(), "close")
+
+	actual, _ := ioutil.ReadFile(path)
+	assert.Equal(t, "test | INFO  | info\ntest | ERROR | error\n", string(actual), "contents")
+
+}
+
+func Test_RotatingFileSink_CopyTruncate(t *testing.T) {
+
+	dir, _ := ioutil.TempDir("", "go-log")
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "app.log")
+
+	sink, err := log.OpenRotatingFileSink(path, log.RotationOptions{CopyTruncate: true})
+	assert.NoError(t, err, "open")
+
+	// Another process keeping the file open keeps writing to the same file after the rotation
+	other, err := log.OpenFileSink(path, log.FileOptions{})
+	assert.NoError(t, err, "open-other")
+	defer other.Close()
+
+	sink.Write([]byte("first\n"))
+	assert.NoError(t, sink.Rotate(), "rotate")
+	sink.Write([]byte("second\n"))
+	other.Write([]byte("other\n"))
+
+	assert.NoError(t, sink.Close(), "close")
+
+	backups := rotatedFiles(t, dir)
+	if assert.Len(t, backups, 1, "backups") {
+		actual, _ := ioutil.ReadFile(filepath.Join(dir, backups[0]))
+		assert.Equal(t, "first\n", string(actual), "backup")
+	}
+
+	actual, _ := ioutil.ReadFile(path)
+	assert.Equal(t, "second\nother\n", string(actual), "current")
+
+}
@@ -0,0 +1,188 @@
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+)
+
+// TransformMatch selects the entries a transform rule applies to, an empty match selects all entries
+type TransformMatch struct {
+	Levels []string          `json:"levels,omitempty"`
+	Logger string            `json:"logger,omitempty"`
+	Fields map[string]string `json:"fields,omitempty"`
+}
+
+// TransformRule adjusts the entries which match it before they are written
+//
+// Fields are matched against their formatted value, where "*" matches any value as long as the field is present.
+// Logger matches the "logger" field of the entry.
+type TransformRule struct {
+	Name      string                 `json:"name"`
+	Match     TransformMatch         `json:"match"`
+	SetFields map[string]interface{} `json:"set_fields,omitempty"`
+	SetLevel  string                 `json:"set_level,omitempty"`
+	Drop      bool                   `json:"drop,omitempty"`
+	Route     string                 `json:"route,omitempty"`
+}
+
+// TransformRules are evaluated in order against every entry, all matching rules are applied
+//
+// A SetLevel which isn't a known level (see ParseLevel) is ignored, LoadTransformRules and ApplyConfig reject it.
+var TransformRules = []TransformRule{}
+
+// RouteSinks are the named writers transform rules can route entries to, in addition to "stdout" and "stderr"
+var RouteSinks = map[string]io.Writer{}
+
+// LoadTransformRules reads a JSON array of transform rules from path, the levels of the rules are parsed with
+// ParseLevel and an error is returned for unknown levels
+func LoadTransformRules(path string) ([]TransformRule, error) {
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var rules []TransformRule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, err
+	}
+
+	return normalizeTransformRules(rules)
+
+}
+
+// normalizeTransformRules returns a copy of the rules with their levels replaced by the labels shown in the entries
+// (e.g. "warning" becomes "WARN")
+func normalizeTransformRules(rules []TransformRule) ([]TransformRule, error) {
+
+	normalized := make([]TransformRule, 0, len(rules))
+
+	for _, rule := range rules {
+
+		if rule.SetLevel != "" {
+			level, err := ParseLevel(rule.SetLevel)
+			if err != nil {
+				return nil, fmt.Errorf("transform rule %q: %v", rule.Name, err)
+			}
+			rule.SetLevel = level.String()
+		}
+
+		if len(rule.Match.Levels) > 0 {
+			levels := make([]string, 0, len(rule.Match.Levels))
+			for _, name := range rule.Match.Levels {
+				level, err := ParseLevel(name)
+				if err != nil {
+					return nil, fmt.Errorf("transform rule %q: %v", rule.Name, err)
+				}
+				levels = append(levels, level.String())
+			}
+			rule.Match.Levels = levels
+		}
+
+		normalized = append(normalized, rule)
+
+	}
+
+	return normalized, nil
+
+}
+
+func (m TransformMatch) matches(e entry) bool {
+
+	if len(m.Levels) > 0 {
+		found := false
+		for _, level := range m.Levels {
+			if strings.TrimSpace(strings.ToUpper(level)) == e.level {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if m.Logger != "" && !matchFieldValue(e.fields, "logger", m.Logger) {
+		return false
+	}
+
+	for name, value := range m.Fields {
+		if !matchFieldValue(e.fields, name, value) {
+			return false
+		}
+	}
+
+	return true
+
+}
+
+func matchFieldValue(fields map[string]interface{}, name string, expected string) bool {
+	value, ok := fields[name]
+	if !ok {
+		return false
+	}
+	return expected == "*" || fmt.Sprint(value) == expected
+}
+
+// applyTransformRules returns the transformed entry, the name of the sink it is routed to and false if it is dropped
+func applyTransformRules(e entry) (entry, string, bool) {
+
+	route := ""
+	copied := false
+
+	for _, rule := range TransformRules {
+
+		if !rule.Match.matches(e) {
+			continue
+		}
+
+		if rule.Drop {
+			return e, route, false
+		}
+
+		if len(rule.SetFields) > 0 {
+			if !copied {
+				fields := make(map[string]interface{}, len(e.fields)+len(rule.SetFields))
+				for key, value := range e.fields {
+					fields[key] = value
+				}
+				e.fields = fields
+				copied = true
+			}
+			for key, value := range rule.SetFields {
+				e.fields[key] = value
+			}
+		}
+
+		if level, err := ParseLevel(rule.SetLevel); rule.SetLevel != "" && err == nil {
+			e.level = level.String()
+			if !e.logger.levelEnabled(e.level) {
+				return e, route, false
+			}
+		}
+
+		if rule.Route != "" {
+			route = rule.Route
+		}
+
+	}
+
+	return e, route, true
+
+}
+
+func writerForRoute(l *Logger, route string, level string) (io.Writer, string) {
+	switch route {
+	case "stdout":
+		return l.stdout(), route
+	case "stderr":
+		return l.stderr(), route
+	}
+	if w, ok := RouteSinks[route]; ok && w != nil {
+		return w, route
+	}
+	return l.streamForLevel(level)
+}
@@ -0,0 +1,160 @@
+package log_test
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func resetTransformRules() {
+	log.TransformRules = []log.TransformRule{}
+	log.RouteSinks = map[string]io.Writer{}
+}
+
+func Test_TransformRules(t *testing.T) {
+
+	type test struct {
+		name   string
+		rules  []log.TransformRule
+		stdout string
+		stderr string
+	}
+
+	var tests = []test{
+		{
+			"no-match",
+			[]log.TransformRule{{Match: log.TransformMatch{Levels: []string{"error"}}, Drop: true}},
+			"test | INFO  | user john msg.template=\"user {user}\" user=john\n",
+			"",
+		},
+		{
+			"drop",
+			[]log.TransformRule{{Match: log.TransformMatch{Fields: map[string]string{"user": "john"}}, Drop: true}},
+			"",
+			"",
+		},
+		{
+			"set-field",
+			[]log.TransformRule{{Match: log.TransformMatch{Fields: map[string]string{"user": "*"}}, SetFields: map[string]interface{}{"team": "core"}}},
+			"test | INFO  | user john msg.template=\"user {user}\" team=core user=john\n",
+			"",
+		},
+		{
+			"set-level",
+			[]log.TransformRule{{Match: log.TransformMatch{Levels: []string{"INFO"}}, SetLevel: "error"}},
+			"",
+			"test | ERROR | user john msg.template=\"user {user}\" user=john\n",
+		},
+		{
+			"set-level-alias",
+			[]log.TransformRule{{Match: log.TransformMatch{Levels: []string{"INFO"}}, SetLevel: "warning"}},
+			"test | WARN  | user john msg.template=\"user {user}\" user=john\n",
+			"",
+		},
+		{
+			"set-level-unknown",
+			[]log.TransformRule{{Match: log.TransformMatch{Levels: []string{"INFO"}}, SetLevel: "loud"}},
+			"test | INFO  | user john msg.template=\"user {user}\" user=john\n",
+			"",
+		},
+		{
+			"set-level-filtered",
+			[]log.TransformRule{{Match: log.TransformMatch{Levels: []string{"INFO"}}, SetLevel: "debug"}},
+			"",
+			"",
+		},
+		{
+			"route",
+			[]log.TransformRule{{Match: log.TransformMatch{Fields: map[string]string{"user": "john"}}, Route: "stderr"}},
+			"",
+			"test | INFO  | user john msg.template=\"user {user}\" user=john\n",
+		},
+		{
+			"all-matching",
+			[]log.TransformRule{
+				{SetFields: map[string]interface{}{"a": 1}},
+				{SetFields: map[string]interface{}{"b": 2}},
+			},
+			"test | INFO  | user john a=1 b=2 msg.template=\"user {user}\" user=john\n",
+			"",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			resetLogConfig()
+			stdout, stderr := redirectOutput()
+			defer resetLogOutput()
+			defer resetTransformRules()
+
+			log.TransformRules = tc.rules
+
+			fields := map[string]interface{}{"user": "john"}
+			log.InfoTemplate("user {user}", fields)
+
+			assert.Equal(t, tc.stdout, stdout.String(), "stdout")
+			assert.Equal(t, tc.stderr, stderr.String(), "stderr")
+			assert.Len(t, fields, 1, "fields-unchanged")
+
+		})
+	}
+
+}
+
+func Test_TransformRules_RouteSink(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+	defer resetTransformRules()
+
+	audit := &bytes.Buffer{}
+	log.RouteSinks["audit"] = audit
+	log.TransformRules = []log.TransformRule{
+		{Match: log.TransformMatch{Logger: "audit"}, Route: "audit"},
+	}
+
+	log.InfoTemplate("login by {user}", map[string]interface{}{"logger": "audit", "user": "john"})
+	log.Info("regular")
+
+	assert.Equal(t, "test | INFO  | regular\n", stdout.String(), "stdout")
+	assert.Equal(t, "test | INFO  | login by john logger=audit msg.template=\"login by {user}\" user=john\n", audit.String(), "audit")
+
+}
+
+func Test_LoadTransformRules(t *testing.T) {
+
+	dir, _ := ioutil.TempDir("", "go-log")
+	defer os.RemoveAll(dir)
+
+	path := writeConfigFile(t, dir, `[{"name":"quiet","match":{"levels":["warning"],"fields":{"component":"cache"}},"set_level":"debug"}]`)
+
+	rules, err := log.LoadTransformRules(path)
+
+	assert.NoError(t, err, "error")
+	assert.Equal(t, []log.TransformRule{{
+		Name:     "quiet",
+		Match:    log.TransformMatch{Levels: []string{"WARN"}, Fields: map[string]string{"component": "cache"}},
+		SetLevel: "DEBUG",
+	}}, rules, "rules")
+
+	_, err = log.LoadTransformRules(writeConfigFile(t, dir, "not json"))
+	assert.Error(t, err, "invalid")
+
+	_, err = log.LoadTransformRules(writeConfigFile(t, dir, `[{"name":"louder","set_level":"loud"}]`))
+	assert.EqualError(t, err, `transform rule "louder": unknown level: "loud"`, "unknown-set-level")
+
+	_, err = log.LoadTransformRules(writeConfigFile(t, dir, `[{"name":"quiet","match":{"levels":["verbose"]},"drop":true}]`))
+	assert.EqualError(t, err, `transform rule "quiet": unknown level: "verbose"`, "unknown-match-level")
+
+	_, err = log.LoadTransformRules("missing.json")
+	assert.Error(t, err, "missing")
+
+}
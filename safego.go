@@ -0,0 +1,24 @@
+package log
+
+// SafeGo runs fn in a new goroutine which recovers from and logs any panic (see Recover)
+//
+// The optional restart callbacks are called after a panic was logged, e.g. to start the worker again:
+//
+//	var worker func()
+//	worker = func() {
+//		log.SafeGo(run, worker)
+//	}
+//	worker()
+func SafeGo(fn func(), restart ...func()) {
+	go func() {
+		defer func() {
+			if r := recover(); r != nil && !exitOnFatal(r) {
+				logPanic(r)
+				for _, callback := range restart {
+					callback()
+				}
+			}
+		}()
+		fn()
+	}()
+}
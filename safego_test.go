@@ -0,0 +1,61 @@
+package log_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func Test_SafeGo_Panic(t *testing.T) {
+
+	resetLogConfig()
+	redirectOutput()
+	defer resetLogOutput()
+
+	stderr := &syncBuffer{}
+	log.Stderr = stderr
+
+	restarted := make(chan struct{})
+	log.SafeGo(func() {
+		panic("boom")
+	}, func() {
+		close(restarted)
+	})
+
+	select {
+	case <-restarted:
+	case <-time.After(time.Second):
+		t.Fatal("restart callback not called")
+	}
+
+	assert.True(t, strings.HasPrefix(stderr.String(), "test | ERROR | Recovered from panic: boom\n"), "stderr")
+	assert.Contains(t, stderr.String(), "recovered=true", "field")
+
+}
+
+func Test_SafeGo_NoPanic(t *testing.T) {
+
+	resetLogConfig()
+	redirectOutput()
+	defer resetLogOutput()
+
+	stderr := &syncBuffer{}
+	log.Stderr = stderr
+
+	done := make(chan struct{})
+	log.SafeGo(func() {
+		close(done)
+	}, func() {
+		t.Error("restart callback called without panic")
+	})
+
+	<-done
+	time.Sleep(10 * time.Millisecond)
+
+	assert.Equal(t, "", stderr.String())
+
+}
@@ -0,0 +1,201 @@
+package log
+
+import (
+	"sync"
+	"time"
+)
+
+// Sampler decides which entries are written, to keep noisy log lines from flooding the outputs
+//
+// Sample is called for every enabled entry before it is formatted and must be safe for concurrent use. The entries
+// it rejects are not written, recorded or passed to the hooks.
+type Sampler interface {
+	Sample(level Level, message string) bool
+}
+
+// SampleKey defines which entries the built-in samplers count together
+type SampleKey int
+
+const (
+	// SampleByMessage counts the entries with the same level and message together
+	SampleByMessage SampleKey = iota
+
+	// SampleByLevel counts the entries with the same level together, for messages which include changing values
+	SampleByLevel
+)
+
+// maxSampleKeys is the number of keys after which the built-in samplers remove the state of the keys which expired
+const maxSampleKeys = 10000
+
+var samplerMutex = &sync.RWMutex{}
+var sampler Sampler
+
+// SetSampler installs the sampler (nil removes it)
+func SetSampler(s Sampler) {
+	samplerMutex.Lock()
+	sampler = s
+	samplerMutex.Unlock()
+}
+
+func currentSampler() Sampler {
+	samplerMutex.RLock()
+	defer samplerMutex.RUnlock()
+	return sampler
+}
+
+func sampleKey(by SampleKey, level Level, message string) string {
+	if by == SampleByLevel {
+		return level.String()
+	}
+	return level.String() + "|" + message
+}
+
+// FirstNSampler writes the first entries for each key and every Mth entry after that, the counts restart every tick
+type FirstNSampler struct {
+	mutex      sync.Mutex
+	first      int
+	thereafter int
+	tick       time.Duration
+	by         SampleKey
+	counts     map[string]*sampleCount
+	dropped    uint64
+}
+
+type sampleCount struct {
+	start time.Time
+	count int
+}
+
+// NewFirstNSampler creates a sampler which writes the first entries per key and every thereafter-th entry after that
+//
+// A thereafter of 0 drops all entries after the first ones, a tick of 0 never restarts the counts (and keeps the
+// count of every key).
+func NewFirstNSampler(first int, thereafter int, tick time.Duration, by SampleKey) *FirstNSampler {
+	return &FirstNSampler{
+		first:      first,
+		thereafter: thereafter,
+		tick:       tick,
+		by:         by,
+		counts:     map[string]*sampleCount{},
+	}
+}
+
+// Sample returns true if the entry should be written
+func (s *FirstNSampler) Sample(level Level, message string) bool {
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	key := sampleKey(s.by, level, message)
+
+	c, ok := s.counts[key]
+	if !ok || (s.tick > 0 && now.Sub(c.start) >= s.tick) {
+		if s.tick > 0 && len(s.counts) >= maxSampleKeys {
+			s.expire(now)
+		}
+		c = &sampleCount{start: now}
+		s.counts[key] = c
+	}
+
+	c.count++
+	if c.count <= s.first || (s.thereafter > 0 && (c.count-s.first)%s.thereafter == 0) {
+		return true
+	}
+
+	s.dropped++
+	return false
+
+}
+
+// Dropped returns the number of entries which were rejected
+func (s *FirstNSampler) Dropped() uint64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.dropped
+}
+
+// expire removes the counts of the previous ticks so that unique messages don't accumulate
+func (s *FirstNSampler) expire(now time.Time) {
+	for key, c := range s.counts {
+		if now.Sub(c.start) >= s.tick {
+			delete(s.counts, key)
+		}
+	}
+}
+
+// TokenBucketSampler writes the entries for each key at a maximum rate, allowing bursts
+type TokenBucketSampler struct {
+	mutex   sync.Mutex
+	rate    float64
+	burst   int
+	by      SampleKey
+	buckets map[string]*tokenBucket
+	dropped uint64
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewTokenBucketSampler creates a sampler which writes up to rate entries per second per key, with bursts of up to
+// burst entries
+func NewTokenBucketSampler(rate float64, burst int, by SampleKey) *TokenBucketSampler {
+	return &TokenBucketSampler{
+		rate:    rate,
+		burst:   burst,
+		by:      by,
+		buckets: map[string]*tokenBucket{},
+	}
+}
+
+// Sample returns true if the entry should be written
+func (s *TokenBucketSampler) Sample(level Level, message string) bool {
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	key := sampleKey(s.by, level, message)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		if len(s.buckets) >= maxSampleKeys {
+			s.expire(now)
+		}
+		b = &tokenBucket{tokens: float64(s.burst), last: now}
+		s.buckets[key] = b
+	}
+
+	b.tokens += now.Sub(b.last).Seconds() * s.rate
+	if b.tokens > float64(s.burst) {
+		b.tokens = float64(s.burst)
+	}
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true
+	}
+
+	s.dropped++
+	return false
+
+}
+
+// Dropped returns the number of entries which were rejected
+func (s *TokenBucketSampler) Dropped() uint64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.dropped
+}
+
+// expire removes the buckets which refilled since they were last used, they behave the same as new ones
+func (s *TokenBucketSampler) expire(now time.Time) {
+	for key, b := range s.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*s.rate >= float64(s.burst) {
+			delete(s.buckets, key)
+		}
+	}
+}
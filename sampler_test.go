@@ -0,0 +1,116 @@
+package log_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func Test_FirstNSampler(t *testing.T) {
+
+	type test struct {
+		name       string
+		first      int
+		thereafter int
+		expected   []int
+	}
+
+	var tests = []test{
+		{"first-only", 2, 0, []int{1, 2}},
+		{"thereafter", 2, 3, []int{1, 2, 5, 8}},
+		{"every", 0, 1, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			s := log.NewFirstNSampler(tc.first, tc.thereafter, 0, log.SampleByMessage)
+
+			actual := []int{}
+			for i := 1; i <= 10; i++ {
+				if s.Sample(log.DebugLevel, "message") {
+					actual = append(actual, i)
+				}
+			}
+
+			assert.Equal(t, tc.expected, actual, "sampled")
+			assert.Equal(t, uint64(10-len(tc.expected)), s.Dropped(), "dropped")
+
+		})
+	}
+
+}
+
+func Test_FirstNSampler_Keys(t *testing.T) {
+
+	byMessage := log.NewFirstNSampler(1, 0, 0, log.SampleByMessage)
+	assert.True(t, byMessage.Sample(log.DebugLevel, "first"), "first")
+	assert.True(t, byMessage.Sample(log.DebugLevel, "second"), "second")
+	assert.True(t, byMessage.Sample(log.InfoLevel, "first"), "other-level")
+	assert.False(t, byMessage.Sample(log.DebugLevel, "first"), "repeated")
+
+	byLevel := log.NewFirstNSampler(1, 0, 0, log.SampleByLevel)
+	assert.True(t, byLevel.Sample(log.DebugLevel, "first"), "first")
+	assert.False(t, byLevel.Sample(log.DebugLevel, "second"), "second")
+	assert.True(t, byLevel.Sample(log.InfoLevel, "first"), "other-level")
+
+}
+
+func Test_FirstNSampler_Tick(t *testing.T) {
+
+	s := log.NewFirstNSampler(1, 0, 20*time.Millisecond, log.SampleByMessage)
+
+	assert.True(t, s.Sample(log.DebugLevel, "message"), "first")
+	assert.False(t, s.Sample(log.DebugLevel, "message"), "second")
+
+	time.Sleep(30 * time.Millisecond)
+
+	assert.True(t, s.Sample(log.DebugLevel, "message"), "next-tick")
+
+}
+
+func Test_TokenBucketSampler(t *testing.T) {
+
+	s := log.NewTokenBucketSampler(50, 2, log.SampleByLevel)
+
+	assert.True(t, s.Sample(log.DebugLevel, "first"), "first")
+	assert.True(t, s.Sample(log.DebugLevel, "second"), "second")
+	assert.False(t, s.Sample(log.DebugLevel, "third"), "third")
+	assert.True(t, s.Sample(log.InfoLevel, "first"), "other-level")
+	assert.Equal(t, uint64(1), s.Dropped(), "dropped")
+
+	time.Sleep(30 * time.Millisecond)
+
+	assert.True(t, s.Sample(log.DebugLevel, "refilled"), "refilled")
+
+}
+
+func Test_SetSampler(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+	defer log.SetSampler(nil)
+
+	log.DebugMode = true
+	log.SetSampler(log.NewFirstNSampler(2, 0, 0, log.SampleByMessage))
+
+	for i := 0; i < 5; i++ {
+		log.Debug("hot loop")
+		log.Info("item " + strconv.Itoa(i))
+	}
+
+	assert.Equal(t, 2, strings.Count(stdout.String(), "hot loop"), "debug")
+	assert.Equal(t, 5, strings.Count(stdout.String(), "item"), "info")
+
+	log.SetSampler(nil)
+	log.Debug("hot loop")
+
+	assert.Equal(t, 3, strings.Count(stdout.String(), "hot loop"), "removed")
+
+}
@@ -0,0 +1,96 @@
+package log
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ScheduledEscalateAfter is the number of consecutive failures after which the failures of a scheduled job are
+// logged as errors instead of warnings
+var ScheduledEscalateAfter = 3
+
+// ScheduledJob logs the runs of a job which is started by a scheduler
+//
+// It implements the Job interface of robfig/cron (a Run method), Run can also be passed as a plain func().
+type ScheduledJob struct {
+	// NextRun returns the time of the next run, it is used to add the "next_run" field when set
+	NextRun func() time.Time
+
+	name     string
+	fn       func() error
+	mutex    sync.Mutex
+	failures int
+}
+
+// Scheduled wraps fn so that each run logs its start, outcome, duration and next run with consistent fields
+//
+// Failed runs are logged as warnings until ScheduledEscalateAfter consecutive runs failed, from then on they are
+// logged as errors. Panics are recovered and count as failures.
+func Scheduled(name string, fn func() error) *ScheduledJob {
+	return &ScheduledJob{
+		name: name,
+		fn:   fn,
+	}
+}
+
+// Run runs the job once
+func (j *ScheduledJob) Run() {
+
+	if DebugMode || isRecording() {
+		printEntry("DEBUG", "Scheduled run started", map[string]interface{}{"job": j.name})
+	}
+
+	start := time.Now()
+	err := j.call()
+	duration := time.Since(start)
+
+	j.mutex.Lock()
+	previousFailures := j.failures
+	if err != nil {
+		j.failures++
+	} else {
+		j.failures = 0
+	}
+	failures := j.failures
+	j.mutex.Unlock()
+
+	fields := map[string]interface{}{
+		"job":      j.name,
+		"duration": duration,
+	}
+	if j.NextRun != nil {
+		fields["next_run"] = j.NextRun().Format(time.RFC3339)
+	}
+
+	if err == nil {
+		fields["outcome"] = "success"
+		message := "Scheduled run succeeded"
+		if previousFailures > 0 {
+			fields["previous_failures"] = previousFailures
+			message = "Scheduled run recovered"
+		}
+		printEntry("INFO", message, fields)
+		return
+	}
+
+	fields["outcome"] = "failure"
+	fields["error"] = err.Error()
+	fields["consecutive_failures"] = failures
+
+	if ScheduledEscalateAfter > 0 && failures >= ScheduledEscalateAfter {
+		printEntry("ERROR", "Scheduled run failed", fields)
+	} else {
+		printEntry("WARN", "Scheduled run failed", fields)
+	}
+
+}
+
+func (j *ScheduledJob) call() (err error) {
+	defer func() {
+		if r := recover(); r != nil && !exitOnFatal(r) {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return j.fn()
+}
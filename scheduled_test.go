@@ -0,0 +1,61 @@
+package log_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func Test_Scheduled(t *testing.T) {
+
+	resetLogConfig()
+	stdout, stderr := redirectOutput()
+	defer resetLogOutput()
+
+	outcomes := []error{nil, errors.New("timeout"), errors.New("timeout"), errors.New("timeout"), nil}
+	run := 0
+
+	job := log.Scheduled("cleanup", func() error {
+		err := outcomes[run]
+		run++
+		return err
+	})
+	job.NextRun = func() time.Time {
+		return time.Date(2020, 3, 4, 12, 0, 0, 0, time.UTC)
+	}
+
+	for range outcomes {
+		job.Run()
+	}
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+
+	assert.Len(t, lines, 4, "stdout-lines")
+	assert.Regexp(t, `^test \| INFO  \| Scheduled run succeeded duration=\S+ job=cleanup next_run=2020-03-04T12:00:00Z outcome=success$`, lines[0], "success")
+	assert.Regexp(t, `^test \| WARN  \| Scheduled run failed consecutive_failures=1 duration=\S+ error=timeout job=cleanup next_run=2020-03-04T12:00:00Z outcome=failure$`, lines[1], "warn-1")
+	assert.Regexp(t, `consecutive_failures=2 `, lines[2], "warn-2")
+	assert.Regexp(t, `^test \| INFO  \| Scheduled run recovered duration=\S+ job=cleanup next_run=2020-03-04T12:00:00Z outcome=success previous_failures=3$`, lines[3], "recovered")
+	assert.Regexp(t, `^test \| ERROR \| Scheduled run failed consecutive_failures=3 duration=\S+ error=timeout job=cleanup next_run=2020-03-04T12:00:00Z outcome=failure\n$`, stderr.String(), "escalated")
+
+}
+
+func Test_Scheduled_Panic(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+
+	log.DebugMode = true
+
+	log.Scheduled("report", func() error {
+		panic("boom")
+	}).Run()
+
+	assert.Regexp(t, `^test \| DEBUG \| Scheduled run started job=report\ntest \| WARN  \| Scheduled run failed consecutive_failures=1 duration=\S+ error="panic: boom" job=report outcome=failure\n$`, stdout.String())
+
+}
@@ -0,0 +1,12 @@
+package log
+
+// SchemaVersion is the version of the structured outputs, written as SchemaVersionField in the Datadog entries,
+// events, metrics, scrub reports, recordings and dead letters
+//
+// Within a schema version, keys are only ever added: existing keys are not renamed, removed or given a different
+// type, so parsers written against a version keep working when the library is upgraded. Any other change to the
+// structured outputs increments the version.
+const SchemaVersion = 1
+
+// SchemaVersionField is the key holding the SchemaVersion in the structured outputs
+const SchemaVersionField = "schema_version"
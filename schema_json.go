@@ -0,0 +1,98 @@
+package log
+
+// The JSON Schemas (draft-07) of the structured outputs for SchemaVersion, see logtest.ValidateJSON for validating
+// output against them in tests
+
+// DatadogSchema is the JSON Schema of the entries written in the Datadog format
+const DatadogSchema = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "$id": "https://github.com/pieterclaerhout/go-log/schema/v1/datadog.json",
+  "title": "Datadog log entry",
+  "type": "object",
+  "required": ["status", "message", "schema_version"],
+  "properties": {
+    "timestamp": {"type": "string", "format": "date-time"},
+    "status": {"type": "string", "enum": ["debug", "info", "notice", "warn", "error", "critical"]},
+    "message": {"type": "string"},
+    "schema_version": {"const": 1},
+    "service": {"type": "string"},
+    "env": {"type": "string"},
+    "version": {"type": "string"},
+    "logger.method_name": {"type": "string"},
+    "logger.thread_name": {"type": "string"},
+    "entry.id": {"type": "string"},
+    "extra_redacted_count": {"type": "integer", "minimum": 1}
+  },
+  "additionalProperties": true
+}`
+
+// EventSchema is the JSON Schema of the lines written by Event
+const EventSchema = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "$id": "https://github.com/pieterclaerhout/go-log/schema/v1/event.json",
+  "title": "Event",
+  "type": "object",
+  "required": ["time", "event", "properties", "schema_version"],
+  "properties": {
+    "time": {"type": "string", "format": "date-time"},
+    "event": {"type": "string"},
+    "properties": {"type": "object"},
+    "schema_version": {"const": 1}
+  },
+  "additionalProperties": false
+}`
+
+// MetricSchema is the JSON Schema of the lines written by Metric and MetricCount
+const MetricSchema = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "$id": "https://github.com/pieterclaerhout/go-log/schema/v1/metric.json",
+  "title": "Metric",
+  "type": "object",
+  "required": ["time", "metric", "value", "type", "tags", "schema_version"],
+  "properties": {
+    "time": {"type": "string", "format": "date-time"},
+    "metric": {"type": "string"},
+    "value": {"type": "number"},
+    "type": {"type": "string", "enum": ["gauge", "count"]},
+    "tags": {"type": "array", "items": {"type": "string"}},
+    "schema_version": {"const": 1}
+  },
+  "additionalProperties": false
+}`
+
+// RecordedEntrySchema is the JSON Schema of the lines written by StartRecording and to the DeadLetter
+const RecordedEntrySchema = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "$id": "https://github.com/pieterclaerhout/go-log/schema/v1/recorded-entry.json",
+  "title": "Recorded entry",
+  "type": "object",
+  "required": ["time", "level", "message", "schema_version"],
+  "properties": {
+    "time": {"type": "string", "format": "date-time"},
+    "level": {"type": "string", "enum": ["TRACE", "DEBUG", "INFO", "NOTE", "WARN", "ERROR", "FATAL"]},
+    "message": {"type": "string"},
+    "fields": {"type": "object"},
+    "caller": {"type": "string"},
+    "goroutine": {"type": "integer", "minimum": 1},
+    "schema_version": {"const": 1}
+  },
+  "additionalProperties": false
+}`
+
+// ScrubReportSchema is the JSON Schema of the lines written to the ScrubReport
+const ScrubReportSchema = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "$id": "https://github.com/pieterclaerhout/go-log/schema/v1/scrub-report.json",
+  "title": "Scrub report",
+  "type": "object",
+  "required": ["time", "rule", "level", "caller", "matches", "schema_version"],
+  "properties": {
+    "time": {"type": "string", "format": "date-time"},
+    "rule": {"type": "string"},
+    "level": {"type": "string"},
+    "caller": {"type": "string"},
+    "matches": {"type": "integer", "minimum": 1},
+    "schema_version": {"const": 1}
+  },
+  "additionalProperties": false
+}`
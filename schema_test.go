@@ -0,0 +1,92 @@
+package log_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func jsonKeys(t *testing.T, data []byte) ([]string, map[string]interface{}) {
+	var actual map[string]interface{}
+	err := json.Unmarshal(data, &actual)
+	assert.NoError(t, err, "json")
+	keys := []string{}
+	for key := range actual {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys, actual
+}
+
+// Test_SchemaVersion pins the keys of the structured outputs, keys may only be added within a schema version
+func Test_SchemaVersion(t *testing.T) {
+
+	type test struct {
+		name     string
+		write    func()
+		expected []string
+	}
+
+	var tests = []test{
+		{
+			"datadog",
+			func() {
+				log.Format = log.FormatDatadog
+				log.Info("message")
+			},
+			[]string{"message", "schema_version", "status", "timestamp"},
+		},
+		{
+			"event",
+			func() {
+				log.Event("signup", map[string]interface{}{"plan": "pro"})
+			},
+			[]string{"event", "properties", "schema_version", "time"},
+		},
+		{
+			"metric",
+			func() {
+				log.Metric("queue.depth", 3, map[string]string{"queue": "emails"})
+			},
+			[]string{"metric", "schema_version", "tags", "time", "type", "value"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			resetLogConfig()
+			stdout, _ := redirectOutput()
+			defer resetLogOutput()
+			defer resetAllowListConfig()
+
+			tc.write()
+
+			keys, actual := jsonKeys(t, bytes.TrimSpace(stdout.Bytes()))
+			assert.Equal(t, tc.expected, keys, "keys")
+			assert.Equal(t, float64(log.SchemaVersion), actual[log.SchemaVersionField], "schema-version")
+
+		})
+	}
+
+}
+
+func Test_SchemaVersion_Replay(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+
+	log.PrintTimestamp = false
+
+	_, err := log.Replay(bytes.NewBufferString(`{"time":"2019-01-01T12:00:00Z","level":"INFO","message":"replayed","schema_version":1}` + "\n"))
+
+	assert.NoError(t, err, "replay")
+	assert.Equal(t, "replayed\n", stdout.String(), "output")
+
+}
@@ -0,0 +1,190 @@
+package log
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"regexp"
+	"time"
+	"unicode"
+)
+
+// DefaultScrubReplacement is the replacement used for scrub rules which don't define one
+var DefaultScrubReplacement = "[REDACTED]"
+
+// ScrubHashKey is the key used for the HMAC of the ScrubHash strategy
+//
+// It defaults to a random key generated when the process starts, so that the hashes can be correlated within the
+// process but low-entropy values (such as card or phone numbers) can't be recovered by hashing the candidates. Set
+// a secret key shared by the processes whose hashes have to be correlated. While it's empty, the values matched by
+// ScrubHash rules are replaced with DefaultScrubReplacement instead.
+var ScrubHashKey = randomScrubHashKey()
+
+func randomScrubHashKey() []byte {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return []byte{}
+	}
+	return key
+}
+
+// ScrubStrategy defines how a matched value is scrubbed
+type ScrubStrategy int
+
+const (
+	// ScrubMask replaces the matched value with the replacement of the rule, which can refer to the groups of the
+	// pattern (e.g. "$1****")
+	ScrubMask ScrubStrategy = iota
+
+	// ScrubHash replaces the matched value with a stable HMAC-SHA256 hash keyed with ScrubHashKey, so that it can still
+	// be correlated
+	ScrubHash
+
+	// ScrubPartial masks all letters and digits of the matched value except for the last KeepLast ones, keeping
+	// separators in place (e.g. "****-****-****-1234")
+	ScrubPartial
+)
+
+// ScrubRule redacts the parts of a log message which match its pattern
+type ScrubRule struct {
+	Name        string
+	Pattern     *regexp.Regexp
+	Strategy    ScrubStrategy
+	Replacement string
+	KeepLast    int
+}
+
+// ScrubRules are the rules which are applied to every log message and to the string values of its fields
+var ScrubRules = []ScrubRule{}
+
+// ScrubDryRun indicates if the scrub rules should only report where they would have matched instead of redacting
+var ScrubDryRun = false
+
+// ScrubReport is the writer to where the dry run matches are reported (defaults to Stderr when nil)
+var ScrubReport io.Writer
+
+type scrubReportEntry struct {
+	Time          string `json:"time"`
+	Rule          string `json:"rule"`
+	Level         string `json:"level"`
+	Caller        string `json:"caller"`
+	Matches       int    `json:"matches"`
+	SchemaVersion int    `json:"schema_version"`
+}
+
+func scrubMessage(level string, message string) string {
+
+	for _, rule := range ScrubRules {
+
+		if rule.Pattern == nil {
+			continue
+		}
+
+		if ScrubDryRun {
+			if matches := rule.Pattern.FindAllStringIndex(message, -1); len(matches) > 0 {
+				reportScrubMatch(rule, level, len(matches))
+			}
+			continue
+		}
+
+		if rule.Strategy == ScrubMask {
+			// The replacement is a template, so that it can keep parts of the match (e.g. "$1****")
+			message = rule.Pattern.ReplaceAllString(message, rule.replacement())
+		} else {
+			message = rule.Pattern.ReplaceAllStringFunc(message, rule.scrub)
+		}
+
+	}
+
+	return message
+
+}
+
+// scrubFields applies the rules to the string values of the fields, which are copied when one of them changes
+func scrubFields(level string, fields map[string]interface{}) map[string]interface{} {
+
+	var scrubbed map[string]interface{}
+
+	for key, value := range fields {
+		s, ok := value.(string)
+		if !ok {
+			continue
+		}
+		if replaced := scrubMessage(level, s); replaced != s {
+			if scrubbed == nil {
+				scrubbed = mergeFields(fields, nil)
+			}
+			scrubbed[key] = replaced
+		}
+	}
+
+	if scrubbed == nil {
+		return fields
+	}
+
+	return scrubbed
+
+}
+
+func reportScrubMatch(rule ScrubRule, level string, matches int) {
+
+	w := ScrubReport
+	if w == nil {
+		w = Stderr
+	}
+
+	caller := ""
+	if frame, ok := findCaller(CallerSkip); ok {
+		caller = formatFrame(frame, CallerFull)
+	}
+
+	now := time.Now()
+	writeJSONLine("scrub-report", w, now, scrubReportEntry{
+		Time:          now.UTC().Format(time.RFC3339Nano),
+		Rule:          rule.Name,
+		Level:         level,
+		Caller:        caller,
+		Matches:       matches,
+		SchemaVersion: SchemaVersion,
+	})
+
+}
+
+func (rule ScrubRule) scrub(value string) string {
+	switch rule.Strategy {
+	case ScrubHash:
+		if len(ScrubHashKey) == 0 {
+			return DefaultScrubReplacement
+		}
+		mac := hmac.New(sha256.New, ScrubHashKey)
+		mac.Write([]byte(value))
+		return "[hash:" + hex.EncodeToString(mac.Sum(nil))[:16] + "]"
+	default:
+		return maskPartial(value, rule.KeepLast)
+	}
+}
+
+func (rule ScrubRule) replacement() string {
+	if rule.Replacement == "" {
+		return DefaultScrubReplacement
+	}
+	return rule.Replacement
+}
+
+func maskPartial(value string, keepLast int) string {
+	runes := []rune(value)
+	kept := 0
+	for i := len(runes) - 1; i >= 0; i-- {
+		if !unicode.IsLetter(runes[i]) && !unicode.IsDigit(runes[i]) {
+			continue
+		}
+		if kept < keepLast {
+			kept++
+			continue
+		}
+		runes[i] = '*'
+	}
+	return string(runes)
+}
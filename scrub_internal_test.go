@@ -0,0 +1,32 @@
+package log
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_maskPartial(t *testing.T) {
+
+	type test struct {
+		name     string
+		value    string
+		keepLast int
+		expected string
+	}
+
+	var tests = []test{
+		{"card", "1234-5678-9012-3456", 4, "****-****-****-3456"},
+		{"none-kept", "abc-def", 0, "***-***"},
+		{"all-kept", "abc", 5, "abc"},
+		{"unicode", "ünïcode", 2, "*****de"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := maskPartial(tc.value, tc.keepLast)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+
+}
@@ -0,0 +1,149 @@
+package log_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+var emailRule = log.ScrubRule{
+	Name:    "email",
+	Pattern: regexp.MustCompile(`[a-z]+@[a-z]+\.com`),
+}
+
+func resetScrubConfig() {
+	log.ScrubRules = []log.ScrubRule{}
+	log.ScrubDryRun = false
+	log.ScrubReport = nil
+}
+
+func Test_Scrub(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+	defer resetScrubConfig()
+
+	log.ScrubRules = []log.ScrubRule{
+		emailRule,
+		{Name: "token", Pattern: regexp.MustCompile(`token=\w+`), Replacement: "token=***"},
+		{Name: "key", Pattern: regexp.MustCompile(`(key=\w{2})\w+`), Replacement: "$1****"},
+	}
+
+	log.Info("user john@example.com logged in with token=abc123 and key=ab1234")
+
+	assert.Equal(t, "test | INFO  | user [REDACTED] logged in with token=*** and key=ab****\n", stdout.String())
+
+}
+
+func Test_Scrub_Fields(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+	defer resetScrubConfig()
+
+	log.ScrubRules = []log.ScrubRule{
+		emailRule,
+		{Name: "password", Pattern: regexp.MustCompile(`^hunter\d+$`)},
+	}
+
+	fields := map[string]interface{}{"password": "hunter2", "count": 3}
+	log.WithFields(fields).Info("login")
+	log.WithField("user", "john@example.com").Info("login")
+
+	assert.Equal(t, "test | INFO  | login count=3 password=[REDACTED]\ntest | INFO  | login user=[REDACTED]\n", stdout.String(), "stdout")
+	assert.Equal(t, "hunter2", fields["password"], "unchanged")
+
+	stdout.Reset()
+	log.InfoDump(map[string]string{"user": "john@example.com"}, "dump")
+
+	assert.Contains(t, stdout.String(), "\"user\": \"[REDACTED]\"", "dump")
+
+}
+
+func Test_Scrub_DryRun(t *testing.T) {
+
+	resetLogConfig()
+	stdout, stderr := redirectOutput()
+	defer resetLogOutput()
+	defer resetScrubConfig()
+
+	report := bytes.NewBufferString("")
+
+	log.ScrubRules = []log.ScrubRule{emailRule}
+	log.ScrubDryRun = true
+	log.ScrubReport = report
+
+	log.Warn("mail john@example.com and jane@example.com")
+
+	assert.Equal(t, "test | WARN  | mail john@example.com and jane@example.com\n", stdout.String(), "stdout")
+	assert.Equal(t, "", stderr.String(), "stderr")
+
+	var actual map[string]interface{}
+	err := json.Unmarshal(report.Bytes(), &actual)
+
+	assert.NoError(t, err, "json")
+	assert.Equal(t, "email", actual["rule"], "rule")
+	assert.Equal(t, "WARN", actual["level"], "level")
+	assert.Equal(t, 2.0, actual["matches"], "matches")
+	assert.Contains(t, actual["caller"], "scrub_test.go:", "caller")
+	assert.NotContains(t, report.String(), "john@example.com", "no-values")
+
+}
+
+func Test_Scrub_Strategies(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+	defer resetScrubConfig()
+
+	defer func(key []byte) {
+		log.ScrubHashKey = key
+	}(log.ScrubHashKey)
+	log.ScrubHashKey = []byte("secret")
+
+	log.ScrubRules = []log.ScrubRule{
+		{Name: "email", Pattern: emailRule.Pattern, Strategy: log.ScrubHash},
+		{Name: "card", Pattern: regexp.MustCompile(`\d{4}-\d{4}-\d{4}-\d{4}`), Strategy: log.ScrubPartial, KeepLast: 4},
+	}
+
+	log.Info("john@example.com paid with 1234-5678-9012-3456")
+	log.Info("john@example.com")
+	log.Info("jane@example.com")
+
+	lines := bytes.Split(bytes.TrimSpace(stdout.Bytes()), []byte("\n"))
+
+	assert.Regexp(t, `^test \| INFO  \| \[hash:[0-9a-f]{16}\] paid with \*\*\*\*-\*\*\*\*-\*\*\*\*-3456$`, string(lines[0]), "line")
+	assert.Equal(t, lines[0][15:38], lines[1][15:], "stable")
+	assert.NotEqual(t, lines[1], lines[2], "distinct")
+
+}
+
+func Test_Scrub_HashKey(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+	defer resetScrubConfig()
+
+	defer func(key []byte) {
+		log.ScrubHashKey = key
+	}(log.ScrubHashKey)
+
+	assert.Len(t, log.ScrubHashKey, 32, "random-default")
+
+	log.ScrubHashKey = []byte{}
+	log.ScrubRules = []log.ScrubRule{{Name: "email", Pattern: emailRule.Pattern, Strategy: log.ScrubHash}}
+
+	log.Info("john@example.com")
+
+	assert.Equal(t, "test | INFO  | [REDACTED]\n", stdout.String(), "no-key")
+
+}
@@ -0,0 +1,41 @@
+package log
+
+import (
+	"io"
+	"reflect"
+)
+
+// SequenceField is the field which holds the sequence number of an entry
+const SequenceField = "seq"
+
+// SequenceNumbers indicates if every entry gets a sequence number in the SequenceField field
+//
+// The numbers start at 1 for each output when the process starts and increase by one for every entry written to it,
+// so that VerifyLog can detect missing entries.
+var SequenceNumbers = false
+
+// sequenceNumbers are the last sequence numbers of each output, guarded by logMutex
+var sequenceNumbers = map[interface{}]uint64{}
+
+// ResetSequenceNumbers starts the sequence numbers of all outputs over, as if the process restarted
+func ResetSequenceNumbers() {
+	logMutex.Lock()
+	sequenceNumbers = map[interface{}]uint64{}
+	logMutex.Unlock()
+}
+
+// nextSequenceNumber returns the next sequence number of the output and must be called while holding logMutex
+func nextSequenceNumber(sinkName string, w io.Writer) uint64 {
+	key := outputKey(sinkName, w)
+	sequenceNumbers[key]++
+	return sequenceNumbers[key]
+}
+
+// outputKey identifies an output by its writer, so that outputs which share the same writer (such as Stdout and
+// Stderr writing to the same file) share their state, or by its name for writers which can't be compared
+func outputKey(sinkName string, w io.Writer) interface{} {
+	if w != nil && reflect.TypeOf(w).Comparable() {
+		return w
+	}
+	return sinkName
+}
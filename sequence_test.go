@@ -0,0 +1,54 @@
+package log_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func resetSequenceConfig() {
+	log.SequenceNumbers = false
+	log.ResetSequenceNumbers()
+	log.Format = log.FormatText
+}
+
+func Test_SequenceNumbers(t *testing.T) {
+
+	resetLogConfig()
+	stdout, stderr := redirectOutput()
+	defer resetLogOutput()
+	defer resetSequenceConfig()
+
+	log.ResetSequenceNumbers()
+	log.SequenceNumbers = true
+
+	log.Info("first")
+	log.WithField("user", "john").Info("second")
+	log.Error("error")
+
+	assert.Equal(t, "test | INFO  | first seq=1\ntest | INFO  | second seq=2 user=john\n", stdout.String(), "stdout")
+	assert.Equal(t, "test | ERROR | error seq=1\n", stderr.String(), "stderr")
+
+}
+
+func Test_SequenceNumbers_SharedWriter(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+	defer resetSequenceConfig()
+
+	log.ResetSequenceNumbers()
+	log.SequenceNumbers = true
+	log.Format = log.FormatDatadog
+	log.Stderr = stdout
+
+	log.Info("first")
+	log.Error("second")
+
+	assert.Contains(t, stdout.String(), `"message":"first","schema_version":1,"seq":1,`, "first")
+	assert.Contains(t, stdout.String(), `"message":"second","schema_version":1,"seq":2,`, "second")
+
+}
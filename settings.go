@@ -0,0 +1,66 @@
+package log
+
+import (
+	"fmt"
+	"time"
+)
+
+// SetDebugMode changes DebugMode at runtime and records the change as a notice
+//
+// The source describes who or what changed the setting (e.g. "SIGUSR1", "http" or "env")
+func SetDebugMode(enabled bool, source string) {
+	old := DebugMode
+	DebugMode = enabled
+	auditChange("DebugMode", old, enabled, source)
+}
+
+// SetTraceMode changes TraceMode at runtime and records the change as a notice
+func SetTraceMode(enabled bool, source string) {
+	old := TraceMode
+	TraceMode = enabled
+	auditChange("TraceMode", old, enabled, source)
+}
+
+// SetDebugSQLMode changes DebugSQLMode at runtime and records the change as a notice
+func SetDebugSQLMode(enabled bool, source string) {
+	old := DebugSQLMode
+	DebugSQLMode = enabled
+	auditChange("DebugSQLMode", old, enabled, source)
+}
+
+// SetPrintTimestamp changes PrintTimestamp at runtime and records the change as a notice
+func SetPrintTimestamp(enabled bool, source string) {
+	old := PrintTimestamp
+	PrintTimestamp = enabled
+	auditChange("PrintTimestamp", old, enabled, source)
+}
+
+// SetTimeFormat changes TimeFormat at runtime and records the change as a notice
+func SetTimeFormat(format string, source string) {
+	old := TimeFormat
+	TimeFormat = format
+	auditChange("TimeFormat", old, format, source)
+}
+
+// SetTimeZone changes TimeZone at runtime and records the change as a notice
+func SetTimeZone(location *time.Location, source string) {
+	old := TimeZone
+	TimeZone = location
+	auditChange("TimeZone", old, location, source)
+}
+
+func auditChange(setting string, old interface{}, new interface{}, source string) {
+	if message, changed := changeMessage(setting, old, new, source); changed {
+		Notice(message)
+	}
+}
+
+func changeMessage(setting string, old interface{}, new interface{}, source string) (string, bool) {
+	if fmt.Sprint(old) == fmt.Sprint(new) {
+		return "", false
+	}
+	if source == "" {
+		source = "unknown"
+	}
+	return fmt.Sprintf("%s changed from %q to %q by %s", setting, fmt.Sprint(old), fmt.Sprint(new), source), true
+}
@@ -0,0 +1,75 @@
+package log_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func Test_Notice(t *testing.T) {
+
+	resetLogConfig()
+	stdout, stderr := redirectOutput()
+	defer resetLogOutput()
+
+	log.Notice("notice")
+
+	assert.Equal(t, "test | NOTE  | notice\n", stdout.String(), "stdout")
+	assert.Equal(t, "", stderr.String(), "stderr")
+
+}
+
+func Test_SetDebugMode(t *testing.T) {
+
+	resetLogConfig()
+	stdout, stderr := redirectOutput()
+	defer resetLogOutput()
+
+	log.SetDebugMode(true, "SIGUSR1")
+
+	assert.True(t, log.DebugMode, "debug-mode")
+	assert.Equal(t, "test | NOTE  | DebugMode changed from \"false\" to \"true\" by SIGUSR1\n", stdout.String(), "stdout")
+	assert.Equal(t, "", stderr.String(), "stderr")
+
+}
+
+func Test_SetDebugMode_Unchanged(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+
+	log.SetDebugMode(false, "http")
+
+	assert.False(t, log.DebugMode, "debug-mode")
+	assert.Equal(t, "", stdout.String(), "stdout")
+
+}
+
+func Test_SetSettings(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+
+	log.SetDebugSQLMode(true, "")
+	log.SetTimeZone(time.UTC, "env")
+	log.SetPrintTimestamp(false, "env")
+	log.SetTimeFormat("2006", "env")
+
+	assert.True(t, log.DebugSQLMode, "debug-sql-mode")
+	assert.Equal(t, time.UTC, log.TimeZone, "time-zone")
+	assert.Equal(t, "2006", log.TimeFormat, "time-format")
+	assert.False(t, log.PrintTimestamp, "print-timestamp")
+
+	expected := "test | NOTE  | DebugSQLMode changed from \"false\" to \"true\" by unknown\n" +
+		"test | NOTE  | TimeZone changed from \"Europe/Brussels\" to \"UTC\" by env\n" +
+		"PrintTimestamp changed from \"true\" to \"false\" by env\n" +
+		"TimeFormat changed from \"test\" to \"2006\" by env\n"
+
+	assert.Equal(t, expected, stdout.String(), "stdout")
+
+}
@@ -0,0 +1,114 @@
+package log
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"regexp"
+)
+
+// SignatureField is the key of the HMAC chain value appended to the signed entries
+const SignatureField = "sig"
+
+// SigningKey is the key used to append an HMAC-SHA256 chain value to each entry (nil disables signing)
+//
+// The value of each entry is the HMAC of the value of the previous entry written to the same output followed by the
+// entry itself, so that VerifySignatures detects modified, inserted, reordered and deleted entries. The chain of each
+// output starts over when the process restarts, removing the last entries of a file can't be detected. The chain
+// continues across rotated files, verify them by concatenating them in order.
+var SigningKey []byte
+
+// ErrSignatureMismatch is returned when a signed log doesn't match its signatures
+var ErrSignatureMismatch = errors.New("signature mismatch")
+
+// signatureChains are the last chain values of each output, guarded by logMutex
+var signatureChains = map[interface{}][]byte{}
+
+var textSignature = regexp.MustCompile(` ` + SignatureField + `=([0-9a-f]{64})\n?$`)
+var jsonSignature = regexp.MustCompile(`,"` + SignatureField + `":"([0-9a-f]{64})"}\n?$`)
+
+// ResetSignatureChains starts new chains for all outputs, as if the process restarted
+func ResetSignatureChains() {
+	logMutex.Lock()
+	signatureChains = map[interface{}][]byte{}
+	logMutex.Unlock()
+}
+
+// signLine appends the next chain value of the output to the entry and must be called while holding logMutex
+//
+// Outputs which share the same writer, such as Stdout and Stderr writing to the same file, share the same chain.
+func signLine(sinkName string, w io.Writer, line []byte, format OutputFormat) []byte {
+
+	key := outputKey(sinkName, w)
+
+	sig := chainValue(SigningKey, signatureChains[key], line)
+	signatureChains[key] = sig
+
+	value := hex.EncodeToString(sig)
+	body := bytes.TrimSuffix(line, []byte("\n"))
+
+	var b bytes.Buffer
+	if format == FormatDatadog && bytes.HasSuffix(body, []byte("}")) {
+		b.Write(body[:len(body)-1])
+		b.WriteString(`,"` + SignatureField + `":"` + value + `"}`)
+	} else {
+		b.Write(body)
+		b.WriteString(" " + SignatureField + "=" + value)
+	}
+	if len(body) < len(line) {
+		b.WriteByte('\n')
+	}
+
+	return b.Bytes()
+
+}
+
+func chainValue(key []byte, previous []byte, line []byte) []byte {
+	if previous == nil {
+		previous = make([]byte, sha256.Size)
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write(previous)
+	mac.Write(line)
+	return mac.Sum(nil)
+}
+
+// VerifySignatures reads a log written with SigningKey set to key and verifies the chain of its entries, see
+// VerifyLog
+func VerifySignatures(r io.Reader, key []byte) (SignatureReport, error) {
+	return VerifyLog(r, VerifyOptions{Key: key})
+}
+
+// splitSignature returns the entry as it was signed and its chain value
+func splitSignature(data []byte) ([]byte, []byte, bool) {
+
+	newline := bytes.HasSuffix(data, []byte("\n"))
+
+	if m := jsonSignature.FindSubmatchIndex(data); m != nil {
+		content := append(append([]byte{}, data[:m[0]]...), '}')
+		return withNewline(content, newline), decodeSignature(data[m[2]:m[3]]), true
+	}
+
+	if m := textSignature.FindSubmatchIndex(data); m != nil {
+		content := append([]byte{}, data[:m[0]]...)
+		return withNewline(content, newline), decodeSignature(data[m[2]:m[3]]), true
+	}
+
+	return nil, nil, false
+
+}
+
+func withNewline(data []byte, newline bool) []byte {
+	if newline {
+		return append(data, '\n')
+	}
+	return data
+}
+
+func decodeSignature(data []byte) []byte {
+	sig, _ := hex.DecodeString(string(data))
+	return sig
+}
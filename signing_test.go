@@ -0,0 +1,123 @@
+package log_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func resetSigningConfig() {
+	log.SigningKey = nil
+	log.ResetSignatureChains()
+	log.Format = log.FormatText
+}
+
+func writeSignedLog(format log.OutputFormat) string {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+
+	log.ResetSignatureChains()
+	log.SigningKey = []byte("secret")
+	log.Format = format
+	log.Stderr = stdout
+
+	log.Info("first")
+	log.WithField("user", "john").Warn("second")
+	log.Error("multi\nline")
+	log.Info("last")
+
+	return stdout.String()
+
+}
+
+func Test_SigningKey(t *testing.T) {
+
+	type test struct {
+		name   string
+		format log.OutputFormat
+		suffix string
+	}
+
+	var tests = []test{
+		{"text", log.FormatText, ` sig=[0-9a-f]{64}$`},
+		{"datadog", log.FormatDatadog, `,"sig":"[0-9a-f]{64}"}$`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			defer resetSigningConfig()
+
+			output := writeSignedLog(tc.format)
+
+			lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+			if tc.format == log.FormatText {
+				assert.Len(t, lines, 5, "lines")
+				assert.Equal(t, "test | ERROR | multi", lines[2], "multi-line")
+				lines = append(lines[:2], lines[3:]...)
+			}
+			for _, line := range lines {
+				assert.Regexp(t, tc.suffix, line, "signature")
+			}
+
+			report, err := log.VerifySignatures(strings.NewReader(output), []byte("secret"))
+			assert.NoError(t, err, "verify")
+			assert.Equal(t, log.SignatureReport{Entries: 4, Restarts: 1}, report, "report")
+
+		})
+	}
+
+}
+
+func Test_VerifySignatures_Tampered(t *testing.T) {
+
+	defer resetSigningConfig()
+
+	output := writeSignedLog(log.FormatText)
+	lines := strings.SplitAfter(output, "\n")
+
+	type test struct {
+		name     string
+		log      string
+		key      string
+		expected string
+	}
+
+	var tests = []test{
+		{"wrong-key", output, "other", "line 1: signature mismatch"},
+		{"modified", strings.Replace(output, "second", "altered", 1), "secret", "line 2: signature mismatch"},
+		{"deleted", lines[0] + strings.Join(lines[2:], ""), "secret", "line 2: signature mismatch"},
+		{"reordered", lines[1] + lines[0] + strings.Join(lines[2:], ""), "secret", "line 1: signature mismatch"},
+		{"unsigned", output + "test | INFO  | injected\n", "secret", "line 6: unsigned entry"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := log.VerifySignatures(strings.NewReader(tc.log), []byte(tc.key))
+			if assert.Error(t, err, "error") {
+				assert.Equal(t, tc.expected, err.Error(), "message")
+			}
+		})
+	}
+
+}
+
+func Test_VerifySignatures_Restart(t *testing.T) {
+
+	defer resetSigningConfig()
+
+	var combined bytes.Buffer
+	combined.WriteString(writeSignedLog(log.FormatText))
+	combined.WriteString(writeSignedLog(log.FormatText))
+
+	report, err := log.VerifySignatures(&combined, []byte("secret"))
+	assert.NoError(t, err, "verify")
+	assert.Equal(t, log.SignatureReport{Entries: 8, Restarts: 2}, report, "report")
+
+}
@@ -0,0 +1,137 @@
+//go:build go1.21
+// +build go1.21
+
+package log
+
+import (
+	"context"
+	"log/slog"
+)
+
+// slogHandler is a slog.Handler which writes the records through a Logger
+type slogHandler struct {
+	logger *Logger
+	fields map[string]interface{}
+	prefix string
+}
+
+// SlogHandler returns a slog.Handler which writes the records through the default logger
+//
+// The records use the same outputs, format and level settings (such as DebugMode) as the other entries. The
+// attributes become fields, with the names of their groups joined by dots (e.g. "http.status").
+func SlogHandler() slog.Handler {
+	return std.SlogHandler()
+}
+
+// AsSlog returns a slog.Logger which writes the records through the default logger
+func AsSlog() *slog.Logger {
+	return slog.New(SlogHandler())
+}
+
+// SlogHandler returns a slog.Handler which writes the records through the logger
+func (l *Logger) SlogHandler() slog.Handler {
+	return &slogHandler{logger: l}
+}
+
+// Enabled returns true if entries with the level are written
+func (h *slogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.logger.levelEnabled(slogLevel(level)) || isRecording()
+}
+
+// Handle writes the record as an entry, with the time and the caller of the record
+func (h *slogHandler) Handle(ctx context.Context, r slog.Record) error {
+
+	if !h.Enabled(ctx, r.Level) {
+		return nil
+	}
+
+	fields := make(map[string]interface{}, len(h.fields)+r.NumAttrs()+1)
+	for key, value := range h.fields {
+		fields[key] = value
+	}
+
+	r.Attrs(func(attr slog.Attr) bool {
+		addSlogAttr(fields, h.prefix, attr)
+		return true
+	})
+
+	if ctx != nil {
+		if id := RequestIDFromContext(ctx); id != "" {
+			if _, ok := fields[RequestIDField]; !ok {
+				fields[RequestIDField] = id
+			}
+		}
+	}
+
+	h.logger.logEntryAt(slogLevel(r.Level), r.Message, fields, true, r.Time, r.PC)
+
+	return nil
+
+}
+
+// WithAttrs returns a handler which adds the attributes to all records
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+
+	fields := make(map[string]interface{}, len(h.fields)+len(attrs))
+	for key, value := range h.fields {
+		fields[key] = value
+	}
+	for _, attr := range attrs {
+		addSlogAttr(fields, h.prefix, attr)
+	}
+
+	return &slogHandler{logger: h.logger, fields: fields, prefix: h.prefix}
+
+}
+
+// WithGroup returns a handler which adds the attributes of the records to the group
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &slogHandler{logger: h.logger, fields: h.fields, prefix: h.prefix + name + "."}
+}
+
+func addSlogAttr(fields map[string]interface{}, prefix string, attr slog.Attr) {
+
+	value := attr.Value.Resolve()
+
+	if value.Kind() == slog.KindGroup {
+		group := value.Group()
+		if len(group) == 0 {
+			return
+		}
+		if attr.Key != "" {
+			prefix += attr.Key + "."
+		}
+		for _, a := range group {
+			addSlogAttr(fields, prefix, a)
+		}
+		return
+	}
+
+	if attr.Key == "" {
+		return
+	}
+
+	fields[prefix+attr.Key] = value.Any()
+
+}
+
+// slogLevel returns the label of the level closest to the slog level
+func slogLevel(level slog.Level) string {
+	switch {
+	case level < slog.LevelDebug:
+		return TraceLevel.String()
+	case level < slog.LevelInfo:
+		return DebugLevel.String()
+	case level < slog.LevelInfo+2:
+		return InfoLevel.String()
+	case level < slog.LevelWarn:
+		return NoticeLevel.String()
+	case level < slog.LevelError:
+		return WarnLevel.String()
+	default:
+		return ErrorLevel.String()
+	}
+}
@@ -0,0 +1,112 @@
+//go:build go1.21
+// +build go1.21
+
+package log_test
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func Test_AsSlog(t *testing.T) {
+
+	type test struct {
+		name           string
+		debug          bool
+		log            func(l *slog.Logger)
+		expectedStdout string
+		expectedStderr string
+	}
+
+	var tests = []test{
+		{"info", false, func(l *slog.Logger) { l.Info("info", "user", "john", "count", 3) }, "test | INFO  | info count=3 user=john\n", ""},
+		{"notice", false, func(l *slog.Logger) { l.Log(context.Background(), slog.LevelInfo+2, "notice") }, "test | NOTE  | notice\n", ""},
+		{"warn", false, func(l *slog.Logger) { l.Warn("warn") }, "test | WARN  | warn\n", ""},
+		{"error", false, func(l *slog.Logger) { l.Error("error") }, "", "test | ERROR | error\n"},
+		{"debug-disabled", false, func(l *slog.Logger) { l.Debug("debug") }, "", ""},
+		{"debug-enabled", true, func(l *slog.Logger) { l.Debug("debug") }, "test | DEBUG | debug\n", ""},
+		{"trace-disabled", true, func(l *slog.Logger) { l.Log(context.Background(), slog.LevelDebug-4, "trace") }, "", ""},
+		{"groups", false, func(l *slog.Logger) {
+			l.With("service", "api").WithGroup("http").Info("request", "status", 200, slog.Group("client", "ip", "127.0.0.1"))
+		}, "test | INFO  | request http.client.ip=127.0.0.1 http.status=200 service=api\n", ""},
+		{"empty-group", false, func(l *slog.Logger) { l.Info("message", slog.Group("empty"), slog.Attr{}) }, "test | INFO  | message\n", ""},
+		{"request-id", false, func(l *slog.Logger) {
+			l.InfoContext(log.ContextWithRequestID(context.Background(), "req-1"), "message")
+		}, "test | INFO  | message request_id=req-1\n", ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			resetLogConfig()
+			stdout, stderr := redirectOutput()
+			defer resetLogOutput()
+
+			log.DebugMode = tc.debug
+
+			tc.log(log.AsSlog())
+
+			assert.Equal(t, tc.expectedStdout, stdout.String(), "stdout")
+			assert.Equal(t, tc.expectedStderr, stderr.String(), "stderr")
+
+		})
+	}
+
+}
+
+func Test_Logger_SlogHandler(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+
+	slog.New(log.WithField("component", "db").SlogHandler()).Info("connected")
+
+	assert.Equal(t, "test | INFO  | connected component=db\n", stdout.String(), "stdout")
+
+}
+
+func Test_AsSlog_Caller(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+	defer func() {
+		log.ReportCaller = false
+		log.CallerFormat = log.CallerFull
+	}()
+
+	log.ReportCaller = true
+	log.CallerFormat = log.CallerShort
+
+	_, _, line, _ := runtime.Caller(0)
+	log.AsSlog().Info("message")
+
+	assert.Equal(t, "test | INFO  | slog_test.go:"+strconv.Itoa(line+1)+" | message\n", stdout.String())
+
+}
+
+func Test_AsSlog_Time(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+
+	log.PrintTimestamp = true
+	log.TimeFormat = "2006-01-02 15:04:05"
+	log.TimeZone = time.UTC
+
+	r := slog.NewRecord(time.Date(2000, 1, 2, 3, 4, 5, 0, time.UTC), slog.LevelInfo, "message", 0)
+	log.SlogHandler().Handle(context.Background(), r)
+
+	assert.Equal(t, "2000-01-02 03:04:05 | INFO  | message\n", stdout.String())
+
+}
@@ -0,0 +1,80 @@
+package log
+
+import (
+	"path"
+	"runtime/debug"
+	"sort"
+	"strings"
+)
+
+// TrimSourcePaths indicates if the file paths in stack traces and reported callers are shortened so that they don't
+// leak the layout of the build machine
+//
+// Files of the main module become relative to the module root (e.g. "pkg/file.go"), files of the standard library
+// and of dependencies start at their import path (e.g. "net/http/server.go" or "github.com/lib/pq@v1.0.0/conn.go").
+var TrimSourcePaths = false
+
+// SourcePathPrefixes maps path prefixes to their replacement in stack traces and reported callers (e.g.
+// {"/home/build/src/": ""}), they take precedence over TrimSourcePaths
+var SourcePathPrefixes = map[string]string{}
+
+var mainModulePath = readMainModulePath()
+
+func readMainModulePath() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	return info.Main.Path
+}
+
+func trimsSourcePaths() bool {
+	return TrimSourcePaths || len(SourcePathPrefixes) > 0
+}
+
+// trimSourcePath shortens file, which contains the code of function, according to SourcePathPrefixes and
+// TrimSourcePaths
+func trimSourcePath(file string, function string) string {
+
+	if len(SourcePathPrefixes) > 0 {
+		prefixes := make([]string, 0, len(SourcePathPrefixes))
+		for prefix := range SourcePathPrefixes {
+			prefixes = append(prefixes, prefix)
+		}
+		sort.Slice(prefixes, func(i, j int) bool {
+			return len(prefixes[i]) > len(prefixes[j])
+		})
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(file, prefix) {
+				return SourcePathPrefixes[prefix] + strings.TrimPrefix(file, prefix)
+			}
+		}
+	}
+
+	if !TrimSourcePaths {
+		return file
+	}
+
+	slashed := strings.Replace(file, "\\", "/", -1)
+	if i := strings.LastIndex(slashed, "/pkg/mod/"); i >= 0 {
+		return slashed[i+len("/pkg/mod/"):]
+	}
+
+	dir, base := path.Split(slashed)
+	dir = strings.TrimSuffix(dir, "/")
+	pkg := strings.TrimSuffix(funcPackage(function), "_test")
+
+	if mainModulePath != "" && (pkg == mainModulePath || strings.HasPrefix(pkg, mainModulePath+"/")) {
+		relative := strings.TrimPrefix(pkg, mainModulePath)
+		if strings.HasSuffix(dir, relative) {
+			return strings.TrimPrefix(relative+"/"+base, "/")
+		}
+	}
+
+	if pkg != "" && strings.HasSuffix(dir, "/"+pkg) {
+		return pkg + "/" + base
+	}
+
+	return file
+
+}
@@ -0,0 +1,52 @@
+package log
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_trimSourcePath(t *testing.T) {
+
+	oldMainModulePath := mainModulePath
+	defer func() {
+		mainModulePath = oldMainModulePath
+		TrimSourcePaths = false
+		SourcePathPrefixes = map[string]string{}
+	}()
+
+	mainModulePath = "github.com/user/app"
+
+	type test struct {
+		name     string
+		trim     bool
+		prefixes map[string]string
+		file     string
+		function string
+		expected string
+	}
+
+	var tests = []test{
+		{"disabled", false, nil, "/home/build/app/pkg/file.go", "github.com/user/app/pkg.Run", "/home/build/app/pkg/file.go"},
+		{"main-module", true, nil, "/home/build/app/pkg/file.go", "github.com/user/app/pkg.Run", "pkg/file.go"},
+		{"main-module-root", true, nil, "/home/build/app/main.go", "github.com/user/app.main", "main.go"},
+		{"external-test", true, nil, "/home/build/app/pkg/file_test.go", "github.com/user/app/pkg_test.Test_Run", "pkg/file_test.go"},
+		{"module-cache", true, nil, "/go/pkg/mod/github.com/lib/pq@v1.0.0/conn.go", "github.com/lib/pq.(*conn).query", "github.com/lib/pq@v1.0.0/conn.go"},
+		{"stdlib", true, nil, "/usr/local/go/src/net/http/server.go", "net/http.(*conn).serve", "net/http/server.go"},
+		{"gopath", true, nil, "/go/src/github.com/lib/pq/conn.go", "github.com/lib/pq.(*conn).query", "github.com/lib/pq/conn.go"},
+		{"windows", true, nil, `C:\go\pkg\mod\github.com\lib\pq@v1.0.0\conn.go`, "github.com/lib/pq.(*conn).query", "github.com/lib/pq@v1.0.0/conn.go"},
+		{"unknown", true, nil, "/tmp/generated.go", "main.init", "/tmp/generated.go"},
+		{"prefix", false, map[string]string{"/home/build/": "", "/home/build/app/": "app:"}, "/home/build/app/pkg/file.go", "github.com/user/app/pkg.Run", "app:pkg/file.go"},
+		{"prefix-before-trim", true, map[string]string{"/home/build/": "~/"}, "/home/build/app/pkg/file.go", "github.com/user/app/pkg.Run", "~/app/pkg/file.go"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			TrimSourcePaths = tc.trim
+			SourcePathPrefixes = tc.prefixes
+			actual := trimSourcePath(tc.file, tc.function)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+
+}
@@ -0,0 +1,29 @@
+package log
+
+import (
+	"context"
+)
+
+// TraceIDField is the field which holds the trace ID of the span of the context, see FromContext
+const TraceIDField = "trace_id"
+
+// SpanIDField is the field which holds the span ID of the span of the context, see FromContext
+const SpanIDField = "span_id"
+
+type spanKey struct{}
+
+type span struct {
+	traceID string
+	spanID  string
+}
+
+// ContextWithSpan returns a copy of ctx carrying the trace and span IDs of the current span (e.g. from the tracer)
+func ContextWithSpan(ctx context.Context, traceID string, spanID string) context.Context {
+	return context.WithValue(ctx, spanKey{}, span{traceID: traceID, spanID: spanID})
+}
+
+// SpanFromContext returns the trace and span IDs carried by ctx (or empty strings if there are none)
+func SpanFromContext(ctx context.Context) (string, string) {
+	s, _ := ctx.Value(spanKey{}).(span)
+	return s.traceID, s.spanID
+}
@@ -0,0 +1,24 @@
+package log_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func Test_SpanFromContext(t *testing.T) {
+
+	ctx := context.Background()
+
+	traceID, spanID := log.SpanFromContext(ctx)
+	assert.Equal(t, "", traceID, "missing-trace-id")
+	assert.Equal(t, "", spanID, "missing-span-id")
+
+	traceID, spanID = log.SpanFromContext(log.ContextWithSpan(ctx, "trace", "span"))
+	assert.Equal(t, "trace", traceID, "trace-id")
+	assert.Equal(t, "span", spanID, "span-id")
+
+}
@@ -0,0 +1,62 @@
+package log
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// SQLArgsField is the name of the field with the arguments of the statements logged by DebugSQLQuery
+const SQLArgsField = "sql.args"
+
+// formatSQLArgs returns the arguments of a statement as a list of SQL literals (e.g. "1, 'jane'")
+func formatSQLArgs(args []interface{}) string {
+	values := make([]string, len(args))
+	for i, arg := range args {
+		values[i] = formatSQLValue(arg)
+	}
+	return strings.Join(values, ", ")
+}
+
+// formatSQLValue returns the value as an SQL literal
+func formatSQLValue(value interface{}) string {
+
+	if valuer, ok := value.(driver.Valuer); ok {
+		v, err := valuer.Value()
+		if err != nil {
+			return quoteSQL("<error: " + err.Error() + ">")
+		}
+		value = v
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return "NULL"
+	case string:
+		return quoteSQL(v)
+	case []byte:
+		if utf8.Valid(v) {
+			return quoteSQL(string(v))
+		}
+		return "X'" + hex.EncodeToString(v) + "'"
+	case bool:
+		if v {
+			return "TRUE"
+		}
+		return "FALSE"
+	case time.Time:
+		return quoteSQL(v.Format("2006-01-02 15:04:05.999999999Z07:00"))
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return fmt.Sprint(v)
+	default:
+		return quoteSQL(fmt.Sprint(v))
+	}
+
+}
+
+func quoteSQL(s string) string {
+	return "'" + strings.Replace(s, "'", "''", -1) + "'"
+}
@@ -0,0 +1,69 @@
+package log
+
+import (
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testValuer struct {
+	value driver.Value
+	err   error
+}
+
+func (v testValuer) Value() (driver.Value, error) {
+	return v.value, v.err
+}
+
+func Test_formatSQLArgs(t *testing.T) {
+
+	var tests = []struct {
+		name     string
+		args     []interface{}
+		expected string
+	}{
+		{"no-args", nil, ""},
+		{"args", []interface{}{1, "jane", nil}, "1, 'jane', NULL"},
+		{"injection", []interface{}{"'; drop table t; --"}, "'''; drop table t; --'"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, formatSQLArgs(tc.args))
+		})
+	}
+
+}
+
+func Test_formatSQLValue(t *testing.T) {
+
+	var tests = []struct {
+		name     string
+		value    interface{}
+		expected string
+	}{
+		{"nil", nil, "NULL"},
+		{"string", "o'neil", "'o''neil'"},
+		{"bytes", []byte("text"), "'text'"},
+		{"binary", []byte{0xff, 0x00}, "X'ff00'"},
+		{"true", true, "TRUE"},
+		{"false", false, "FALSE"},
+		{"int", int64(-3), "-3"},
+		{"float", 1.5, "1.5"},
+		{"time", time.Date(2020, 3, 1, 10, 0, 0, 0, time.UTC), "'2020-03-01 10:00:00Z'"},
+		{"valuer", testValuer{value: "v"}, "'v'"},
+		{"valuer-null", testValuer{}, "NULL"},
+		{"valuer-error", testValuer{err: errors.New("failed")}, "'<error: failed>'"},
+		{"other", struct{ A int }{1}, "'{1}'"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, formatSQLValue(tc.value))
+		})
+	}
+
+}
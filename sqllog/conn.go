@@ -0,0 +1,225 @@
+package sqllog
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"time"
+)
+
+// conn logs the statements executed directly on the wrapped connection and wraps its prepared statements
+//
+// The optional interfaces which the wrapped connection doesn't implement return driver.ErrSkip, so that database/sql
+// falls back to what it does for the wrapped connection.
+type conn struct {
+	conn driver.Conn
+}
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	s, err := c.conn.Prepare(query)
+	if err != nil {
+		return nil, err
+	}
+	return c.wrapStmt(s, query), nil
+}
+
+func (c *conn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if pc, ok := c.conn.(driver.ConnPrepareContext); ok {
+		s, err := pc.PrepareContext(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		return c.wrapStmt(s, query), nil
+	}
+	return c.Prepare(query)
+}
+
+func (c *conn) Close() error {
+	return c.conn.Close()
+}
+
+func (c *conn) Begin() (driver.Tx, error) {
+	return c.conn.Begin()
+}
+
+func (c *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if bc, ok := c.conn.(driver.ConnBeginTx); ok {
+		return bc.BeginTx(ctx, opts)
+	}
+	if opts.Isolation != 0 {
+		return nil, errors.New("sqllog: driver does not support non-default isolation level")
+	}
+	if opts.ReadOnly {
+		return nil, errors.New("sqllog: driver does not support read-only transactions")
+	}
+	return c.conn.Begin()
+}
+
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+
+	start := time.Now()
+
+	var result driver.Result
+	var err error
+
+	switch e := c.conn.(type) {
+	case driver.ExecerContext:
+		result, err = e.ExecContext(ctx, query, args)
+	case driver.Execer:
+		var dargs []driver.Value
+		if dargs, err = values(args); err == nil {
+			result, err = e.Exec(query, dargs)
+		}
+	default:
+		return nil, driver.ErrSkip
+	}
+
+	logStatement(query, args, start, err)
+	return result, err
+
+}
+
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+
+	start := time.Now()
+
+	var rows driver.Rows
+	var err error
+
+	switch q := c.conn.(type) {
+	case driver.QueryerContext:
+		rows, err = q.QueryContext(ctx, query, args)
+	case driver.Queryer:
+		var dargs []driver.Value
+		if dargs, err = values(args); err == nil {
+			rows, err = q.Query(query, dargs)
+		}
+	default:
+		return nil, driver.ErrSkip
+	}
+
+	logStatement(query, args, start, err)
+	return rows, err
+
+}
+
+func (c *conn) Ping(ctx context.Context) error {
+	if p, ok := c.conn.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *conn) ResetSession(ctx context.Context) error {
+	if r, ok := c.conn.(driver.SessionResetter); ok {
+		return r.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *conn) CheckNamedValue(nv *driver.NamedValue) error {
+	if checker, ok := c.conn.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+// wrapStmt wraps the prepared statement, it only implements driver.ColumnConverter when the statement does as
+// database/sql handles the arguments differently for them
+func (c *conn) wrapStmt(s driver.Stmt, query string) driver.Stmt {
+	wrapped := &stmt{stmt: s, conn: c.conn, query: query}
+	if cc, ok := s.(driver.ColumnConverter); ok {
+		return &converterStmt{stmt: wrapped, converter: cc}
+	}
+	return wrapped
+}
+
+// stmt logs the executions of a prepared statement
+type stmt struct {
+	stmt  driver.Stmt
+	conn  driver.Conn
+	query string
+}
+
+func (s *stmt) Close() error {
+	return s.stmt.Close()
+}
+
+func (s *stmt) NumInput() int {
+	return s.stmt.NumInput()
+}
+
+func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
+	start := time.Now()
+	result, err := s.stmt.Exec(args)
+	logStatement(s.query, namedValues(args), start, err)
+	return result, err
+}
+
+func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
+	start := time.Now()
+	rows, err := s.stmt.Query(args)
+	logStatement(s.query, namedValues(args), start, err)
+	return rows, err
+}
+
+func (s *stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+
+	start := time.Now()
+
+	var result driver.Result
+	var err error
+
+	if e, ok := s.stmt.(driver.StmtExecContext); ok {
+		result, err = e.ExecContext(ctx, args)
+	} else {
+		var dargs []driver.Value
+		if dargs, err = values(args); err == nil {
+			result, err = s.stmt.Exec(dargs)
+		}
+	}
+
+	logStatement(s.query, args, start, err)
+	return result, err
+
+}
+
+func (s *stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+
+	start := time.Now()
+
+	var rows driver.Rows
+	var err error
+
+	if q, ok := s.stmt.(driver.StmtQueryContext); ok {
+		rows, err = q.QueryContext(ctx, args)
+	} else {
+		var dargs []driver.Value
+		if dargs, err = values(args); err == nil {
+			rows, err = s.stmt.Query(dargs)
+		}
+	}
+
+	logStatement(s.query, args, start, err)
+	return rows, err
+
+}
+
+func (s *stmt) CheckNamedValue(nv *driver.NamedValue) error {
+	if checker, ok := s.stmt.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+	if checker, ok := s.conn.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+type converterStmt struct {
+	*stmt
+	converter driver.ColumnConverter
+}
+
+func (s *converterStmt) ColumnConverter(idx int) driver.ValueConverter {
+	return s.converter.ColumnConverter(idx)
+}
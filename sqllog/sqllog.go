@@ -0,0 +1,146 @@
+// Package sqllog wraps database/sql drivers to log the statements they execute with
+// github.com/pieterclaerhout/go-log
+//
+// Every statement is logged as a debug message with the statement, its arguments and its duration as fields, failed
+// statements are logged as errors. Nothing is logged unless DebugSQLMode is enabled.
+//
+//	sqllog.Register("postgres-log", &pq.Driver{})
+//	db, err := sql.Open("postgres-log", dsn)
+package sqllog
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"time"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+// logQuery logs the statements, it's replaced while testing
+var logQuery = func(query string, args []interface{}, duration time.Duration) {
+	log.WithFields(map[string]interface{}{
+		"statement": query,
+		"args":      args,
+		"duration":  duration,
+	}).Debug("SQL statement")
+}
+
+// Register registers the wrapped driver under name, so that it can be used with sql.Open
+func Register(name string, d driver.Driver) {
+	sql.Register(name, Wrap(d))
+}
+
+// Wrap returns a driver which logs the statements executed by the connections of d
+func Wrap(d driver.Driver) driver.Driver {
+	return &loggingDriver{driver: d}
+}
+
+// WrapConnector returns a connector which logs the statements executed by the connections of c, for sql.OpenDB
+func WrapConnector(c driver.Connector) driver.Connector {
+	return &connector{connector: c, driver: Wrap(c.Driver())}
+}
+
+type loggingDriver struct {
+	driver driver.Driver
+}
+
+// Open opens a connection using the wrapped driver
+func (d *loggingDriver) Open(name string) (driver.Conn, error) {
+	c, err := d.driver.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &conn{conn: c}, nil
+}
+
+// OpenConnector returns a connector of the wrapped driver, or one which calls Open when it doesn't have them
+func (d *loggingDriver) OpenConnector(name string) (driver.Connector, error) {
+	if dc, ok := d.driver.(driver.DriverContext); ok {
+		c, err := dc.OpenConnector(name)
+		if err != nil {
+			return nil, err
+		}
+		return &connector{connector: c, driver: d}, nil
+	}
+	return &connector{connector: dsnConnector{name: name, driver: d.driver}, driver: d}, nil
+}
+
+type connector struct {
+	connector driver.Connector
+	driver    driver.Driver
+}
+
+func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
+	dc, err := c.connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &conn{conn: dc}, nil
+}
+
+func (c *connector) Driver() driver.Driver {
+	return c.driver
+}
+
+type dsnConnector struct {
+	name   string
+	driver driver.Driver
+}
+
+func (c dsnConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.name)
+}
+
+func (c dsnConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// logStatement logs the statement and its error, driver.ErrSkip only means the statement will be executed in
+// another way and isn't logged
+func logStatement(query string, args []driver.NamedValue, start time.Time, err error) {
+
+	if !log.DebugSQLMode || err == driver.ErrSkip {
+		return
+	}
+
+	duration := time.Since(start)
+
+	values := make([]interface{}, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+
+	logQuery(query, values, duration)
+
+	if err != nil {
+		log.WithFields(map[string]interface{}{
+			"statement": query,
+			"duration":  duration,
+			"error":     err.Error(),
+		}).Error("SQL statement failed")
+	}
+
+}
+
+// namedValues converts the arguments of the deprecated driver interfaces
+func namedValues(args []driver.Value) []driver.NamedValue {
+	named := make([]driver.NamedValue, len(args))
+	for i, arg := range args {
+		named[i] = driver.NamedValue{Ordinal: i + 1, Value: arg}
+	}
+	return named
+}
+
+// values converts the arguments for the deprecated driver interfaces, which don't support named arguments
+func values(args []driver.NamedValue) ([]driver.Value, error) {
+	result := make([]driver.Value, len(args))
+	for i, arg := range args {
+		if arg.Name != "" {
+			return nil, errors.New("sqllog: driver does not support the use of Named Parameters")
+		}
+		result[i] = arg.Value
+	}
+	return result, nil
+}
@@ -0,0 +1,288 @@
+package sqllog
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+// fakeDriver returns connections which only implement the required interfaces, or also the context ones
+type fakeDriver struct {
+	context bool
+}
+
+func (d *fakeDriver) Open(name string) (driver.Conn, error) {
+	if d.context {
+		return &fakeContextConn{}, nil
+	}
+	return &fakeConn{}, nil
+}
+
+type fakeConn struct{}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{query: query}, nil
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return fakeTx{}, nil
+}
+
+type fakeContextConn struct {
+	fakeConn
+}
+
+func (c *fakeContextConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return execute(query)
+}
+
+func (c *fakeContextConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return &fakeRows{}, nil
+}
+
+type fakeStmt struct {
+	query string
+}
+
+func (s *fakeStmt) Close() error {
+	return nil
+}
+
+func (s *fakeStmt) NumInput() int {
+	return -1
+}
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	for _, arg := range args {
+		if !driver.IsValue(arg) {
+			return nil, fmt.Errorf("invalid argument: %T", arg)
+		}
+	}
+	return execute(s.query)
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return &fakeRows{}, nil
+}
+
+func execute(query string) (driver.Result, error) {
+	if strings.Contains(query, "fail") {
+		return nil, errors.New("syntax error")
+	}
+	return driver.RowsAffected(1), nil
+}
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error {
+	return nil
+}
+
+func (fakeTx) Rollback() error {
+	return nil
+}
+
+type fakeRows struct {
+	done bool
+}
+
+func (r *fakeRows) Columns() []string {
+	return []string{"value"}
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = int64(42)
+	return nil
+}
+
+type loggedQuery struct {
+	query string
+	args  []interface{}
+}
+
+var registerOnce sync.Once
+
+// captureQueries replaces the logging of the statements and the error output
+func captureQueries(t *testing.T) (*[]loggedQuery, *bytes.Buffer, func()) {
+
+	queries := &[]loggedQuery{}
+	stderr := &bytes.Buffer{}
+
+	oldLogQuery, oldStderr, oldMode := logQuery, log.Stderr, log.DebugSQLMode
+	logQuery = func(query string, args []interface{}, duration time.Duration) {
+		assert.True(t, duration >= 0, "duration")
+		*queries = append(*queries, loggedQuery{query: query, args: args})
+	}
+	log.Stderr = stderr
+	log.DebugSQLMode = true
+
+	return queries, stderr, func() {
+		logQuery, log.Stderr, log.DebugSQLMode = oldLogQuery, oldStderr, oldMode
+	}
+
+}
+
+func Test_Register(t *testing.T) {
+
+	registerOnce.Do(func() {
+		Register("sqllog-basic", &fakeDriver{})
+		Register("sqllog-context", &fakeDriver{context: true})
+	})
+
+	for _, name := range []string{"sqllog-basic", "sqllog-context"} {
+		t.Run(name, func(t *testing.T) {
+
+			queries, stderr, restore := captureQueries(t)
+			defer restore()
+
+			db, err := sql.Open(name, "")
+			assert.NoError(t, err, "open")
+			defer db.Close()
+
+			_, err = db.Exec("insert into t values (?, ?)", 1, "a")
+			assert.NoError(t, err, "exec")
+
+			var value int
+			assert.NoError(t, db.QueryRow("select value from t where id = $1", 7).Scan(&value), "query")
+			assert.Equal(t, 42, value, "value")
+
+			stmt, err := db.Prepare("update t set a = ?")
+			assert.NoError(t, err, "prepare")
+			_, err = stmt.Exec(time.Duration(1))
+			assert.NoError(t, err, "exec-prepared")
+			stmt.Close()
+
+			tx, err := db.Begin()
+			assert.NoError(t, err, "begin")
+			_, err = tx.Exec("delete from t")
+			assert.NoError(t, err, "exec-tx")
+			assert.NoError(t, tx.Commit(), "commit")
+
+			_, err = db.Exec("fail")
+			assert.EqualError(t, err, "syntax error", "error")
+
+			assert.Equal(t, []loggedQuery{
+				{"insert into t values (?, ?)", []interface{}{int64(1), "a"}},
+				{"select value from t where id = $1", []interface{}{int64(7)}},
+				{"update t set a = ?", []interface{}{int64(1)}},
+				{"delete from t", []interface{}{}},
+				{"fail", []interface{}{}},
+			}, *queries, "queries")
+
+			assert.Contains(t, stderr.String(), "SQL statement failed", "failed")
+			assert.Contains(t, stderr.String(), `error="syntax error"`, "error-field")
+
+		})
+	}
+
+}
+
+func Test_logQuery(t *testing.T) {
+
+	stdout := &bytes.Buffer{}
+	oldStdout, oldDebugMode, oldMode := log.Stdout, log.DebugMode, log.DebugSQLMode
+	log.Stdout, log.DebugMode, log.DebugSQLMode = stdout, true, true
+	defer func() {
+		log.Stdout, log.DebugMode, log.DebugSQLMode = oldStdout, oldDebugMode, oldMode
+	}()
+
+	logQuery("select * from t where id = ?", []interface{}{int64(7)}, 1500*time.Millisecond)
+
+	assert.Contains(t, stdout.String(), "SQL statement", "message")
+	assert.Contains(t, stdout.String(), `statement="select * from t where id = ?"`, "statement")
+	assert.Contains(t, stdout.String(), "args=[7]", "args")
+	assert.Contains(t, stdout.String(), "duration=1.5s", "duration")
+
+}
+
+func Test_WrapConnector(t *testing.T) {
+
+	queries, _, restore := captureQueries(t)
+	defer restore()
+
+	db := sql.OpenDB(WrapConnector(dsnConnector{driver: &fakeDriver{context: true}}))
+	defer db.Close()
+
+	_, err := db.ExecContext(context.Background(), "insert into t values (?)", "x")
+	assert.NoError(t, err, "exec")
+	assert.NoError(t, db.Ping(), "ping")
+
+	assert.Len(t, *queries, 1, "queries")
+
+}
+
+func Test_OpenConnector(t *testing.T) {
+
+	queries, _, restore := captureQueries(t)
+	defer restore()
+
+	c, err := Wrap(&fakeDriver{}).(driver.DriverContext).OpenConnector("")
+	assert.NoError(t, err, "connector")
+
+	db := sql.OpenDB(c)
+	defer db.Close()
+
+	_, err = db.Exec("insert into t values (?)", "x")
+	assert.NoError(t, err, "exec")
+
+	assert.Len(t, *queries, 1, "queries")
+
+}
+
+func Test_DebugSQLMode_Disabled(t *testing.T) {
+
+	queries, _, restore := captureQueries(t)
+	defer restore()
+	log.DebugSQLMode = false
+
+	db := sql.OpenDB(WrapConnector(dsnConnector{driver: &fakeDriver{}}))
+	defer db.Close()
+
+	_, err := db.Exec("insert into t values (?)", 1)
+	assert.NoError(t, err, "exec")
+
+	assert.Empty(t, *queries, "queries")
+
+}
+
+func Test_values(t *testing.T) {
+
+	_, err := values([]driver.NamedValue{{Name: "id", Ordinal: 1, Value: 1}})
+	assert.Error(t, err, "named")
+
+	converted, err := values([]driver.NamedValue{{Ordinal: 1, Value: "a"}})
+	assert.NoError(t, err, "positional")
+	assert.Equal(t, []driver.Value{"a"}, converted, "values")
+
+}
+
+func TestMain(m *testing.M) {
+	log.Stdout = ioutil.Discard
+	os.Exit(m.Run())
+}
@@ -0,0 +1,340 @@
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// ColorStackTraces indicates if StackTrace colorizes the frames when its output is written to a terminal
+//
+// File names and functions are highlighted, frames of the runtime, the standard library and dependencies are dimmed.
+var ColorStackTraces = false
+
+// StackTraceSourceLines is the number of source lines shown around the top application frame by StackTrace (e.g. 2)
+//
+// The snippet is only shown when the source file can be read, 0 disables it.
+var StackTraceSourceLines = 0
+
+const (
+	ansiDim     = "\x1b[2m"
+	ansiBold    = "\x1b[1m"
+	ansiCyan    = "\x1b[36m"
+	ansiBoldRed = "\x1b[1;31m"
+)
+
+// stackError is an error with the stack where it was created
+type stackError interface {
+	error
+	TypeName() string
+	Callers() []uintptr
+	ErrorStack() string
+}
+
+func stackTraceMessage(err error) string {
+	w, _ := streamForLevel("ERROR")
+	color := ColorStackTraces && Format == FormatText && isTerminal(w)
+	return formatStackTrace(err, color, StackTraceSourceLines, 2)
+}
+
+// formatStackTrace formats the stack trace of err, skip is the number of frames between the caller of StackTrace or
+// FormattedStackTrace and formatStackTrace
+//
+// All the errors of the unwrap chain of err are shown with the stacks embedded in them (by github.com/pkg/errors,
+// github.com/go-errors/errors or StackTrace itself), the stack of the caller is only used when none of them has one.
+func formatStackTrace(err error, color bool, sourceLines int, skip int) string {
+
+	if err == nil {
+		return ""
+	}
+
+	chain := errorChain(err)
+
+	origin := -1
+	for i, link := range chain {
+		if link.stack != nil {
+			origin = i
+		}
+	}
+
+	if origin < 0 {
+		origin = len(chain) - 1
+		chain[origin].stack = newStackError(err, skip+1).Callers()
+	}
+
+	return formatStackChain(chain, origin, color, sourceLines)
+
+}
+
+// chainLink is an error of an unwrap chain with the stack embedded in it
+type chainLink struct {
+	err   error
+	stack []uintptr
+}
+
+// errorChain returns the errors wrapped by err using Unwrap or Cause, the wrappers which only add a stack to the error
+// they wrap are merged with it
+func errorChain(err error) []chainLink {
+
+	var chain []chainLink
+
+	for err != nil {
+		link := chainLink{err: err, stack: embeddedStack(err)}
+		if n := len(chain); n > 0 && chain[n-1].err.Error() == err.Error() {
+			if link.stack == nil {
+				link.stack = chain[n-1].stack
+			}
+			chain[n-1] = link
+		} else {
+			chain = append(chain, link)
+		}
+		next := unwrapError(err)
+		if next == err {
+			break
+		}
+		err = next
+	}
+
+	return chain
+
+}
+
+// embeddedStack returns the stack where err was created, nil if it doesn't have one
+func embeddedStack(err error) []uintptr {
+	if e, ok := err.(interface{ Callers() []uintptr }); ok {
+		return e.Callers()
+	}
+	return pkgErrorsStack(err)
+}
+
+// unwrapError returns the error wrapped by err, nil if it doesn't wrap one
+func unwrapError(err error) error {
+	switch e := err.(type) {
+	case interface{ Cause() error }:
+		return e.Cause()
+	case interface{ Unwrap() error }:
+		return e.Unwrap()
+	}
+	return unwrapGoError(err)
+}
+
+// errorTypeName returns the name of the type of err, errors wrapping another one can report the type of that one
+func errorTypeName(err error) string {
+	if e, ok := err.(interface{ TypeName() string }); ok {
+		return e.TypeName()
+	}
+	return reflect.TypeOf(err).String()
+}
+
+// formatStackChain shows the errors with their stacks, the frames which a stack has in common with the one shown
+// before it are left out (like "... 3 more" in Java) and the source snippet is shown for the stack of origin
+func formatStackChain(chain []chainLink, origin int, color bool, sourceLines int) string {
+
+	var b strings.Builder
+
+	sources := map[string][][]byte{}
+	var previous []uintptr
+
+	for i, link := range chain {
+
+		header := errorTypeName(link.err) + " " + link.err.Error()
+		if i > 0 {
+			header = "caused by: " + header
+		}
+		if color {
+			header = ansiBoldRed + header + ansiReset
+		}
+		b.WriteString(header + "\n")
+
+		if link.stack == nil {
+			continue
+		}
+
+		common := 0
+		for common < len(link.stack) && common < len(previous) &&
+			link.stack[len(link.stack)-1-common] == previous[len(previous)-1-common] {
+			common++
+		}
+
+		snippetLines := 0
+		if i == origin {
+			snippetLines = sourceLines
+		}
+
+		writeStackFrames(&b, sources, link.stack[:len(link.stack)-common], color, snippetLines)
+
+		if common > 0 {
+			more := fmt.Sprintf("\t... %d more", common)
+			if color {
+				more = ansiDim + more + ansiReset
+			}
+			b.WriteString(more + "\n")
+		}
+
+		previous = link.stack
+
+	}
+
+	return strings.TrimSpace(b.String())
+
+}
+
+// writeStackFrames writes the frames of the stack, with a snippet of sourceLines lines around the top application frame
+func writeStackFrames(b *strings.Builder, sources map[string][][]byte, stack []uintptr, color bool, sourceLines int) {
+
+	if len(stack) == 0 {
+		return
+	}
+
+	snippetShown := sourceLines <= 0
+
+	frames := runtime.CallersFrames(stack)
+	for {
+
+		frame, more := frames.Next()
+		frame = resolveFrame(frame)
+
+		library := isLibraryFrame(frame)
+		location := fmt.Sprintf("%s:%d (0x%x)", trimSourcePath(frame.File, frame.Function), frame.Line, frame.PC)
+		name := frameName(frame.Function)
+		source, sourceOK := sourceLine(sources, frame.File, frame.Line)
+
+		switch {
+		case color && library:
+			b.WriteString(ansiDim + location)
+			if sourceOK {
+				b.WriteString("\n\t" + name + ": " + source)
+			}
+			b.WriteString(ansiReset + "\n")
+		case color:
+			b.WriteString(ansiCyan + location + ansiReset + "\n")
+			if sourceOK {
+				b.WriteString("\t" + ansiBold + name + ansiReset + ": " + source + "\n")
+			}
+		default:
+			b.WriteString(location + "\n")
+			if sourceOK {
+				b.WriteString("\t" + name + ": " + source + "\n")
+			}
+		}
+
+		if !snippetShown && !library {
+			b.WriteString(sourceSnippet(frame.File, frame.Line, sourceLines, color))
+			snippetShown = true
+		}
+
+		if !more {
+			break
+		}
+
+	}
+
+}
+
+// panicStack returns the stack of the goroutine starting at the function which panicked, it must be called from the
+// deferred function which recovered the panic
+func panicStack() string {
+
+	pcs := make([]uintptr, 100)
+	n := runtime.Callers(2, pcs)
+	pcs = pcs[:n]
+
+	for i, pc := range pcs {
+		if frame, _ := runtime.CallersFrames([]uintptr{pc}).Next(); frame.Function == "runtime.gopanic" {
+			pcs = pcs[i+1:]
+			break
+		}
+	}
+
+	var b strings.Builder
+	writeStackFrames(&b, map[string][][]byte{}, pcs, false, 0)
+
+	return strings.TrimSpace(b.String())
+
+}
+
+// isLibraryFrame reports if the frame belongs to the runtime, the standard library or a dependency
+func isLibraryFrame(frame runtime.Frame) bool {
+	file := strings.Replace(frame.File, "\\", "/", -1)
+	if strings.Contains(file, "/vendor/") || strings.Contains(file, "/pkg/mod/") {
+		return true
+	}
+	// packages of the standard library don't have a dot in their first path element
+	pkg := funcPackage(frame.Function)
+	return !strings.Contains(strings.SplitN(pkg, "/", 2)[0], ".") && pkg != "main"
+}
+
+// frameName returns the name of the function without its package (e.g. "(*Buffer).String")
+func frameName(function string) string {
+	_, name := path.Split(function)
+	if i := strings.Index(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
+
+// sourceLine returns the trimmed source of the line in file, caching the files read in sources
+func sourceLine(sources map[string][][]byte, file string, line int) (string, bool) {
+
+	lines, ok := sources[file]
+	if !ok {
+		if data, err := ioutil.ReadFile(file); err == nil {
+			lines = bytes.Split(data, []byte{'\n'})
+		}
+		sources[file] = lines
+	}
+
+	if line <= 0 || line > len(lines) {
+		return "", false
+	}
+
+	return string(bytes.TrimSpace(lines[line-1])), true
+
+}
+
+// sourceSnippet returns the lines around line of file, marking the line itself
+func sourceSnippet(file string, line int, context int, color bool) string {
+
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return ""
+	}
+
+	lines := bytes.Split(data, []byte{'\n'})
+	if line <= 0 || line > len(lines) {
+		return ""
+	}
+
+	first, last := line-context, line+context
+	if first < 1 {
+		first = 1
+	}
+	if last > len(lines) {
+		last = len(lines)
+	}
+
+	width := len(fmt.Sprint(last))
+
+	var b strings.Builder
+	for i := first; i <= last; i++ {
+		text := strings.TrimRight(string(lines[i-1]), "\r")
+		marker := " "
+		if i == line {
+			marker = ">"
+		}
+		row := fmt.Sprintf("\t%s %*d | %s", marker, width, i, text)
+		if color && i != line {
+			row = ansiDim + row + ansiReset
+		} else if color {
+			row = ansiBold + row + ansiReset
+		}
+		b.WriteString(row + "\n")
+	}
+
+	return b.String()
+
+}
@@ -0,0 +1,146 @@
+package log
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/go-errors/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_isLibraryFrame(t *testing.T) {
+
+	type test struct {
+		name     string
+		frame    runtime.Frame
+		expected bool
+	}
+
+	var tests = []test{
+		{"runtime", runtime.Frame{Function: "runtime.goexit", File: "/usr/local/go/src/runtime/proc.go"}, true},
+		{"stdlib", runtime.Frame{Function: "net/http.(*conn).serve", File: "/usr/local/go/src/net/http/server.go"}, true},
+		{"main", runtime.Frame{Function: "main.main", File: "/src/app/main.go"}, false},
+		{"application", runtime.Frame{Function: "github.com/user/app.Run", File: "/src/app/app.go"}, false},
+		{"module-cache", runtime.Frame{Function: "github.com/lib/pq.(*conn).query", File: "/go/pkg/mod/github.com/lib/pq@v1.0.0/conn.go"}, true},
+		{"vendor", runtime.Frame{Function: "github.com/lib/pq.(*conn).query", File: "/src/app/vendor/github.com/lib/pq/conn.go"}, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, isLibraryFrame(tc.frame))
+		})
+	}
+
+}
+
+func Test_frameName(t *testing.T) {
+	assert.Equal(t, "(*Buffer).String", frameName("bytes.(*Buffer).String"), "method")
+	assert.Equal(t, "Run.func1", frameName("github.com/user/app.Run.func1"), "closure")
+	assert.Equal(t, "main", frameName("main.main"), "main")
+}
+
+func Test_sourceSnippet(t *testing.T) {
+
+	dir, _ := ioutil.TempDir("", "go-log")
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "source.go")
+	ioutil.WriteFile(path, []byte("one\ntwo\nthree\nfour\nfive\n"), 0644)
+
+	type test struct {
+		name     string
+		line     int
+		color    bool
+		expected string
+	}
+
+	var tests = []test{
+		{"middle", 3, false, "\t  1 | one\n\t  2 | two\n\t> 3 | three\n\t  4 | four\n\t  5 | five\n"},
+		{"first", 1, false, "\t> 1 | one\n\t  2 | two\n\t  3 | three\n"},
+		{"color", 1, true, "\x1b[1m\t> 1 | one\x1b[0m\n\x1b[2m\t  2 | two\x1b[0m\n\x1b[2m\t  3 | three\x1b[0m\n"},
+		{"out-of-range", 10, false, ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, sourceSnippet(path, tc.line, 2, tc.color))
+		})
+	}
+
+	assert.Equal(t, "", sourceSnippet(filepath.Join(dir, "missing.go"), 1, 2, false), "missing")
+
+}
+
+func Test_stackTraceMessage_Color(t *testing.T) {
+
+	oldIsTerminal := isTerminal
+	defer func() {
+		isTerminal = oldIsTerminal
+		ColorStackTraces = false
+	}()
+
+	isTerminal = func(w io.Writer) bool {
+		return true
+	}
+	ColorStackTraces = true
+
+	actual := stackTraceMessage(errors.New("my error"))
+
+	assert.True(t, strings.HasPrefix(actual, ansiBoldRed+"*errors.errorString my error"+ansiReset+"\n"), "header")
+	assert.Contains(t, actual, ansiCyan, "application-frame")
+	assert.Contains(t, actual, ansiDim, "library-frame")
+
+	ColorStackTraces = false
+	assert.Equal(t, "*errors.errorString my error", strings.SplitN(stackTraceMessage(errors.New("my error")), "\n", 2)[0], "plain")
+
+}
+
+func Test_errorChain(t *testing.T) {
+
+	goErr := errors.Wrap(io.EOF, 0)
+	wrapped := fmt.Errorf("reading: %w", goErr)
+
+	type test struct {
+		name     string
+		err      error
+		expected []string
+		stacks   []bool
+	}
+
+	var tests = []test{
+		{"plain", io.EOF, []string{"EOF"}, []bool{false}},
+		{"wrapped", fmt.Errorf("reading: %w", io.EOF), []string{"reading: EOF", "EOF"}, []bool{false, false}},
+		{"go-errors", goErr, []string{"EOF"}, []bool{true}},
+		{"go-errors-wrapped", wrapped, []string{"reading: EOF", "EOF"}, []bool{false, true}},
+		{"cause", newCustomError("custom error"), []string{"custom error", "cause of error"}, []bool{false, true}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			chain := errorChain(tc.err)
+
+			var actual []string
+			var stacks []bool
+			for _, link := range chain {
+				actual = append(actual, link.err.Error())
+				stacks = append(stacks, link.stack != nil)
+			}
+
+			assert.Equal(t, tc.expected, actual, "errors")
+			assert.Equal(t, tc.stacks, stacks, "stacks")
+
+		})
+	}
+
+	chain := errorChain(goErr)
+	assert.Equal(t, io.EOF, chain[0].err, "merged")
+	assert.Equal(t, goErr.Callers(), chain[0].stack, "stack")
+
+}
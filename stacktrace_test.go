@@ -0,0 +1,118 @@
+package log_test
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func Test_StackTrace_SourceLines(t *testing.T) {
+
+	resetLogConfig()
+	_, stderr := redirectOutput()
+	defer resetLogOutput()
+	defer func() {
+		log.StackTraceSourceLines = 0
+	}()
+
+	log.StackTraceSourceLines = 2
+
+	log.StackTrace(errors.New("my error"))
+
+	actual := stderr.String()
+
+	assert.True(t, strings.HasPrefix(actual, "test | ERROR | *errors.fundamental my error\n"), "header")
+	assert.Contains(t, actual, " | \tlog.StackTrace(errors.New(\"my error\"))\n", "snippet")
+	assert.Equal(t, 1, strings.Count(actual, "\t> "), "top-frame-only")
+	assert.NotContains(t, actual, "\x1b[", "no-color")
+
+}
+
+func Test_FormattedStackTrace_TrimSourcePaths(t *testing.T) {
+
+	defer func() {
+		log.TrimSourcePaths = false
+	}()
+
+	log.TrimSourcePaths = true
+
+	lines := strings.Split(log.FormattedStackTrace(errors.New("my error")), "\n")
+
+	assert.Equal(t, "*errors.fundamental my error", lines[0], "header")
+	for _, line := range lines[1:] {
+		if !strings.HasPrefix(line, "\t") {
+			assert.False(t, strings.HasPrefix(line, "/"), line)
+		}
+	}
+
+}
+
+func newOriginError() error {
+	return errors.New("boom")
+}
+
+func Test_FormattedStackTrace_EmbeddedStacks(t *testing.T) {
+
+	type test struct {
+		name     string
+		err      error
+		headers  []string
+		frames   []string
+		noFrames []string
+	}
+
+	var tests = []test{
+		{
+			"pkg-errors",
+			errors.Wrap(newOriginError(), "loading"),
+			[]string{"*errors.withMessage loading: boom", "caused by: *errors.fundamental boom"},
+			[]string{"\tnewOriginError: return errors.New(\"boom\")", "\t... "},
+			nil,
+		},
+		{
+			"wrapped",
+			fmt.Errorf("loading: %w", newOriginError()),
+			[]string{"*fmt.wrapError loading: boom", "caused by: *errors.fundamental boom"},
+			[]string{"\tnewOriginError: return errors.New(\"boom\")"},
+			[]string{"\t... "},
+		},
+		{
+			"without-stack",
+			fmt.Errorf("loading: %w", io.EOF),
+			[]string{"*fmt.wrapError loading: EOF", "caused by: *errors.errorString EOF"},
+			[]string{"stacktrace_test.go:"},
+			[]string{"newOriginError"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			actual := log.FormattedStackTrace(tc.err)
+			lines := strings.Split(actual, "\n")
+
+			assert.Equal(t, tc.headers[0], lines[0], "header")
+			for _, header := range tc.headers[1:] {
+				assert.Contains(t, lines, header, "cause")
+			}
+			for _, frame := range tc.frames {
+				assert.Contains(t, actual, frame, "frames")
+			}
+			for _, frame := range tc.noFrames {
+				assert.NotContains(t, actual, frame, "no-frames")
+			}
+
+		})
+	}
+
+}
+
+func Test_FormattedStackTrace_Nil(t *testing.T) {
+	assert.Equal(t, "", log.FormattedStackTrace(nil))
+}
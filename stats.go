@@ -0,0 +1,78 @@
+package log
+
+import (
+	"time"
+)
+
+// SinkStats contains the counters of a single output of the log pipeline
+type SinkStats struct {
+	Name       string `json:"name"`
+	Entries    uint64 `json:"entries"`
+	Bytes      uint64 `json:"bytes"`
+	Errors     uint64 `json:"errors"`
+	Retries    uint64 `json:"retries"`
+	QueueDepth int    `json:"queue_depth"`
+
+	// The latencies of the entries written by the output, only measured when MeasureLatency is enabled
+	LastLatency    time.Duration `json:"last_latency_ns"`
+	AverageLatency time.Duration `json:"average_latency_ns"`
+	MaxLatency     time.Duration `json:"max_latency_ns"`
+}
+
+type sinkState struct {
+	name          string
+	entries       uint64
+	bytes         uint64
+	errors        uint64
+	retries       uint64
+	queueDepth    int
+	latencies     uint64
+	latencyTotal  time.Duration
+	latencyLast   time.Duration
+	latencyMax    time.Duration
+	lastError     error
+	lastErrorTime time.Time
+}
+
+// Stats returns the counters for each output which received log entries
+func Stats() []SinkStats {
+
+	health.mutex.Lock()
+	defer health.mutex.Unlock()
+
+	stats := []SinkStats{}
+	for _, sink := range health.sinks {
+		stats = append(stats, SinkStats{
+			Name:       sink.name,
+			Entries:    sink.entries,
+			Bytes:      sink.bytes,
+			Errors:     sink.errors,
+			Retries:    sink.retries,
+			QueueDepth: sink.queueDepth,
+
+			LastLatency:    sink.latencyLast,
+			AverageLatency: sink.averageLatency(),
+			MaxLatency:     sink.latencyMax,
+		})
+	}
+
+	return stats
+
+}
+
+// SinkStatsFor returns the counters for the output with the given name
+func SinkStatsFor(name string) (SinkStats, bool) {
+	for _, stats := range Stats() {
+		if stats.Name == name {
+			return stats, true
+		}
+	}
+	return SinkStats{Name: name}, false
+}
+
+func (s *sinkState) averageLatency() time.Duration {
+	if s.latencies == 0 {
+		return 0
+	}
+	return s.latencyTotal / time.Duration(s.latencies)
+}
@@ -0,0 +1,55 @@
+package log_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func Test_Stats(t *testing.T) {
+
+	resetLogConfig()
+	redirectOutput()
+	defer resetLogOutput()
+
+	log.PrintTimestamp = false
+
+	before, _ := log.SinkStatsFor("stdout")
+
+	log.Info("info")
+
+	after, found := log.SinkStatsFor("stdout")
+
+	assert.True(t, found, "found")
+	assert.Equal(t, before.Entries+1, after.Entries, "entries")
+	assert.Equal(t, before.Bytes+5, after.Bytes, "bytes")
+	assert.Equal(t, before.Errors, after.Errors, "errors")
+
+}
+
+func Test_Stats_Errors(t *testing.T) {
+
+	resetLogConfig()
+	redirectOutput()
+	defer resetLogOutput()
+
+	log.Stderr = failingWriter{}
+
+	before, _ := log.SinkStatsFor("stderr")
+
+	log.Error("error")
+
+	after, _ := log.SinkStatsFor("stderr")
+
+	assert.Equal(t, before.Entries, after.Entries, "entries")
+	assert.Equal(t, before.Errors+1, after.Errors, "errors")
+
+}
+
+func Test_SinkStatsFor_Unknown(t *testing.T) {
+	stats, found := log.SinkStatsFor("unknown")
+	assert.False(t, found)
+	assert.Equal(t, "unknown", stats.Name)
+}
@@ -0,0 +1,73 @@
+//go:build !tinygo
+// +build !tinygo
+
+package log
+
+import (
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// StatsDHook sends per-level entry counters and metrics to a StatsD or DogStatsD endpoint over UDP
+type StatsDHook struct {
+	conn   net.Conn
+	prefix string
+}
+
+var statsdMutex = &sync.RWMutex{}
+var statsdHook *StatsDHook
+
+// NewStatsDHook creates a hook which sends to the StatsD endpoint at addr (e.g. "127.0.0.1:8125")
+//
+// The prefix is prepended to all metric names (e.g. "myapp.")
+func NewStatsDHook(addr string, prefix string) (*StatsDHook, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return &StatsDHook{conn: conn, prefix: prefix}, nil
+}
+
+// SetStatsDHook installs the StatsD hook (nil removes it)
+//
+// While a hook is installed, each log entry increments the "log.entries" counter tagged with its level and the
+// values passed to Metric and MetricCount are sent to StatsD instead of being written as log lines.
+func SetStatsDHook(h *StatsDHook) {
+	statsdMutex.Lock()
+	statsdHook = h
+	statsdMutex.Unlock()
+}
+
+// Close closes the connection to the StatsD endpoint
+func (h *StatsDHook) Close() error {
+	return h.conn.Close()
+}
+
+func currentStatsDHook() *StatsDHook {
+	statsdMutex.RLock()
+	defer statsdMutex.RUnlock()
+	return statsdHook
+}
+
+func (h *StatsDHook) countEntry(level string) {
+	level = strings.ToLower(strings.TrimSpace(level))
+	h.send("log.entries", 1, MetricCounter, []string{"level:" + level})
+}
+
+func (h *StatsDHook) send(name string, value float64, metricType MetricType, tags []string) {
+
+	statsdType := "g"
+	if metricType == MetricCounter {
+		statsdType = "c"
+	}
+
+	packet := h.prefix + name + ":" + strconv.FormatFloat(value, 'f', -1, 64) + "|" + statsdType
+	if len(tags) > 0 {
+		packet += "|#" + strings.Join(tags, ",")
+	}
+
+	h.conn.Write([]byte(packet))
+
+}
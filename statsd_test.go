@@ -0,0 +1,57 @@
+//go:build !tinygo
+// +build !tinygo
+
+package log_test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func readPacket(t *testing.T, conn net.PacketConn) string {
+	buf := make([]byte, 1024)
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	assert.NoError(t, err, "read")
+	return string(buf[:n])
+}
+
+func Test_StatsDHook(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+
+	server, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.NoError(t, err, "listen")
+	defer server.Close()
+
+	hook, err := log.NewStatsDHook(server.LocalAddr().String(), "app.")
+	assert.NoError(t, err, "dial")
+	defer hook.Close()
+
+	log.SetStatsDHook(hook)
+	defer log.SetStatsDHook(nil)
+
+	log.Warn("warning")
+	assert.Equal(t, "app.log.entries:1|c|#level:warn", readPacket(t, server), "entries")
+
+	log.Metric("queue_depth", 42, map[string]string{"queue": "jobs"})
+	assert.Equal(t, "app.queue_depth:42|g|#queue:jobs", readPacket(t, server), "gauge")
+
+	log.MetricCount("jobs", 1.5, nil)
+	assert.Equal(t, "app.jobs:1.5|c", readPacket(t, server), "count")
+
+	assert.Equal(t, "test | WARN  | warning\n", stdout.String(), "stdout")
+
+}
+
+func Test_NewStatsDHook_Invalid(t *testing.T) {
+	_, err := log.NewStatsDHook("invalid", "")
+	assert.Error(t, err)
+}
@@ -0,0 +1,15 @@
+//go:build tinygo
+// +build tinygo
+
+package log
+
+// StatsDHook is not available when building with TinyGo, the metrics are written as JSON lines instead
+type StatsDHook struct{}
+
+func currentStatsDHook() *StatsDHook {
+	return nil
+}
+
+func (h *StatsDHook) countEntry(level string) {}
+
+func (h *StatsDHook) send(name string, value float64, metricType MetricType, tags []string) {}
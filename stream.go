@@ -0,0 +1,91 @@
+package log
+
+import (
+	"io"
+	"strings"
+	"sync"
+)
+
+// Stream identifies one of the standard output writers
+type Stream int
+
+const (
+	// StreamStdout writes to Stdout
+	StreamStdout Stream = iota
+
+	// StreamStderr writes to Stderr
+	StreamStderr
+)
+
+// LevelStreams maps the levels ("TRACE", "DEBUG", "INFO", "NOTE", "WARN", "ERROR" and "FATAL") to the stream they
+// are written to
+//
+// Levels which are not in the map are written to Stdout
+var LevelStreams = DefaultLevelStreams()
+
+// DefaultLevelStreams returns the default mapping which writes errors and fatal errors to Stderr
+func DefaultLevelStreams() map[string]Stream {
+	return map[string]Stream{
+		"ERROR": StreamStderr,
+		"FATAL": StreamStderr,
+	}
+}
+
+// AllLevelsToStderr returns a mapping which writes all levels to Stderr, for command line tools which use Stdout
+// for their data
+func AllLevelsToStderr() map[string]Stream {
+	return map[string]Stream{
+		"TRACE": StreamStderr,
+		"DEBUG": StreamStderr,
+		"INFO":  StreamStderr,
+		"NOTE":  StreamStderr,
+		"WARN":  StreamStderr,
+		"ERROR": StreamStderr,
+		"FATAL": StreamStderr,
+	}
+}
+
+var levelOutputsMutex = &sync.RWMutex{}
+
+// levelOutputs are the writers set with SetOutput
+var levelOutputs = map[string]io.Writer{}
+
+// SetOutput writes the entries of the level to w instead of the stream of the level in LevelStreams (nil restores
+// the stream)
+//
+// It applies to the default logger (and the loggers derived from it with WithField) and to the loggers created with
+// New which don't have their own writer for the stream of the level.
+// Use io.MultiWriter to write the entries to multiple outputs, such as Stderr and a file.
+func SetOutput(level Level, w io.Writer) {
+	levelOutputsMutex.Lock()
+	defer levelOutputsMutex.Unlock()
+	if w == nil {
+		delete(levelOutputs, level.String())
+		return
+	}
+	levelOutputs[level.String()] = w
+}
+
+func streamForLevel(level string) (io.Writer, string) {
+	return std.streamForLevel(level)
+}
+
+func (l *Logger) streamForLevel(level string) (io.Writer, string) {
+
+	level = strings.TrimSpace(strings.ToUpper(level))
+	stderr := LevelStreams[level] == StreamStderr
+
+	levelOutputsMutex.RLock()
+	w, ok := levelOutputs[level]
+	levelOutputsMutex.RUnlock()
+
+	if ok && (l.isDefault() || (stderr && l.Stderr == nil) || (!stderr && l.Stdout == nil)) {
+		return w, strings.ToLower(level)
+	}
+
+	if stderr {
+		return l.stderr(), "stderr"
+	}
+	return l.stdout(), "stdout"
+
+}
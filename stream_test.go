@@ -0,0 +1,108 @@
+package log_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func Test_LevelStreams(t *testing.T) {
+
+	type test struct {
+		name           string
+		streams        map[string]log.Stream
+		expectedStdout string
+		expectedStderr string
+	}
+
+	var tests = []test{
+		{"default", log.DefaultLevelStreams(), "test | INFO  | info\ntest | WARN  | warn\n", "test | ERROR | error\n"},
+		{"warn-to-stderr", map[string]log.Stream{"WARN": log.StreamStderr, "ERROR": log.StreamStderr}, "test | INFO  | info\n", "test | WARN  | warn\ntest | ERROR | error\n"},
+		{"all-to-stderr", log.AllLevelsToStderr(), "", "test | INFO  | info\ntest | WARN  | warn\ntest | ERROR | error\n"},
+		{"all-to-stdout", map[string]log.Stream{}, "test | INFO  | info\ntest | WARN  | warn\ntest | ERROR | error\n", ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			resetLogConfig()
+			stdout, stderr := redirectOutput()
+			defer resetLogOutput()
+
+			log.LevelStreams = tc.streams
+			defer func() {
+				log.LevelStreams = log.DefaultLevelStreams()
+			}()
+
+			log.Info("info")
+			log.Warn("warn")
+			log.Error("error")
+
+			assert.Equal(t, tc.expectedStdout, stdout.String(), "stdout")
+			assert.Equal(t, tc.expectedStderr, stderr.String(), "stderr")
+
+		})
+	}
+
+}
+
+func Test_SetOutput(t *testing.T) {
+
+	resetLogConfig()
+	stdout, stderr := redirectOutput()
+	defer resetLogOutput()
+
+	warnings := &bytes.Buffer{}
+	defer func() {
+		for _, level := range []log.Level{log.WarnLevel, log.ErrorLevel, log.FatalLevel} {
+			log.SetOutput(level, nil)
+		}
+	}()
+
+	for _, level := range []log.Level{log.WarnLevel, log.ErrorLevel, log.FatalLevel} {
+		log.SetOutput(level, io.MultiWriter(log.Stderr, warnings))
+	}
+
+	log.DebugMode = true
+	log.Debug("debug")
+	log.Info("info")
+	log.Warn("warn")
+	log.Error("error")
+
+	assert.Equal(t, "test | DEBUG | debug\ntest | INFO  | info\n", stdout.String(), "stdout")
+	assert.Equal(t, "test | WARN  | warn\ntest | ERROR | error\n", stderr.String(), "stderr")
+	assert.Equal(t, "test | WARN  | warn\ntest | ERROR | error\n", warnings.String(), "warnings")
+
+	_, found := log.SinkStatsFor("warn")
+	assert.True(t, found, "stats")
+
+	log.SetOutput(log.WarnLevel, nil)
+	log.Warn("restored")
+
+	assert.Equal(t, "test | DEBUG | debug\ntest | INFO  | info\ntest | WARN  | restored\n", stdout.String(), "restored")
+
+}
+
+func Test_SetOutput_LoggerOutputs(t *testing.T) {
+
+	resetLogConfig()
+	redirectOutput()
+	defer resetLogOutput()
+	defer log.SetOutput(log.InfoLevel, nil)
+
+	shared := &bytes.Buffer{}
+	own := &bytes.Buffer{}
+	log.SetOutput(log.InfoLevel, shared)
+
+	log.WithField("component", "db").Info("shared")
+	log.New(log.WithOutput(own, nil)).Info("own")
+	log.New(log.WithOutput(nil, nil)).Info("no-own")
+
+	assert.Equal(t, "test | INFO  | shared component=db\nno-own\n", shared.String(), "shared")
+	assert.Equal(t, "own\n", own.String(), "own")
+
+}
@@ -0,0 +1,188 @@
+//go:build !tinygo
+// +build !tinygo
+
+package log
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// SyslogFacility is the facility used for the messages sent by a SyslogWriter (defaults to 1, user-level messages)
+var SyslogFacility = 1
+
+// syslogSockets are the paths tried for the local syslog daemon
+var syslogSockets = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// ErrSyslogUnavailable is returned when no local syslog daemon could be found
+var ErrSyslogUnavailable = errors.New("no local syslog daemon found")
+
+// SyslogWriter is a writer which sends the log entries as RFC 5424 messages to a syslog daemon
+//
+// The levels are mapped to the syslog severities and the syslog daemon adds the timestamps, so the entries don't
+// include them, regardless of PrintTimestamp. Stream connections use octet counting framing (RFC 6587).
+type SyslogWriter struct {
+	mutex    sync.Mutex
+	network  string
+	addr     string
+	tag      string
+	hostname string
+	conn     net.Conn
+	closed   bool
+}
+
+// ToSyslog connects to the syslog daemon and uses it as Stdout and Stderr
+//
+// An empty network and addr connects to the local syslog daemon. The tag is used as the app name of the messages
+// and defaults to the name of the executable.
+func ToSyslog(network string, addr string, tag string) (*SyslogWriter, error) {
+
+	w, err := NewSyslogWriter(network, addr, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	Stdout = w
+	Stderr = w
+
+	return w, nil
+
+}
+
+// NewSyslogWriter connects to the syslog daemon at addr (e.g. "udp", "logs.example.com:514")
+//
+// An empty network and addr connects to the local syslog daemon.
+func NewSyslogWriter(network string, addr string, tag string) (*SyslogWriter, error) {
+
+	if tag == "" {
+		tag = filepath.Base(os.Args[0])
+	}
+
+	hostname, _ := os.Hostname()
+	if hostname == "" {
+		hostname = "-"
+	}
+
+	w := &SyslogWriter{network: network, addr: addr, tag: tag, hostname: hostname}
+	if err := w.connect(); err != nil {
+		return nil, err
+	}
+
+	return w, nil
+
+}
+
+// Write sends p as an info message
+func (w *SyslogWriter) Write(p []byte) (int, error) {
+	return w.writeLevel("INFO", p)
+}
+
+// Close closes the connection to the syslog daemon
+func (w *SyslogWriter) Close() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.closed = true
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
+
+func (w *SyslogWriter) printTimestamp() bool {
+	return false
+}
+
+// writeLevel sends p with the severity of the level, reconnecting when the connection was lost
+func (w *SyslogWriter) writeLevel(level string, p []byte) (int, error) {
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.closed {
+		return 0, ErrSinkClosed
+	}
+
+	if w.conn != nil {
+		if _, err := w.conn.Write(w.format(level, time.Now(), p)); err == nil {
+			return len(p), nil
+		}
+		w.conn.Close()
+		w.conn = nil
+	}
+
+	if err := w.connect(); err != nil {
+		return 0, err
+	}
+
+	if _, err := w.conn.Write(w.format(level, time.Now(), p)); err != nil {
+		w.conn.Close()
+		w.conn = nil
+		return 0, err
+	}
+
+	return len(p), nil
+
+}
+
+// format returns the RFC 5424 message, framed for stream connections
+func (w *SyslogWriter) format(level string, t time.Time, p []byte) []byte {
+
+	severity, ok := syslogSeverities[level]
+	if !ok {
+		severity = syslogSeverities["INFO"]
+	}
+
+	var b bytes.Buffer
+	b.WriteString("<" + strconv.Itoa(SyslogFacility*8+severity) + ">1 ")
+	b.WriteString(t.UTC().Format("2006-01-02T15:04:05.000000Z07:00"))
+	b.WriteString(" " + w.hostname + " " + w.tag + " " + strconv.Itoa(os.Getpid()) + " - - ")
+	b.Write(bytes.TrimRight(p, "\n"))
+
+	if !isStreamNetwork(w.conn.RemoteAddr().Network()) {
+		return b.Bytes()
+	}
+
+	return append([]byte(strconv.Itoa(b.Len())+" "), b.Bytes()...)
+
+}
+
+func (w *SyslogWriter) connect() error {
+
+	if w.network != "" || w.addr != "" {
+		conn, err := net.Dial(w.network, w.addr)
+		if err != nil {
+			return err
+		}
+		w.conn = conn
+		return nil
+	}
+
+	for _, path := range syslogSockets {
+		for _, network := range []string{"unixgram", "unix"} {
+			if conn, err := net.Dial(network, path); err == nil {
+				w.conn = conn
+				return nil
+			}
+		}
+	}
+
+	return ErrSyslogUnavailable
+
+}
+
+func isStreamNetwork(network string) bool {
+	switch network {
+	case "tcp", "tcp4", "tcp6", "unix":
+		return true
+	default:
+		return false
+	}
+}
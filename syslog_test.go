@@ -0,0 +1,115 @@
+//go:build !tinygo
+// +build !tinygo
+
+package log_test
+
+import (
+	"bufio"
+	"net"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func Test_ToSyslog_UDP(t *testing.T) {
+
+	resetLogConfig()
+	defer resetLogOutput()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.NoError(t, err, "listen")
+	defer conn.Close()
+
+	w, err := log.ToSyslog("udp", conn.LocalAddr().String(), "myapp")
+	assert.NoError(t, err, "to-syslog")
+	defer w.Close()
+
+	hostname, _ := os.Hostname()
+	suffix := " " + hostname + " myapp " + strconv.Itoa(os.Getpid()) + " - - "
+
+	type test struct {
+		name     string
+		log      func()
+		expected string
+	}
+
+	var tests = []test{
+		{"info", func() { log.Info("info") }, "<14>1 "},
+		{"notice", func() { log.Notice("notice") }, "<13>1 "},
+		{"warn", func() { log.Warn("warn") }, "<12>1 "},
+		{"error", func() { log.Error("error") }, "<11>1 "},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			tc.log()
+
+			buf := make([]byte, 1024)
+			conn.SetReadDeadline(time.Now().Add(time.Second))
+			n, _, err := conn.ReadFrom(buf)
+			assert.NoError(t, err, "read")
+
+			assert.Regexp(t, `^`+tc.expected+`\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}\.\d{6}Z`+suffix+tc.name+`$`, string(buf[:n]), "message")
+
+		})
+	}
+
+}
+
+func Test_SyslogWriter_TCP(t *testing.T) {
+
+	resetLogConfig()
+	defer resetLogOutput()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err, "listen")
+	defer listener.Close()
+
+	w, err := log.NewSyslogWriter("tcp", listener.Addr().String(), "myapp")
+	assert.NoError(t, err, "new")
+	defer w.Close()
+
+	conn, err := listener.Accept()
+	assert.NoError(t, err, "accept")
+	defer conn.Close()
+
+	log.Stdout = w
+	log.Info("hello")
+
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	r := bufio.NewReader(conn)
+
+	length, err := r.ReadString(' ')
+	assert.NoError(t, err, "read-length")
+
+	n, _ := strconv.Atoi(length[:len(length)-1])
+	msg := make([]byte, n)
+	_, err = r.Read(msg)
+	assert.NoError(t, err, "read-message")
+
+	assert.Regexp(t, `^<14>1 .* myapp \d+ - - hello$`, string(msg), "message")
+
+}
+
+func Test_SyslogWriter_Closed(t *testing.T) {
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.NoError(t, err, "listen")
+	defer conn.Close()
+
+	w, err := log.NewSyslogWriter("udp", conn.LocalAddr().String(), "")
+	assert.NoError(t, err, "new")
+
+	assert.NoError(t, w.Close(), "close")
+	assert.NoError(t, w.Close(), "close-twice")
+
+	_, err = w.Write([]byte("message\n"))
+	assert.Equal(t, log.ErrSinkClosed, err, "write")
+
+}
@@ -0,0 +1,294 @@
+//go:build !tinygo
+// +build !tinygo
+
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNoTelemetryEndpoint is returned by NewTelemetry when no endpoint is given
+var ErrNoTelemetryEndpoint = errors.New("telemetry endpoint is not set")
+
+// TelemetryOptions are the options used to create a Telemetry
+type TelemetryOptions struct {
+	// Endpoint is the URL to which the batches of usage entries are posted as a JSON array
+	Endpoint string
+
+	// BatchSize is the number of entries after which a batch is sent (defaults to 50)
+	BatchSize int
+
+	// FlushInterval is the interval at which the pending entries are sent (defaults to 1 minute)
+	FlushInterval time.Duration
+
+	// MaxPending is the number of entries kept while the batches can't be sent, the oldest ones are dropped (defaults
+	// to 10 times BatchSize)
+	MaxPending int
+
+	// ConsentFile is where the consent of the user is stored, so that it's remembered across runs (optional)
+	ConsentFile string
+
+	// Client is the HTTP client used to send the batches (defaults to a client with a 10 second timeout)
+	Client *http.Client
+}
+
+// Telemetry batches the entries logged with Usage and sends them to an endpoint, separately from the other outputs
+//
+// Nothing is collected until the user gives their consent with SetConsent, and nothing is collected while the
+// DO_NOT_TRACK environment variable is set, whatever the consent is.
+type Telemetry struct {
+	opts    TelemetryOptions
+	mutex   sync.Mutex
+	consent bool
+	pending []usageEntry
+	failing bool
+	sending sync.Mutex
+	flush   chan struct{}
+	stop    chan struct{}
+	done    chan struct{}
+	closed  bool
+}
+
+type usageEntry struct {
+	Time          string                 `json:"time"`
+	Feature       string                 `json:"feature"`
+	Properties    map[string]interface{} `json:"properties"`
+	SchemaVersion int                    `json:"schema_version"`
+}
+
+var telemetryMutex = &sync.RWMutex{}
+var telemetry *Telemetry
+
+// NewTelemetry creates a Telemetry which sends to the endpoint, its consent is read from the ConsentFile
+func NewTelemetry(opts TelemetryOptions) (*Telemetry, error) {
+
+	if opts.Endpoint == "" {
+		return nil, ErrNoTelemetryEndpoint
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 50
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = time.Minute
+	}
+	if opts.MaxPending <= 0 {
+		opts.MaxPending = 10 * opts.BatchSize
+	}
+	if opts.Client == nil {
+		opts.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	t := &Telemetry{
+		opts:  opts,
+		flush: make(chan struct{}, 1),
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+
+	if opts.ConsentFile != "" {
+		data, err := ioutil.ReadFile(opts.ConsentFile)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		t.consent = strings.TrimSpace(string(data)) == "granted"
+	}
+
+	go t.run()
+
+	return t, nil
+
+}
+
+// SetTelemetry installs the telemetry used by Usage (nil removes it)
+func SetTelemetry(t *Telemetry) {
+	telemetryMutex.Lock()
+	telemetry = t
+	telemetryMutex.Unlock()
+}
+
+func currentTelemetry() *Telemetry {
+	telemetryMutex.RLock()
+	defer telemetryMutex.RUnlock()
+	return telemetry
+}
+
+// SetConsent records if the user agrees to send the usage entries and stores it in the ConsentFile
+//
+// Revoking the consent drops the entries which weren't sent yet.
+func (t *Telemetry) SetConsent(granted bool) error {
+
+	t.mutex.Lock()
+	t.consent = granted
+	if !granted {
+		t.pending = nil
+	}
+	t.mutex.Unlock()
+
+	if t.opts.ConsentFile == "" {
+		return nil
+	}
+
+	value := "denied"
+	if granted {
+		value = "granted"
+	}
+
+	if err := os.MkdirAll(filepath.Dir(t.opts.ConsentFile), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(t.opts.ConsentFile, []byte(value+"\n"), 0644)
+
+}
+
+// Enabled indicates if the usage entries are collected, which requires the consent of the user and DO_NOT_TRACK
+// not being set
+func (t *Telemetry) Enabled() bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.consent && os.Getenv("DO_NOT_TRACK") == ""
+}
+
+// Flush sends the pending entries, they are kept to be sent again later when sending them fails
+func (t *Telemetry) Flush() error {
+
+	t.sending.Lock()
+	defer t.sending.Unlock()
+
+	t.mutex.Lock()
+	batch := t.pending
+	t.pending = nil
+	t.mutex.Unlock()
+
+	err := t.send(batch)
+
+	t.mutex.Lock()
+	t.failing = err != nil
+	if err != nil && t.consent {
+		t.pending = append(batch, t.pending...)
+		if len(t.pending) > t.opts.MaxPending {
+			t.pending = t.pending[len(t.pending)-t.opts.MaxPending:]
+		}
+	}
+	t.mutex.Unlock()
+
+	return err
+
+}
+
+// Close sends the pending entries and stops the Telemetry, the entries logged afterwards are dropped
+func (t *Telemetry) Close() error {
+
+	t.mutex.Lock()
+	if t.closed {
+		t.mutex.Unlock()
+		return nil
+	}
+	t.closed = true
+	t.mutex.Unlock()
+
+	close(t.stop)
+	<-t.done
+
+	return t.Flush()
+
+}
+
+func (t *Telemetry) add(feature string, properties map[string]interface{}) {
+
+	if !t.Enabled() {
+		return
+	}
+
+	entry := usageEntry{
+		Time:          time.Now().UTC().Format(time.RFC3339Nano),
+		Feature:       feature,
+		Properties:    applyAllowList(properties),
+		SchemaVersion: SchemaVersion,
+	}
+
+	t.mutex.Lock()
+	if t.closed {
+		t.mutex.Unlock()
+		return
+	}
+	t.pending = append(t.pending, entry)
+	if len(t.pending) > t.opts.MaxPending {
+		t.pending = t.pending[len(t.pending)-t.opts.MaxPending:]
+	}
+	// After a failure, the batches are only sent again at the FlushInterval
+	full := len(t.pending) >= t.opts.BatchSize && !t.failing
+	t.mutex.Unlock()
+
+	if full {
+		select {
+		case t.flush <- struct{}{}:
+		default:
+		}
+	}
+
+}
+
+// run sends the pending entries at the FlushInterval and when a batch is full, so that a single batch is sent at once
+func (t *Telemetry) run() {
+
+	defer close(t.done)
+
+	ticker := time.NewTicker(t.opts.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-t.stop:
+			return
+		case <-ticker.C:
+			t.flushInBackground()
+		case <-t.flush:
+			t.flushInBackground()
+		}
+	}
+
+}
+
+// flushInBackground sends the pending entries, the errors are reported as warnings as there is no caller to return
+// them to
+func (t *Telemetry) flushInBackground() {
+	if err := t.Flush(); err != nil {
+		Warn("Failed to send the usage telemetry:", err)
+	}
+}
+
+func (t *Telemetry) send(batch []usageEntry) error {
+
+	if len(batch) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(batch)
+	if err != nil {
+		return err
+	}
+
+	resp, err := t.opts.Client.Post(t.opts.Endpoint, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	ioutil.ReadAll(resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("telemetry endpoint returned %s", resp.Status)
+	}
+
+	return nil
+
+}
@@ -0,0 +1,304 @@
+//go:build !tinygo
+// +build !tinygo
+
+package log_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+type usageBatch []struct {
+	Feature    string                 `json:"feature"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+type telemetryServer struct {
+	*httptest.Server
+	mutex   sync.Mutex
+	status  int
+	batches []usageBatch
+}
+
+func newTelemetryServer(t *testing.T, status int) *telemetryServer {
+	s := &telemetryServer{status: status}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var batch usageBatch
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&batch), "decode")
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"), "content-type")
+		s.mutex.Lock()
+		s.batches = append(s.batches, batch)
+		status := s.status
+		s.mutex.Unlock()
+		w.WriteHeader(status)
+	}))
+	return s
+}
+
+func (s *telemetryServer) setStatus(status int) {
+	s.mutex.Lock()
+	s.status = status
+	s.mutex.Unlock()
+}
+
+func (s *telemetryServer) received() []usageBatch {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]usageBatch{}, s.batches...)
+}
+
+func Test_Usage(t *testing.T) {
+
+	resetLogConfig()
+	stdout, stderr := redirectOutput()
+	defer resetLogOutput()
+
+	server := newTelemetryServer(t, http.StatusNoContent)
+	defer server.Close()
+
+	telemetry, err := log.NewTelemetry(log.TelemetryOptions{Endpoint: server.URL, FlushInterval: time.Hour})
+	assert.NoError(t, err, "new")
+	defer telemetry.Close()
+
+	log.SetTelemetry(telemetry)
+	defer log.SetTelemetry(nil)
+
+	log.Usage("export", map[string]interface{}{"format": "csv"})
+	assert.False(t, telemetry.Enabled(), "enabled-default")
+	assert.NoError(t, telemetry.Flush(), "flush-without-consent")
+	assert.Empty(t, server.received(), "without-consent")
+
+	assert.NoError(t, telemetry.SetConsent(true), "consent")
+	assert.True(t, telemetry.Enabled(), "enabled")
+
+	log.Usage("export", map[string]interface{}{"format": "csv"})
+	log.Usage("import", nil)
+	assert.NoError(t, telemetry.Flush(), "flush")
+
+	received := server.received()
+	if assert.Len(t, received, 1, "batches") && assert.Len(t, received[0], 2, "entries") {
+		assert.Equal(t, "export", received[0][0].Feature, "feature")
+		assert.Equal(t, map[string]interface{}{"format": "csv"}, received[0][0].Properties, "properties")
+		assert.Equal(t, "import", received[0][1].Feature, "feature-nil")
+		assert.Equal(t, map[string]interface{}{}, received[0][1].Properties, "properties-nil")
+	}
+
+	log.Usage("pending", nil)
+	assert.NoError(t, telemetry.SetConsent(false), "revoke")
+	assert.NoError(t, telemetry.Flush(), "flush-revoked")
+	assert.Len(t, server.received(), 1, "revoked")
+
+	assert.Empty(t, stdout.String(), "stdout")
+	assert.Empty(t, stderr.String(), "stderr")
+
+}
+
+func Test_Usage_BatchSize(t *testing.T) {
+
+	resetLogConfig()
+	redirectOutput()
+	defer resetLogOutput()
+
+	server := newTelemetryServer(t, http.StatusOK)
+	defer server.Close()
+
+	telemetry, err := log.NewTelemetry(log.TelemetryOptions{Endpoint: server.URL, BatchSize: 2, FlushInterval: time.Hour})
+	assert.NoError(t, err, "new")
+	defer telemetry.Close()
+	telemetry.SetConsent(true)
+
+	log.SetTelemetry(telemetry)
+	defer log.SetTelemetry(nil)
+
+	log.Usage("a", nil)
+	log.Usage("b", nil)
+
+	assert.Eventually(t, func() bool {
+		return len(server.received()) == 1
+	}, 2*time.Second, 10*time.Millisecond, "batch")
+
+}
+
+func Test_Usage_FlushInterval(t *testing.T) {
+
+	resetLogConfig()
+	redirectOutput()
+	defer resetLogOutput()
+
+	server := newTelemetryServer(t, http.StatusOK)
+	defer server.Close()
+
+	telemetry, err := log.NewTelemetry(log.TelemetryOptions{Endpoint: server.URL, FlushInterval: 10 * time.Millisecond})
+	assert.NoError(t, err, "new")
+	telemetry.SetConsent(true)
+
+	log.SetTelemetry(telemetry)
+	defer log.SetTelemetry(nil)
+
+	log.Usage("a", nil)
+
+	assert.Eventually(t, func() bool {
+		return len(server.received()) == 1
+	}, 2*time.Second, 10*time.Millisecond, "interval")
+
+	log.Usage("b", nil)
+	assert.NoError(t, telemetry.Close(), "close")
+	assert.Len(t, server.received(), 2, "close-flushes")
+
+	log.Usage("c", nil)
+	assert.NoError(t, telemetry.Close(), "close-twice")
+	assert.Len(t, server.received(), 2, "after-close")
+
+}
+
+func Test_Usage_DoNotTrack(t *testing.T) {
+
+	resetLogConfig()
+	redirectOutput()
+	defer resetLogOutput()
+
+	server := newTelemetryServer(t, http.StatusOK)
+	defer server.Close()
+
+	telemetry, err := log.NewTelemetry(log.TelemetryOptions{Endpoint: server.URL, FlushInterval: time.Hour})
+	assert.NoError(t, err, "new")
+	defer telemetry.Close()
+	telemetry.SetConsent(true)
+
+	log.SetTelemetry(telemetry)
+	defer log.SetTelemetry(nil)
+
+	os.Setenv("DO_NOT_TRACK", "1")
+	defer os.Unsetenv("DO_NOT_TRACK")
+
+	assert.False(t, telemetry.Enabled(), "enabled")
+	log.Usage("a", nil)
+	assert.NoError(t, telemetry.Flush(), "flush")
+	assert.Empty(t, server.received(), "received")
+
+}
+
+func Test_Telemetry_ConsentFile(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "go-log-telemetry")
+	assert.NoError(t, err, "tempdir")
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "config", "telemetry")
+
+	first, err := log.NewTelemetry(log.TelemetryOptions{Endpoint: "http://localhost", ConsentFile: path})
+	assert.NoError(t, err, "first")
+	assert.False(t, first.Enabled(), "first-default")
+	assert.NoError(t, first.SetConsent(true), "consent")
+	first.Close()
+
+	second, err := log.NewTelemetry(log.TelemetryOptions{Endpoint: "http://localhost", ConsentFile: path})
+	assert.NoError(t, err, "second")
+	assert.True(t, second.Enabled(), "second-remembered")
+	assert.NoError(t, second.SetConsent(false), "revoke")
+	second.Close()
+
+	third, err := log.NewTelemetry(log.TelemetryOptions{Endpoint: "http://localhost", ConsentFile: path})
+	assert.NoError(t, err, "third")
+	assert.False(t, third.Enabled(), "third-revoked")
+	third.Close()
+
+}
+
+func Test_Telemetry_Errors(t *testing.T) {
+
+	_, err := log.NewTelemetry(log.TelemetryOptions{})
+	assert.Equal(t, log.ErrNoTelemetryEndpoint, err, "endpoint")
+
+	resetLogConfig()
+	redirectOutput()
+	defer resetLogOutput()
+
+	server := newTelemetryServer(t, http.StatusInternalServerError)
+	defer server.Close()
+
+	telemetry, err := log.NewTelemetry(log.TelemetryOptions{Endpoint: server.URL, FlushInterval: time.Hour})
+	assert.NoError(t, err, "new")
+	defer telemetry.Close()
+	telemetry.SetConsent(true)
+
+	log.SetTelemetry(telemetry)
+	defer log.SetTelemetry(nil)
+
+	log.Usage("a", nil)
+	assert.EqualError(t, telemetry.Flush(), "telemetry endpoint returned 500 Internal Server Error", "status")
+
+}
+
+func Test_Telemetry_Retry(t *testing.T) {
+
+	resetLogConfig()
+	redirectOutput()
+	defer resetLogOutput()
+
+	stdout := &syncBuffer{}
+	log.Stdout = stdout
+
+	server := newTelemetryServer(t, http.StatusInternalServerError)
+	defer server.Close()
+
+	telemetry, err := log.NewTelemetry(log.TelemetryOptions{Endpoint: server.URL, BatchSize: 2, MaxPending: 3, FlushInterval: time.Hour})
+	assert.NoError(t, err, "new")
+	defer telemetry.Close()
+	telemetry.SetConsent(true)
+
+	log.SetTelemetry(telemetry)
+	defer log.SetTelemetry(nil)
+
+	log.Usage("a", nil)
+	log.Usage("b", nil)
+
+	assert.Eventually(t, func() bool {
+		return strings.Contains(stdout.String(), "Failed to send the usage telemetry")
+	}, 2*time.Second, 10*time.Millisecond, "failed")
+
+	for _, feature := range []string{"c", "d", "e"} {
+		log.Usage(feature, nil)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	assert.Len(t, server.received(), 1, "no-retry-until-interval")
+
+	server.setStatus(http.StatusOK)
+	assert.NoError(t, telemetry.Flush(), "flush")
+
+	received := server.received()
+	assert.Len(t, received, 2, "retried")
+
+	var features []string
+	for _, entry := range received[1] {
+		features = append(features, entry.Feature)
+	}
+	assert.Equal(t, []string{"c", "d", "e"}, features, "bounded")
+
+}
+
+func Test_Usage_WithoutTelemetry(t *testing.T) {
+
+	resetLogConfig()
+	stdout, stderr := redirectOutput()
+	defer resetLogOutput()
+
+	log.Usage("a", nil)
+
+	assert.Empty(t, stdout.String(), "stdout")
+	assert.Empty(t, stderr.String(), "stderr")
+
+}
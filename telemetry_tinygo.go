@@ -0,0 +1,13 @@
+//go:build tinygo
+// +build tinygo
+
+package log
+
+// Telemetry is not available when building with TinyGo, the usage entries are dropped instead
+type Telemetry struct{}
+
+func currentTelemetry() *Telemetry {
+	return nil
+}
+
+func (t *Telemetry) add(feature string, properties map[string]interface{}) {}
@@ -0,0 +1,76 @@
+package log
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TemplateField is the field which contains the raw template of a templated message
+const TemplateField = "msg.template"
+
+// DebugTemplate prints a debug message from a template with named placeholders
+//
+// Only shown if DebugMode is set to true
+func DebugTemplate(template string, fields map[string]interface{}) {
+	if DebugMode || isRecording() {
+		printTemplate("DEBUG", template, fields)
+	}
+}
+
+// InfoTemplate prints an info message from a template with named placeholders
+//
+// The placeholders (e.g. "user {user} logged in from {ip}") are filled from fields. The fields and the raw template
+// (as "msg.template") are added to the entry so that identical events can be grouped despite their variable data.
+func InfoTemplate(template string, fields map[string]interface{}) {
+	printTemplate("INFO", template, fields)
+}
+
+// WarnTemplate prints a warning message from a template with named placeholders
+func WarnTemplate(template string, fields map[string]interface{}) {
+	printTemplate("WARN", template, fields)
+}
+
+// ErrorTemplate prints an error message from a template with named placeholders
+func ErrorTemplate(template string, fields map[string]interface{}) {
+	printTemplate("ERROR", template, fields)
+}
+
+func printTemplate(level string, template string, fields map[string]interface{}) {
+	entryFields := make(map[string]interface{}, len(fields)+1)
+	for key, value := range fields {
+		entryFields[key] = value
+	}
+	entryFields[TemplateField] = template
+	printEntry(level, renderTemplate(template, fields), entryFields)
+}
+
+// renderTemplate replaces the {name} placeholders with the values of fields, unknown placeholders are kept as-is
+func renderTemplate(template string, fields map[string]interface{}) string {
+
+	var b strings.Builder
+
+	for {
+		start := strings.IndexByte(template, '{')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(template[start:], '}')
+		if end < 0 {
+			break
+		}
+		end += start
+
+		name := template[start+1 : end]
+		b.WriteString(template[:start])
+		if value, ok := fields[name]; ok {
+			b.WriteString(fmt.Sprint(value))
+		} else {
+			b.WriteString(template[start : end+1])
+		}
+		template = template[end+1:]
+	}
+
+	b.WriteString(template)
+	return b.String()
+
+}
@@ -0,0 +1,33 @@
+package log
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_renderTemplate(t *testing.T) {
+
+	type test struct {
+		name     string
+		template string
+		fields   map[string]interface{}
+		expected string
+	}
+
+	var tests = []test{
+		{"no-placeholders", "hello", nil, "hello"},
+		{"placeholders", "{a} and {b}", map[string]interface{}{"a": 1, "b": "two"}, "1 and two"},
+		{"unknown", "{a} and {c}", map[string]interface{}{"a": 1}, "1 and {c}"},
+		{"unclosed", "{a} and {b", map[string]interface{}{"a": 1, "b": 2}, "1 and {b"},
+		{"empty", "{}", nil, "{}"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := renderTemplate(tc.template, tc.fields)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+
+}
@@ -0,0 +1,62 @@
+package log_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func Test_InfoTemplate(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+
+	log.InfoTemplate("user {user} logged in from {ip}", map[string]interface{}{"user": "john", "ip": "10.0.0.1"})
+
+	expected := "test | INFO  | user john logged in from 10.0.0.1 ip=10.0.0.1 msg.template=\"user {user} logged in from {ip}\" user=john\n"
+	assert.Equal(t, expected, stdout.String())
+
+}
+
+func Test_Template_Levels(t *testing.T) {
+
+	resetLogConfig()
+	stdout, stderr := redirectOutput()
+	defer resetLogOutput()
+
+	log.DebugTemplate("hidden {x}", nil)
+	log.WarnTemplate("warn {x}", nil)
+	log.ErrorTemplate("error {x}", map[string]interface{}{"x": 1})
+
+	assert.Equal(t, "test | WARN  | warn {x} msg.template=\"warn {x}\"\n", stdout.String(), "stdout")
+	assert.Equal(t, "test | ERROR | error 1 msg.template=\"error {x}\" x=1\n", stderr.String(), "stderr")
+
+}
+
+func Test_Template_JSON(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+
+	log.Format = log.FormatDatadog
+	log.DebugMode = true
+	defer func() {
+		log.Format = log.FormatText
+	}()
+
+	log.DebugTemplate("job {id} done", map[string]interface{}{"id": 7})
+
+	var actual map[string]interface{}
+	err := json.Unmarshal(stdout.Bytes(), &actual)
+
+	assert.NoError(t, err, "json")
+	assert.Equal(t, "job 7 done", actual["message"], "message")
+	assert.Equal(t, "job {id} done", actual["msg.template"], "template")
+	assert.Equal(t, 7.0, actual["id"], "id")
+
+}
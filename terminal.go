@@ -0,0 +1,66 @@
+package log
+
+import (
+	"bytes"
+	"io"
+	"os"
+)
+
+// HighlightLevels maps the levels to the ANSI escape sequence used to highlight their entries when they are written
+// to a terminal (e.g. DefaultHighlightLevels())
+var HighlightLevels = map[string]string{}
+
+// BellLevels contains the levels which ring the terminal bell when they are written to a terminal, to notify
+// developers tailing the output during long local runs
+var BellLevels = map[string]bool{}
+
+// DefaultHighlightLevels returns a mapping which highlights warnings in yellow and errors in bold red
+func DefaultHighlightLevels() map[string]string {
+	return map[string]string{
+		"WARN":  "\x1b[33m",
+		"ERROR": "\x1b[1;31m",
+		"FATAL": "\x1b[1;41;97m",
+	}
+}
+
+const ansiReset = "\x1b[0m"
+
+// isTerminal is a variable so that it can be replaced during testing
+var isTerminal = func(w io.Writer) bool {
+	f, ok := unwrapWriter(w).(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+func highlightLine(line []byte, level string, w io.Writer) []byte {
+
+	style, highlight := HighlightLevels[level]
+	bell := BellLevels[level]
+	if (!highlight && !bell) || !isTerminal(w) {
+		return line
+	}
+
+	body := bytes.TrimRight(line, "\n")
+
+	var b bytes.Buffer
+	if highlight {
+		b.WriteString(style)
+		b.Write(body)
+		b.WriteString(ansiReset)
+	} else {
+		b.Write(body)
+	}
+	if bell {
+		b.WriteByte('\a')
+	}
+	b.WriteByte('\n')
+
+	return b.Bytes()
+
+}
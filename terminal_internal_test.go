@@ -0,0 +1,53 @@
+package log
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_highlightLine(t *testing.T) {
+
+	oldIsTerminal := isTerminal
+	defer func() {
+		isTerminal = oldIsTerminal
+		HighlightLevels = map[string]string{}
+		BellLevels = map[string]bool{}
+	}()
+
+	HighlightLevels = DefaultHighlightLevels()
+	BellLevels = map[string]bool{"ERROR": true, "INFO": true}
+
+	type test struct {
+		name     string
+		level    string
+		terminal bool
+		expected string
+	}
+
+	var tests = []test{
+		{"not-terminal", "ERROR", false, "message\n"},
+		{"plain-level", "DEBUG", true, "message\n"},
+		{"highlight", "WARN", true, "\x1b[33mmessage\x1b[0m\n"},
+		{"highlight-and-bell", "ERROR", true, "\x1b[1;31mmessage\x1b[0m\a\n"},
+		{"bell", "INFO", true, "message\a\n"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			isTerminal = func(w io.Writer) bool {
+				return tc.terminal
+			}
+			actual := highlightLine([]byte("message\n"), tc.level, &bytes.Buffer{})
+			assert.Equal(t, tc.expected, string(actual))
+		})
+	}
+
+}
+
+func Test_isTerminal(t *testing.T) {
+	assert.False(t, isTerminal(&bytes.Buffer{}), "buffer")
+	assert.False(t, isTerminal(WithTimestamp(&bytes.Buffer{}, true)), "wrapped-buffer")
+}
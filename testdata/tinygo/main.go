@@ -0,0 +1,12 @@
+// Command tinygo is built by Test_TinyGo_Build to check that the core of the package builds with TinyGo
+package main
+
+import (
+	"github.com/pieterclaerhout/go-log"
+)
+
+func main() {
+	log.PrintTimestamp = false
+	log.Info("hello from tinygo")
+	log.WithFields(map[string]interface{}{"answer": 42}).Warn("fields")
+}
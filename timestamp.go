@@ -0,0 +1,42 @@
+package log
+
+import (
+	"io"
+)
+
+// timestampWriter is implemented by writers which override PrintTimestamp
+type timestampWriter interface {
+	io.Writer
+	printTimestamp() bool
+}
+
+type timestampOverride struct {
+	io.Writer
+	enabled bool
+}
+
+func (w timestampOverride) printTimestamp() bool {
+	return w.enabled
+}
+
+// WithTimestamp wraps w so that the entries written to it include a timestamp (and level) or not, regardless of
+// PrintTimestamp
+//
+// This avoids duplicate time columns for outputs which add their own timestamps (such as journald or CloudWatch)
+// while keeping them for plain files.
+func WithTimestamp(w io.Writer, enabled bool) io.Writer {
+	if tw, ok := w.(timestampOverride); ok {
+		w = tw.Writer
+	}
+	return timestampOverride{Writer: w, enabled: enabled}
+}
+
+func timestampFor(w io.Writer, enabled bool) bool {
+	if a, ok := w.(*AsyncWriter); ok {
+		w = a.w
+	}
+	if tw, ok := w.(timestampWriter); ok {
+		return tw.printTimestamp()
+	}
+	return enabled
+}
@@ -0,0 +1,75 @@
+package log_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func Test_WithTimestamp(t *testing.T) {
+
+	type test struct {
+		name           string
+		printTimestamp bool
+		stdout         bool
+		stderr         bool
+		expectedStdout string
+		expectedStderr string
+	}
+
+	var tests = []test{
+		{"global-on-stdout-off", true, false, true, "info\n", "test | ERROR | error\n"},
+		{"global-off-stderr-on", false, false, true, "info\n", "test | ERROR | error\n"},
+		{"global-on-both-on", true, true, true, "test | INFO  | info\n", "test | ERROR | error\n"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			resetLogConfig()
+			defer resetLogOutput()
+
+			stdout := bytes.NewBufferString("")
+			stderr := bytes.NewBufferString("")
+
+			log.PrintTimestamp = tc.printTimestamp
+			log.Stdout = log.WithTimestamp(stdout, tc.stdout)
+			log.Stderr = log.WithTimestamp(log.WithTimestamp(stderr, false), tc.stderr)
+
+			log.Info("info")
+			log.Error("error")
+
+			assert.Equal(t, tc.expectedStdout, stdout.String(), "stdout")
+			assert.Equal(t, tc.expectedStderr, stderr.String(), "stderr")
+
+		})
+	}
+
+}
+
+func Test_WithTimestamp_Datadog(t *testing.T) {
+
+	resetLogConfig()
+	defer resetLogOutput()
+
+	log.Format = log.FormatDatadog
+	defer func() {
+		log.Format = log.FormatText
+	}()
+
+	stdout := bytes.NewBufferString("")
+	log.Stdout = log.WithTimestamp(stdout, false)
+
+	log.Info("info")
+
+	var actual map[string]interface{}
+	err := json.Unmarshal(stdout.Bytes(), &actual)
+
+	assert.NoError(t, err, "json")
+	assert.NotContains(t, actual, "timestamp", "timestamp")
+
+}
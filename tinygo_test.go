@@ -0,0 +1,44 @@
+package log_test
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_TinyGo_Dependencies(t *testing.T) {
+
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go is not installed")
+	}
+
+	output, err := exec.Command(goTool, "list", "-deps", "-tags", "tinygo", ".").CombinedOutput()
+	assert.NoError(t, err, string(output))
+
+	deps := strings.Fields(string(output))
+	for _, pkg := range []string{"net/http", "crypto/tls", "log/syslog", "os/exec"} {
+		assert.NotContains(t, deps, pkg, pkg)
+	}
+
+}
+
+func Test_TinyGo_Build(t *testing.T) {
+
+	tinygo, err := exec.LookPath("tinygo")
+	if err != nil {
+		t.Skip("tinygo is not installed")
+	}
+
+	dir, _ := ioutil.TempDir("", "go-log")
+	defer os.RemoveAll(dir)
+
+	output, err := exec.Command(tinygo, "build", "-o", filepath.Join(dir, "tinygo"), "./testdata/tinygo").CombinedOutput()
+	assert.NoError(t, err, string(output))
+
+}
@@ -0,0 +1,36 @@
+package log
+
+import (
+	"path"
+	"runtime"
+	"time"
+)
+
+// TraceFunc logs the entry of the calling function and returns a function which logs its exit and duration
+//
+// It should be used as:
+//
+//	defer log.TraceFunc()()
+//
+// Only shown if TraceMode is set to true
+func TraceFunc() func() {
+
+	if !TraceMode && !isRecording() {
+		return func() {}
+	}
+
+	name := "unknown"
+	if pc, _, _, ok := runtime.Caller(1); ok {
+		if fn := runtime.FuncForPC(pc); fn != nil {
+			_, name = path.Split(fn.Name())
+		}
+	}
+
+	Trace("->", name)
+	start := time.Now()
+
+	return func() {
+		Trace("<-", name, "("+time.Since(start).String()+")")
+	}
+
+}
@@ -0,0 +1,64 @@
+package log_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func tracedFunction() {
+	defer log.TraceFunc()()
+	log.Trace("inside")
+}
+
+func Test_Trace_Enabled(t *testing.T) {
+
+	resetLogConfig()
+	stdout, stderr := redirectOutput()
+	defer resetLogOutput()
+
+	log.TraceMode = true
+
+	log.Trace("trace")
+
+	assert.Equal(t, "test | TRACE | trace\n", stdout.String(), "stdout")
+	assert.Equal(t, "", stderr.String(), "stderr")
+
+}
+
+func Test_Trace_Disabled(t *testing.T) {
+
+	resetLogConfig()
+	stdout, stderr := redirectOutput()
+	defer resetLogOutput()
+
+	log.Trace("trace")
+	tracedFunction()
+
+	assert.Equal(t, "", stdout.String(), "stdout")
+	assert.Equal(t, "", stderr.String(), "stderr")
+
+}
+
+func Test_TraceFunc(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+
+	log.TraceMode = true
+
+	tracedFunction()
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+
+	assert.Len(t, lines, 3, "lines")
+	assert.Equal(t, "test | TRACE | -> go-log_test.tracedFunction", lines[0], "entry")
+	assert.Equal(t, "test | TRACE | inside", lines[1], "inside")
+	assert.True(t, strings.HasPrefix(lines[2], "test | TRACE | <- go-log_test.tracedFunction ("), "exit")
+	assert.True(t, strings.HasSuffix(lines[2], ")"), "exit-duration")
+
+}
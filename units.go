@@ -0,0 +1,79 @@
+package log
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// ByteSize is a size in bytes which is shown with binary units in text mode (e.g. "3.2 MiB")
+//
+// It stays a plain number in the JSON formats.
+type ByteSize int64
+
+// Count is a number which is shown with thousands separators in text mode (e.g. "1,204,001")
+//
+// It stays a plain number in the JSON formats.
+type Count int64
+
+var byteUnits = []string{"KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+
+// String returns the size using the largest binary unit in which it is at least 1
+func (s ByteSize) String() string {
+
+	if s < 1024 && s > -1024 {
+		return strconv.FormatInt(int64(s), 10) + " B"
+	}
+
+	value := float64(s) / 1024
+	unit := 0
+	for (value >= 1024 || value <= -1024) && unit < len(byteUnits)-1 {
+		value /= 1024
+		unit++
+	}
+
+	return fmt.Sprintf("%.1f %s", value, byteUnits[unit])
+
+}
+
+// String returns the count with a comma as thousands separator
+func (c Count) String() string {
+
+	digits := strconv.FormatInt(int64(c), 10)
+
+	sign := ""
+	if digits[0] == '-' {
+		sign, digits = "-", digits[1:]
+	}
+
+	result := make([]byte, 0, len(digits)+len(digits)/3)
+	for i := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			result = append(result, ',')
+		}
+		result = append(result, digits[i])
+	}
+
+	return sign + string(result)
+
+}
+
+// formatDuration rounds a duration to 3 significant digits (e.g. "1.24s")
+func formatDuration(d time.Duration) string {
+
+	precision := time.Duration(1)
+	for d/precision >= 1000 || d/precision <= -1000 {
+		precision *= 10
+	}
+
+	return d.Round(precision).String()
+
+}
+
+// formatValue formats a value of a field or argument for text mode
+func formatValue(value interface{}) string {
+	if d, ok := value.(time.Duration); ok {
+		return formatDuration(d)
+	}
+	return fmt.Sprint(value)
+}
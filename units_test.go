@@ -0,0 +1,102 @@
+package log_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func Test_ByteSize_String(t *testing.T) {
+
+	type test struct {
+		size     log.ByteSize
+		expected string
+	}
+
+	var tests = []test{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1024, "1.0 KiB"},
+		{3355443, "3.2 MiB"},
+		{5 << 30, "5.0 GiB"},
+		{-2048, "-2.0 KiB"},
+		{1 << 62, "4.0 EiB"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.expected, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.size.String())
+		})
+	}
+
+}
+
+func Test_Count_String(t *testing.T) {
+
+	type test struct {
+		count    log.Count
+		expected string
+	}
+
+	var tests = []test{
+		{0, "0"},
+		{999, "999"},
+		{1000, "1,000"},
+		{1204001, "1,204,001"},
+		{-1234567, "-1,234,567"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.expected, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.count.String())
+		})
+	}
+
+}
+
+func Test_Units_Text(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+
+	log.Info("took", 1234567890*time.Nanosecond, "for", log.ByteSize(3355443))
+	log.InfoTemplate("done", map[string]interface{}{
+		"duration": 1235 * time.Millisecond,
+		"rows":     log.Count(1204001),
+		"short":    999 * time.Microsecond,
+	})
+
+	assert.Equal(t, "test | INFO  | took 1.23s for 3.2 MiB\ntest | INFO  | done duration=1.24s msg.template=done rows=1,204,001 short=999µs\n", stdout.String())
+
+}
+
+func Test_Units_JSON(t *testing.T) {
+
+	resetLogConfig()
+	redirectOutput()
+	defer resetLogOutput()
+	defer resetAllowListConfig()
+
+	stdout := &bytes.Buffer{}
+	log.Stdout = stdout
+	log.Format = log.FormatDatadog
+
+	log.InfoTemplate("done", map[string]interface{}{
+		"duration": 1235 * time.Millisecond,
+		"size":     log.ByteSize(3355443),
+		"rows":     log.Count(1204001),
+	})
+
+	var values map[string]interface{}
+	assert.NoError(t, json.Unmarshal(stdout.Bytes(), &values), "json")
+	assert.Equal(t, float64(1235*time.Millisecond), values["duration"], "duration")
+	assert.Equal(t, float64(3355443), values["size"], "size")
+	assert.Equal(t, float64(1204001), values["rows"], "rows")
+
+}
@@ -0,0 +1,20 @@
+package log
+
+// Usage records that a feature was used, with optional properties, for the installed Telemetry
+//
+// The entries are only collected when a Telemetry is installed with SetTelemetry and the user gave their consent.
+// They are not written to the regular log outputs and are not filtered by DebugMode.
+func Usage(feature string, properties map[string]interface{}) {
+
+	t := currentTelemetry()
+	if t == nil {
+		return
+	}
+
+	if properties == nil {
+		properties = map[string]interface{}{}
+	}
+
+	t.add(feature, properties)
+
+}
@@ -0,0 +1,164 @@
+package log
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+)
+
+// ErrMissingEntries is returned when the sequence numbers of a log show that entries are missing
+var ErrMissingEntries = errors.New("missing entries")
+
+// VerifyOptions are the options used to verify a log with VerifyLog
+type VerifyOptions struct {
+	// Key is the SigningKey the log was written with (nil skips verifying the signatures)
+	Key []byte
+
+	// Sequence verifies that the sequence numbers of the entries are continuous, see SequenceNumbers
+	Sequence bool
+}
+
+// SignatureReport describes a verified log
+type SignatureReport struct {
+	// Entries is the number of verified entries
+	Entries int
+
+	// Restarts is the number of times the chain started over, which happens once per process writing to the log
+	Restarts int
+}
+
+var textSequence = regexp.MustCompile(` ` + SequenceField + `=(\d+)`)
+
+// VerifyLog verifies the signatures and sequence numbers of a log written with SigningKey and SequenceNumbers
+//
+// Entries which span multiple lines (such as stack traces) are verified as a whole. The returned error names the
+// line of the first entry which was tampered with or which follows missing entries.
+func VerifyLog(r io.Reader, opts VerifyOptions) (SignatureReport, error) {
+
+	report := SignatureReport{}
+
+	if opts.Key == nil && !opts.Sequence {
+		return report, errors.New("nothing to verify")
+	}
+
+	reader := bufio.NewReader(r)
+
+	var previous []byte
+	var previousSeq uint64
+	var entry bytes.Buffer
+	lineNumber, entryLine := 0, 1
+
+	for {
+
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+
+			lineNumber++
+			entry.Write(line)
+
+			content, sig, ok := entry.Bytes(), []byte(nil), true
+			if opts.Key != nil {
+				content, sig, ok = splitSignature(entry.Bytes())
+			}
+
+			var seq uint64
+			if ok && opts.Sequence {
+				seq, ok = sequenceOf(content)
+				if !ok && opts.Key != nil {
+					return report, fmt.Errorf("line %d: entry without sequence number", entryLine)
+				}
+			}
+
+			if ok {
+
+				restart := report.Entries == 0
+
+				if opts.Key != nil {
+					switch {
+					case hmac.Equal(sig, chainValue(opts.Key, previous, content)):
+					case previous != nil && hmac.Equal(sig, chainValue(opts.Key, nil, content)):
+						restart = true
+					default:
+						return report, fmt.Errorf("line %d: %v", entryLine, ErrSignatureMismatch)
+					}
+				}
+
+				if opts.Sequence {
+					switch {
+					case seq == previousSeq+1:
+					case seq == 1 && (opts.Key == nil || restart):
+						restart = true
+					default:
+						return report, fmt.Errorf("line %d: %v (expected %s %d, got %d)", entryLine, ErrMissingEntries, SequenceField, previousSeq+1, seq)
+					}
+				}
+
+				if restart {
+					report.Restarts++
+				}
+				report.Entries++
+
+				previous, previousSeq = sig, seq
+				entry.Reset()
+				entryLine = lineNumber + 1
+
+			}
+
+		}
+
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return report, err
+		}
+
+	}
+
+	if entry.Len() > 0 {
+		if opts.Key != nil {
+			return report, fmt.Errorf("line %d: unsigned entry", entryLine)
+		}
+		return report, fmt.Errorf("line %d: entry without sequence number", entryLine)
+	}
+
+	return report, nil
+
+}
+
+// sequenceOf returns the sequence number of the entry, written as text or as JSON
+func sequenceOf(content []byte) (uint64, bool) {
+
+	trimmed := bytes.TrimSpace(content)
+
+	if bytes.HasPrefix(trimmed, []byte("{")) {
+		var attributes map[string]interface{}
+		if err := json.Unmarshal(trimmed, &attributes); err == nil {
+			if seq, ok := attributes[SequenceField].(float64); ok && seq >= 1 {
+				return uint64(seq), true
+			}
+			return 0, false
+		}
+	}
+
+	// The fields follow the message, so the last match is the field even when the message contains "seq="
+	lines := bytes.Split(trimmed, []byte("\n"))
+	matches := textSequence.FindAllSubmatch(lines[len(lines)-1], -1)
+	if len(matches) == 0 {
+		return 0, false
+	}
+
+	seq, err := strconv.ParseUint(string(matches[len(matches)-1][1]), 10, 64)
+	if err != nil || seq == 0 {
+		return 0, false
+	}
+
+	return seq, true
+
+}
@@ -0,0 +1,121 @@
+package log_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func writeSequencedLog(format log.OutputFormat, key []byte) string {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+
+	log.ResetSequenceNumbers()
+	log.ResetSignatureChains()
+	log.SequenceNumbers = true
+	log.SigningKey = key
+	log.Format = format
+	log.Stderr = stdout
+
+	log.Info("first")
+	log.Error("multi\nline")
+	log.Info("the message seq=9 is not the field")
+	log.Info("last")
+
+	return stdout.String()
+
+}
+
+func Test_VerifyLog(t *testing.T) {
+
+	defer resetSigningConfig()
+	defer resetSequenceConfig()
+
+	type test struct {
+		name   string
+		format log.OutputFormat
+		key    []byte
+	}
+
+	var tests = []test{
+		{"text", log.FormatText, nil},
+		{"datadog", log.FormatDatadog, nil},
+		{"text-signed", log.FormatText, []byte("secret")},
+		{"datadog-signed", log.FormatDatadog, []byte("secret")},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			output := writeSequencedLog(tc.format, tc.key)
+
+			report, err := log.VerifyLog(strings.NewReader(output+output), log.VerifyOptions{Key: tc.key, Sequence: true})
+			assert.NoError(t, err, "verify")
+			assert.Equal(t, log.SignatureReport{Entries: 8, Restarts: 2}, report, "report")
+
+		})
+	}
+
+}
+
+func Test_VerifyLog_Missing(t *testing.T) {
+
+	defer resetSigningConfig()
+	defer resetSequenceConfig()
+
+	type test struct {
+		name     string
+		format   log.OutputFormat
+		key      []byte
+		expected string
+	}
+
+	var tests = []test{
+		{"text", log.FormatText, nil, "line 2: missing entries (expected seq 2, got 3)"},
+		{"datadog", log.FormatDatadog, nil, "line 2: missing entries (expected seq 2, got 3)"},
+		{"signed", log.FormatText, []byte("secret"), "line 2: signature mismatch"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			output := writeSequencedLog(tc.format, tc.key)
+
+			// Remove the second entry, which spans two lines in the text format
+			lines := strings.SplitAfter(output, "\n")
+			if tc.format == log.FormatText {
+				lines = append(lines[:1], lines[3:]...)
+			} else {
+				lines = append(lines[:1], lines[2:]...)
+			}
+
+			_, err := log.VerifyLog(strings.NewReader(strings.Join(lines, "")), log.VerifyOptions{Key: tc.key, Sequence: true})
+			if assert.Error(t, err, "error") {
+				assert.Equal(t, tc.expected, err.Error(), "message")
+			}
+
+		})
+	}
+
+}
+
+func Test_VerifyLog_Invalid(t *testing.T) {
+
+	_, err := log.VerifyLog(strings.NewReader(""), log.VerifyOptions{})
+	assert.EqualError(t, err, "nothing to verify", "options")
+
+	_, err = log.VerifyLog(strings.NewReader("test | INFO  | first seq=1\ntest | INFO  | unsequenced\n"), log.VerifyOptions{Sequence: true})
+	assert.EqualError(t, err, "line 2: entry without sequence number", "unsequenced")
+
+	_, err = log.VerifyLog(strings.NewReader("test | INFO  | first seq=1\ntest | INFO  | first seq=1\n"), log.VerifyOptions{Sequence: true})
+	assert.NoError(t, err, "restart")
+
+	_, err = log.VerifyLog(strings.NewReader("test | INFO  | first seq=2\n"), log.VerifyOptions{Sequence: true})
+	assert.EqualError(t, err, "line 1: missing entries (expected seq 1, got 2)", "first-missing")
+
+}
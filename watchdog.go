@@ -0,0 +1,129 @@
+package log
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Watchdog logs a warning with the stack of a worker which stopped calling Ping within its deadline
+type Watchdog struct {
+	name        string
+	deadline    time.Duration
+	mutex       sync.Mutex
+	lastPing    time.Time
+	goroutineID uint64
+	warned      bool
+	stop        chan struct{}
+	done        chan struct{}
+	once        sync.Once
+}
+
+// NewWatchdog starts a watchdog which expects Ping to be called at least once every deadline
+//
+// When the deadline is missed, a warning containing the stack of the goroutine which last called Ping is logged.
+// The warning is logged once per stall.
+func NewWatchdog(name string, deadline time.Duration) *Watchdog {
+
+	w := &Watchdog{
+		name:        name,
+		deadline:    deadline,
+		lastPing:    time.Now(),
+		goroutineID: GoroutineID(),
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+
+	interval := deadline / 4
+	if interval < time.Millisecond {
+		interval = time.Millisecond
+	}
+
+	go w.watch(interval)
+
+	return w
+
+}
+
+// Ping records that the worker calling it is still alive
+func (w *Watchdog) Ping() {
+	w.mutex.Lock()
+	w.lastPing = time.Now()
+	w.goroutineID = GoroutineID()
+	w.warned = false
+	w.mutex.Unlock()
+}
+
+// Stop stops the watchdog and waits until it no longer logs warnings
+func (w *Watchdog) Stop() {
+	w.once.Do(func() {
+		close(w.stop)
+	})
+	<-w.done
+}
+
+func (w *Watchdog) watch(interval time.Duration) {
+
+	defer close(w.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			w.check()
+		}
+	}
+
+}
+
+func (w *Watchdog) check() {
+
+	w.mutex.Lock()
+	elapsed := time.Since(w.lastPing)
+	if w.warned || elapsed < w.deadline {
+		w.mutex.Unlock()
+		return
+	}
+	w.warned = true
+	goroutineID := w.goroutineID
+	w.mutex.Unlock()
+
+	message := "Watchdog " + w.name + " missed its deadline of " + w.deadline.String() +
+		" (last ping " + elapsed.Round(time.Millisecond).String() + " ago)"
+
+	if stack := goroutineStack(goroutineID); stack != "" {
+		message += "\n" + stack
+	}
+
+	Warn(message)
+
+}
+
+func goroutineStack(id uint64) string {
+
+	buf := make([]byte, 1<<16)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+
+	header := []byte("goroutine " + strconv.FormatUint(id, 10) + " [")
+	for _, stack := range bytes.Split(buf, []byte("\n\n")) {
+		if bytes.HasPrefix(stack, header) {
+			return string(bytes.TrimSpace(stack))
+		}
+	}
+
+	return ""
+
+}
@@ -0,0 +1,84 @@
+package log_test
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+type syncBuffer struct {
+	mutex sync.Mutex
+	buf   strings.Builder
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buf.String()
+}
+
+func stalledWorker(w *log.Watchdog, release chan struct{}) {
+	w.Ping()
+	<-release
+}
+
+func Test_Watchdog_Stalled(t *testing.T) {
+
+	resetLogConfig()
+	redirectOutput()
+	defer resetLogOutput()
+
+	stdout := &syncBuffer{}
+	log.Stdout = stdout
+
+	w := log.NewWatchdog("worker", 20*time.Millisecond)
+	defer w.Stop()
+
+	release := make(chan struct{})
+	defer close(release)
+	go stalledWorker(w, release)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) && stdout.String() == "" {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	actual := stdout.String()
+	assert.True(t, strings.HasPrefix(actual, "test | WARN  | Watchdog worker missed its deadline of 20ms"), "warning")
+	assert.Contains(t, actual, "go-log_test.stalledWorker", "stack")
+	assert.Equal(t, 1, strings.Count(actual, "missed its deadline"), "once")
+
+}
+
+func Test_Watchdog_Alive(t *testing.T) {
+
+	resetLogConfig()
+	redirectOutput()
+	defer resetLogOutput()
+
+	stdout := &syncBuffer{}
+	log.Stdout = stdout
+
+	w := log.NewWatchdog("worker", 50*time.Millisecond)
+
+	for i := 0; i < 10; i++ {
+		w.Ping()
+		time.Sleep(5 * time.Millisecond)
+	}
+	w.Stop()
+	w.Stop()
+
+	assert.Equal(t, "", stdout.String())
+
+}
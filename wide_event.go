@@ -0,0 +1,90 @@
+package log
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// WideEvent accumulates fields during a unit of work (such as a request) and is written as a single entry when it
+// finishes, also known as a canonical log line
+type WideEvent struct {
+	name     string
+	start    time.Time
+	mutex    sync.Mutex
+	fields   map[string]interface{}
+	finished bool
+}
+
+type wideEventKey struct{}
+
+// NewWideEvent starts a wide event with the given name and returns a context carrying it
+func NewWideEvent(ctx context.Context, name string) (context.Context, *WideEvent) {
+	e := &WideEvent{
+		name:   name,
+		start:  time.Now(),
+		fields: map[string]interface{}{},
+	}
+	return context.WithValue(ctx, wideEventKey{}, e), e
+}
+
+// WideEventFromContext returns the wide event carried by ctx (or nil if there is none)
+func WideEventFromContext(ctx context.Context) *WideEvent {
+	e, _ := ctx.Value(wideEventKey{}).(*WideEvent)
+	return e
+}
+
+// AddWideField sets a field on the wide event carried by ctx
+//
+// When ctx doesn't carry a wide event, nothing happens
+func AddWideField(ctx context.Context, key string, value interface{}) {
+	WideEventFromContext(ctx).Set(key, value)
+}
+
+// Set sets a field on the wide event, replacing any previous value for the key
+func (e *WideEvent) Set(key string, value interface{}) {
+	if e == nil {
+		return
+	}
+	e.mutex.Lock()
+	e.fields[key] = value
+	e.mutex.Unlock()
+}
+
+// Fields returns a copy of the fields collected so far
+func (e *WideEvent) Fields() map[string]interface{} {
+	fields := map[string]interface{}{}
+	if e == nil {
+		return fields
+	}
+	e.mutex.Lock()
+	for key, value := range e.fields {
+		fields[key] = value
+	}
+	e.mutex.Unlock()
+	return fields
+}
+
+// Finish writes the wide event with all its fields and its duration (as "duration_ms") as a single event
+//
+// Calling Finish more than once only writes the event once
+func (e *WideEvent) Finish() {
+
+	if e == nil {
+		return
+	}
+
+	e.mutex.Lock()
+	if e.finished {
+		e.mutex.Unlock()
+		return
+	}
+	e.finished = true
+	e.mutex.Unlock()
+
+	fields := e.Fields()
+	fields["duration_ms"] = float64(time.Since(e.start).Microseconds()) / 1000
+
+	Event(e.name, fields)
+
+}
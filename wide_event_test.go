@@ -0,0 +1,62 @@
+package log_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func Test_WideEvent(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+
+	ctx, event := log.NewWideEvent(context.Background(), "http_request")
+
+	assert.Equal(t, event, log.WideEventFromContext(ctx), "from-context")
+
+	log.AddWideField(ctx, "user_id", "42")
+	log.AddWideField(ctx, "status", 200)
+	event.Set("status", 201)
+
+	assert.Equal(t, "", stdout.String(), "not-written-yet")
+
+	event.Finish()
+	event.Finish()
+
+	assert.Equal(t, 1, strings.Count(stdout.String(), "\n"), "lines")
+
+	var actual map[string]interface{}
+	err := json.Unmarshal(stdout.Bytes(), &actual)
+	assert.NoError(t, err, "json")
+	assert.Equal(t, "http_request", actual["event"], "event")
+
+	properties := actual["properties"].(map[string]interface{})
+	assert.Equal(t, "42", properties["user_id"], "user_id")
+	assert.Equal(t, 201.0, properties["status"], "status")
+	assert.Contains(t, properties, "duration_ms", "duration")
+
+}
+
+func Test_WideEvent_Missing(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+
+	ctx := context.Background()
+
+	log.AddWideField(ctx, "user_id", "42")
+	log.WideEventFromContext(ctx).Finish()
+
+	assert.Nil(t, log.WideEventFromContext(ctx), "event")
+	assert.Empty(t, log.WideEventFromContext(ctx).Fields(), "fields")
+	assert.Equal(t, "", stdout.String(), "stdout")
+
+}
@@ -0,0 +1,189 @@
+package log
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// WorkerPool instruments the jobs of a worker pool or queue
+//
+// Each job logs its start (as debug) and its end with the fields "pool", "job.id", "worker.id", "queue_wait" and
+// "duration". A summary of the throughput is logged periodically and when the pool is stopped.
+type WorkerPool struct {
+	name      string
+	lastJobID uint64
+	mutex     sync.Mutex
+	since     time.Time
+	completed uint64
+	failed    uint64
+	duration  time.Duration
+	wait      time.Duration
+	stop      chan struct{}
+	summaries sync.WaitGroup
+	once      sync.Once
+}
+
+// Job is a single unit of work of a WorkerPool
+type Job struct {
+	pool     *WorkerPool
+	id       uint64
+	enqueued time.Time
+	started  time.Time
+	worker   int
+}
+
+// NewWorkerPool creates the instrumentation for the named pool which logs a summary every summaryInterval (0
+// only logs the summary when the pool is stopped)
+func NewWorkerPool(name string, summaryInterval time.Duration) *WorkerPool {
+
+	p := &WorkerPool{
+		name:  name,
+		since: time.Now(),
+		stop:  make(chan struct{}),
+	}
+
+	if summaryInterval > 0 {
+		p.summaries.Add(1)
+		go p.summarize(summaryInterval)
+	}
+
+	return p
+
+}
+
+// Enqueue creates a job and records the time it was queued
+func (p *WorkerPool) Enqueue() *Job {
+	return &Job{
+		pool:     p,
+		id:       atomic.AddUint64(&p.lastJobID, 1),
+		enqueued: time.Now(),
+	}
+}
+
+// Run runs fn as a job which was queued at enqueued on the given worker and returns its error
+func (p *WorkerPool) Run(workerID int, enqueued time.Time, fn func() error) error {
+
+	job := p.Enqueue()
+	job.enqueued = enqueued
+	job.Start(workerID)
+
+	err := fn()
+	job.Finish(err)
+
+	return err
+
+}
+
+// Stop stops the periodic summary and logs the final summary once the periodic one is no longer logged
+func (p *WorkerPool) Stop() {
+	p.once.Do(func() {
+		close(p.stop)
+		p.summaries.Wait()
+		p.logSummary()
+	})
+}
+
+// Start records that the worker with the given ID started the job
+func (j *Job) Start(workerID int) {
+
+	j.started = time.Now()
+	j.worker = workerID
+
+	if DebugMode || isRecording() {
+		fields := j.fields()
+		fields["queue_wait"] = j.started.Sub(j.enqueued)
+		printEntry("DEBUG", "Job started", fields)
+	}
+
+}
+
+// Finish records the end of the job, a non-nil err marks the job as failed
+func (j *Job) Finish(err error) {
+
+	if j.started.IsZero() {
+		j.started = j.enqueued
+	}
+
+	duration := time.Since(j.started)
+	wait := j.started.Sub(j.enqueued)
+
+	j.pool.mutex.Lock()
+	if err != nil {
+		j.pool.failed++
+	} else {
+		j.pool.completed++
+	}
+	j.pool.duration += duration
+	j.pool.wait += wait
+	j.pool.mutex.Unlock()
+
+	fields := j.fields()
+	fields["queue_wait"] = wait
+	fields["duration"] = duration
+
+	if err != nil {
+		fields["error"] = err.Error()
+		printEntry("ERROR", "Job failed", fields)
+		return
+	}
+
+	printEntry("INFO", "Job finished", fields)
+
+}
+
+func (j *Job) fields() map[string]interface{} {
+	return map[string]interface{}{
+		"pool":      j.pool.name,
+		"job.id":    j.id,
+		"worker.id": j.worker,
+	}
+}
+
+func (p *WorkerPool) summarize(interval time.Duration) {
+
+	defer p.summaries.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			p.logSummary()
+		}
+	}
+
+}
+
+// logSummary logs the counters since the previous summary and resets them
+func (p *WorkerPool) logSummary() {
+
+	p.mutex.Lock()
+	now := time.Now()
+	elapsed := now.Sub(p.since)
+	completed, failed := p.completed, p.failed
+	duration, wait := p.duration, p.wait
+	p.since = now
+	p.completed, p.failed = 0, 0
+	p.duration, p.wait = 0, 0
+	p.mutex.Unlock()
+
+	fields := map[string]interface{}{
+		"pool":      p.name,
+		"completed": completed,
+		"failed":    failed,
+		"interval":  elapsed,
+	}
+
+	if jobs := completed + failed; jobs > 0 {
+		fields["throughput"] = float64(jobs) / elapsed.Seconds()
+		fields["avg_duration"] = duration / time.Duration(jobs)
+		fields["avg_queue_wait"] = wait / time.Duration(jobs)
+	}
+
+	printEntry("INFO", "Worker pool summary", fields)
+
+}
@@ -0,0 +1,63 @@
+package log_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func Test_WorkerPool(t *testing.T) {
+
+	resetLogConfig()
+	stdout, stderr := redirectOutput()
+	defer resetLogOutput()
+
+	log.DebugMode = true
+
+	pool := log.NewWorkerPool("mailer", 0)
+
+	job := pool.Enqueue()
+	job.Start(3)
+	job.Finish(nil)
+
+	err := pool.Run(4, time.Now(), func() error {
+		return errors.New("smtp down")
+	})
+	assert.Error(t, err, "run-error")
+
+	pool.Stop()
+	pool.Stop()
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+
+	assert.Len(t, lines, 4, "lines")
+	assert.Regexp(t, `^test \| DEBUG \| Job started job\.id=1 pool=mailer queue_wait=\S+ worker\.id=3$`, lines[0], "started")
+	assert.Regexp(t, `^test \| INFO  \| Job finished duration=\S+ job\.id=1 pool=mailer queue_wait=\S+ worker\.id=3$`, lines[1], "finished")
+	assert.Regexp(t, `^test \| DEBUG \| Job started job\.id=2 pool=mailer queue_wait=\S+ worker\.id=4$`, lines[2], "started-2")
+	assert.Regexp(t, `^test \| INFO  \| Worker pool summary avg_duration=\S+ avg_queue_wait=\S+ completed=1 failed=1 interval=\S+ pool=mailer throughput=\S+$`, lines[3], "summary")
+	assert.Regexp(t, `^test \| ERROR \| Job failed duration=\S+ error="smtp down" job\.id=2 pool=mailer queue_wait=\S+ worker\.id=4\n$`, stderr.String(), "failed")
+
+}
+
+func Test_WorkerPool_PeriodicSummary(t *testing.T) {
+
+	resetLogConfig()
+	redirectOutput()
+	defer resetLogOutput()
+
+	stdout := &syncBuffer{}
+	log.Stdout = stdout
+
+	pool := log.NewWorkerPool("indexer", 10*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	pool.Stop()
+
+	assert.Contains(t, stdout.String(), "test | INFO  | Worker pool summary completed=0 failed=0 interval=", "summary")
+	assert.True(t, strings.Count(stdout.String(), "Worker pool summary") >= 2, "periodic")
+
+}
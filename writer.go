@@ -0,0 +1,61 @@
+package log
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// lineWriter logs each line written to it as an entry with its level
+type lineWriter struct {
+	mutex  sync.Mutex
+	logger *Logger
+	level  Level
+	buf    []byte
+}
+
+// Writer returns a writer which logs each line written to it as an entry with the level
+//
+// It can be used as the output of a standard library logger (e.g. log.New(log.Writer(log.ErrorLevel), "", 0) as
+// the ErrorLog of a http.Server). Incomplete lines are kept until the rest of the line is written and entries logged
+// at FatalLevel don't exit the program.
+func Writer(level Level) io.Writer {
+	return std.Writer(level)
+}
+
+// Writer returns a writer which logs each line written to it as an entry with the level and the fields of the logger
+func (l *Logger) Writer(level Level) io.Writer {
+	return &lineWriter{logger: l, level: level}
+}
+
+// Write logs each complete line in p
+func (w *lineWriter) Write(p []byte) (int, error) {
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.buf = append(w.buf, p...)
+
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.log(string(bytes.TrimSuffix(w.buf[:i], []byte("\r"))))
+		w.buf = w.buf[i+1:]
+	}
+
+	if len(w.buf) == 0 {
+		w.buf = nil
+	}
+
+	return len(p), nil
+
+}
+
+func (w *lineWriter) log(line string) {
+	label := w.level.String()
+	if w.level > DebugLevel || w.logger.levelEnabled(label) || isRecording() {
+		w.logger.printEntry(label, line, nil)
+	}
+}
@@ -0,0 +1,67 @@
+package log_test
+
+import (
+	stdlog "log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pieterclaerhout/go-log"
+)
+
+func Test_Writer(t *testing.T) {
+
+	type test struct {
+		name           string
+		level          log.Level
+		debug          bool
+		input          []string
+		expectedStdout string
+		expectedStderr string
+	}
+
+	var tests = []test{
+		{"info", log.InfoLevel, false, []string{"first\nsecond\n"}, "test | INFO  | first\ntest | INFO  | second\n", ""},
+		{"error", log.ErrorLevel, false, []string{"failed\n"}, "", "test | ERROR | failed\n"},
+		{"partial", log.InfoLevel, false, []string{"par", "tial\r\nnext"}, "test | INFO  | partial\n", ""},
+		{"empty-line", log.InfoLevel, false, []string{"\n"}, "test | INFO  | \n", ""},
+		{"debug-disabled", log.DebugLevel, false, []string{"debug\n"}, "", ""},
+		{"debug-enabled", log.DebugLevel, true, []string{"debug\n"}, "test | DEBUG | debug\n", ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+
+			resetLogConfig()
+			stdout, stderr := redirectOutput()
+			defer resetLogOutput()
+
+			log.DebugMode = tc.debug
+
+			w := log.Writer(tc.level)
+			for _, input := range tc.input {
+				n, err := w.Write([]byte(input))
+				assert.NoError(t, err, "write")
+				assert.Equal(t, len(input), n, "n")
+			}
+
+			assert.Equal(t, tc.expectedStdout, stdout.String(), "stdout")
+			assert.Equal(t, tc.expectedStderr, stderr.String(), "stderr")
+
+		})
+	}
+
+}
+
+func Test_Writer_StandardLogger(t *testing.T) {
+
+	resetLogConfig()
+	stdout, _ := redirectOutput()
+	defer resetLogOutput()
+
+	logger := stdlog.New(log.WithField("component", "http").Writer(log.WarnLevel), "http: ", 0)
+	logger.Printf("TLS handshake error from %s", "127.0.0.1")
+
+	assert.Equal(t, "test | WARN  | http: TLS handshake error from 127.0.0.1 component=http\n", stdout.String(), "stdout")
+
+}